@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/infrastructure/database"
+	dbService "github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+)
+
+func main() {
+	promote := flag.String("promote", "", "Grant the admin role to the account with this email")
+	flag.Parse()
+
+	if *promote == "" {
+		log.Fatalln("FATAL -> failed to run admin command: no action specified (use -promote EMAIL)")
+	}
+
+	cfg, err := configs.Load("./configs")
+	if err != nil {
+		log.Fatalln("FATAL ->", err.Error())
+	}
+
+	db, err := database.NewConnection(&cfg.Database)
+	if err != nil {
+		log.Fatalln("FATAL ->", err.Error())
+	}
+	defer db.Close()
+
+	dbs := dbService.NewDatabase(db)
+	tx := dbService.NewTransactor(db)
+
+	ar := repositories.NewAuthRepository(dbs)
+	aar := repositories.NewAdminAuditRepository(dbs)
+
+	// the first admin cannot be promoted through the api, so this is recorded without an actor
+	err = tx.WithTx(context.Background(), func(ctx context.Context) error {
+		authID, err := ar.SetRole(ctx, utils.Normalize(*promote), constants.RoleAdmin)
+		if err != nil {
+			return err
+		}
+
+		data := entities.CreateAdminAudit{
+			TargetID:  &authID,
+			Action:    constants.AdminActionAdminPromoted,
+			UserAgent: "cmd/admin",
+			IPAddress: "127.0.0.1",
+		}
+		return aar.Create(ctx, nil, &data)
+	})
+	if err != nil {
+		log.Fatalln("FATAL ->", err.Error())
+	}
+
+	log.Printf("%s is now an admin\n", *promote)
+}
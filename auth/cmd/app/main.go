@@ -26,12 +26,22 @@ func main() {
 	}
 	defer infra.Close()
 
-	c := di.NewContainer(cfg, infra)
+	c, err := di.NewContainer(cfg, infra)
+	if err != nil {
+		log.Fatalln("FATAL ->", err.Error())
+	}
 
 	if err := validator.RegisterValidators(); err != nil {
 		log.Fatalln("FATAL ->", err.Error())
 	}
 
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	for _, w := range c.Workers() {
+		go w.Run(workerCtx)
+	}
+
 	s := servers.NewHTTPServer(cfg, c.Router().Engine())
 	go func() {
 		if err := s.Start(); err != nil {
@@ -44,6 +54,8 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
+	stopWorkers()
+
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.Timeout.Shutdown)
 	defer cancel()
 
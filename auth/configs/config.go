@@ -9,15 +9,16 @@ import (
 )
 
 type Config struct {
-	App      `mapstructure:"app"`
-	Auth     `mapstructure:"auth"`
-	OAuth    `mapstructure:"oauth"`
-	Server   `mapstructure:"server"`
-	Client   `mapstructure:"client"`
-	Database `mapstructure:"database"`
-	Cache    `mapstructure:"cache"`
-	Tracer   `mapstructure:"tracer"`
-	Broker   `mapstructure:"broker"`
+	App       `mapstructure:"app"`
+	Auth      `mapstructure:"auth"`
+	OAuth     `mapstructure:"oauth"`
+	RateLimit `mapstructure:"rate_limit"`
+	Server    `mapstructure:"server"`
+	Client    `mapstructure:"client"`
+	Database  `mapstructure:"database"`
+	Cache     `mapstructure:"cache"`
+	Tracer    `mapstructure:"tracer"`
+	Broker    `mapstructure:"broker"`
 }
 
 type App struct {
@@ -26,43 +27,181 @@ type App struct {
 }
 
 type Auth struct {
+	Hasher struct {
+		Algorithm string `mapstructure:"algorithm"`
+	} `mapstructure:"hasher"`
+
 	BCrypt struct {
 		Cost int `mapstructure:"cost"`
 	} `mapstructure:"bcrypt"`
 
+	Argon2ID struct {
+		Memory      uint32 `mapstructure:"memory"`
+		Iterations  uint32 `mapstructure:"iterations"`
+		Parallelism uint8  `mapstructure:"parallelism"`
+		SaltLength  uint32 `mapstructure:"salt_length"`
+		KeyLength   uint32 `mapstructure:"key_length"`
+	} `mapstructure:"argon2id"`
+
 	JWT struct {
-		Issuer    string        `mapstructure:"issuer"`
-		Audiences []string      `mapstructure:"audiences"`
-		Secret    string        `mapstructure:"secret"`
-		Duration  time.Duration `mapstructure:"duration"`
+		Issuer        string        `mapstructure:"issuer"`
+		Audiences     []string      `mapstructure:"audiences"`
+		Duration      time.Duration `mapstructure:"duration"`
+		Algorithm     string        `mapstructure:"algorithm"`
+		KeyStore      string        `mapstructure:"key_store"`
+		KeyDir        string        `mapstructure:"key_dir"`
+		EncryptionKey string        `mapstructure:"encryption_key"`
+		Rotation      time.Duration `mapstructure:"rotation"`
+		Overlap       time.Duration `mapstructure:"overlap"`
+		Refresh       time.Duration `mapstructure:"refresh"`
 	} `mapstructure:"jwt"`
 
+	MFA struct {
+		Issuer        string `mapstructure:"issuer"`
+		EncryptionKey string `mapstructure:"encryption_key"`
+		RecoveryCodes int    `mapstructure:"recovery_codes"`
+		MaxAttempts   int    `mapstructure:"max_attempts"`
+	} `mapstructure:"mfa"`
+
+	OTP struct {
+		MaxAttempts int `mapstructure:"max_attempts"`
+	} `mapstructure:"otp"`
+
+	WebAuthn struct {
+		RPID      string   `mapstructure:"rp_id"`
+		RPName    string   `mapstructure:"rp_name"`
+		RPOrigins []string `mapstructure:"rp_origins"`
+	} `mapstructure:"webauthn"`
+
+	Session struct {
+		MaxActive  int           `mapstructure:"max_active"`
+		ReuseGrace time.Duration `mapstructure:"reuse_grace"`
+	} `mapstructure:"session"`
+
+	Denylist struct {
+		LocalTTL        time.Duration `mapstructure:"local_ttl"`
+		LocalMaxEntries int           `mapstructure:"local_max_entries"`
+	} `mapstructure:"denylist"`
+
+	Lockout struct {
+		MaxAttempts   int           `mapstructure:"max_attempts"`
+		IPMaxAttempts int           `mapstructure:"ip_max_attempts"`
+		Window        time.Duration `mapstructure:"window"`
+		Duration      time.Duration `mapstructure:"duration"`
+		BaseDelay     time.Duration `mapstructure:"base_delay"`
+		MaxDelay      time.Duration `mapstructure:"max_delay"`
+	} `mapstructure:"lockout"`
+
+	Deletion struct {
+		CoolingOff time.Duration `mapstructure:"cooling_off"`
+		Interval   time.Duration `mapstructure:"interval"`
+		BatchSize  int           `mapstructure:"batch_size"`
+	} `mapstructure:"deletion"`
+
+	Admin struct {
+		PageSize              int           `mapstructure:"page_size"`
+		MaxPageSize           int           `mapstructure:"max_page_size"`
+		ImpersonationDuration time.Duration `mapstructure:"impersonation_duration"`
+	} `mapstructure:"admin"`
+
+	SignInAlert struct {
+		GeoIPFile         string  `mapstructure:"geoip_file"`
+		HistorySize       int     `mapstructure:"history_size"`
+		MaxTravelSpeed    float64 `mapstructure:"max_travel_speed"`
+		MinTravelDistance float64 `mapstructure:"min_travel_distance"`
+	} `mapstructure:"sign_in_alert"`
+
+	ServiceAccount struct {
+		TokenDuration     time.Duration `mapstructure:"token_duration"`
+		SecretGracePeriod time.Duration `mapstructure:"secret_grace_period"`
+		Interval          time.Duration `mapstructure:"interval"`
+	} `mapstructure:"service_account"`
+
+	SecurityEvents struct {
+		PageSize    int `mapstructure:"page_size"`
+		MaxPageSize int `mapstructure:"max_page_size"`
+	} `mapstructure:"security_events"`
+
+	Pharmacy struct {
+		RequireApproval bool `mapstructure:"require_approval"`
+	} `mapstructure:"pharmacy"`
+
+	PasswordPolicy struct {
+		HistorySize int `mapstructure:"history_size"`
+
+		Breach struct {
+			Source  string        `mapstructure:"source"`
+			APIURL  string        `mapstructure:"api_url"`
+			File    string        `mapstructure:"file"`
+			Timeout time.Duration `mapstructure:"timeout"`
+		} `mapstructure:"breach"`
+	} `mapstructure:"password_policy"`
+
 	TokenDuration struct {
 		Session      time.Duration `mapstructure:"session"`
 		Reset        time.Duration `mapstructure:"reset"`
 		Verification time.Duration `mapstructure:"verification"`
 		EmailChange  time.Duration `mapstructure:"email_change"`
+		MFAPending   time.Duration `mapstructure:"mfa_pending"`
+		WebAuthn     time.Duration `mapstructure:"webauthn"`
+		Unlock       time.Duration `mapstructure:"unlock"`
+		MagicLink    time.Duration `mapstructure:"magic_link"`
+		OTP          time.Duration `mapstructure:"otp"`
+		SignInReport time.Duration `mapstructure:"sign_in_report"`
 	} `mapstructure:"token_duration"`
 }
 
 type OAuth struct {
-	Google struct {
-		ClientID    string `mapstructure:"client_id"`
-		Secret      string `mapstructure:"secret"`
-		RedirectURL string `mapstructure:"redirect_url"`
-	} `mapstructure:"google"`
-
-	Microsoft struct {
-		ClientID    string `mapstructure:"client_id"`
-		Secret      string `mapstructure:"secret"`
-		RedirectURL string `mapstructure:"redirect_url"`
-	} `mapstructure:"microsoft"`
+	Providers map[string]OAuthProvider `mapstructure:"providers"`
+
+	RedirectAllowlist []string `mapstructure:"redirect_allowlist"`
 
 	Duration struct {
 		CodeExchange time.Duration `mapstructure:"code_exchange"`
+		State        time.Duration `mapstructure:"state"`
 	} `mapstructure:"duration"`
 }
 
+type OAuthProvider struct {
+	ID           int16    `mapstructure:"id"`
+	DiscoveryURL string   `mapstructure:"discovery_url"`
+	ClientID     string   `mapstructure:"client_id"`
+	Secret       string   `mapstructure:"secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+	TrustEmail   bool     `mapstructure:"trust_email"`
+
+	Claims struct {
+		Subject       string `mapstructure:"subject"`
+		Email         string `mapstructure:"email"`
+		EmailVerified string `mapstructure:"email_verified"`
+	} `mapstructure:"claims"`
+}
+
+type RateLimit struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	Register           RateLimitRule `mapstructure:"register"`
+	Login              RateLimitRule `mapstructure:"login"`
+	ForgotPassword     RateLimitRule `mapstructure:"forgot_password"`
+	ResendVerification RateLimitRule `mapstructure:"resend_verification"`
+	EmailAvailable     RateLimitRule `mapstructure:"email_available"`
+	ValidateResetToken RateLimitRule `mapstructure:"validate_reset_token"`
+	MagicLink          RateLimitRule `mapstructure:"magic_link"`
+	LoginCode          RateLimitRule `mapstructure:"login_code"`
+	ClientToken        RateLimitRule `mapstructure:"client_token"`
+	ResetPassword      RateLimitRule `mapstructure:"reset_password"`
+	Unlock             RateLimitRule `mapstructure:"unlock"`
+	MagicLinkConfirm   RateLimitRule `mapstructure:"magic_link_confirm"`
+	SignInReport       RateLimitRule `mapstructure:"sign_in_report"`
+}
+
+type RateLimitRule struct {
+	IPLimit      int           `mapstructure:"ip_limit"`
+	AccountLimit int           `mapstructure:"account_limit"`
+	Window       time.Duration `mapstructure:"window"`
+}
+
 type Server struct {
 	Host string `mapstructure:"host"`
 	Port int    `mapstructure:"port"`
@@ -109,6 +248,16 @@ type Broker struct {
 	Timeout struct {
 		Batch time.Duration `mapstructure:"batch"`
 	} `mapstructure:"timeout"`
+
+	Outbox struct {
+		Interval      time.Duration `mapstructure:"interval"`
+		BatchSize     int           `mapstructure:"batch_size"`
+		ClaimDuration time.Duration `mapstructure:"claim_duration"`
+		BaseDelay     time.Duration `mapstructure:"base_delay"`
+		MaxDelay      time.Duration `mapstructure:"max_delay"`
+		MaxAttempts   int           `mapstructure:"max_attempts"`
+		Retention     time.Duration `mapstructure:"retention"`
+	} `mapstructure:"outbox"`
 }
 
 func Load(path string) (*Config, error) {
@@ -19,6 +19,7 @@ const authErrorTracer string = "cache.auth"
 type AuthCache interface {
 	CreateResetToken(ctx context.Context, authID int64, token string, duration time.Duration) (err error)
 	UseResetToken(ctx context.Context, token string) (authID int64, err error)
+	GetResetToken(ctx context.Context, token string) (authID int64, err error)
 	CreateVerificationToken(ctx context.Context, authID int64, token string, duration time.Duration) (err error)
 	UseVerificationToken(ctx context.Context, token string) (authID int64, err error)
 	CreateEmailChangeToken(ctx context.Context, authID int64, newEmail, token string, duration time.Duration) (err error)
@@ -26,6 +27,13 @@ type AuthCache interface {
 	UnreserveEmail(ctx context.Context, email string) (err error)
 	ResetTokenExists(ctx context.Context, token string) (exists bool, err error)
 	IsEmailReserved(ctx context.Context, email string) (exists bool, err error)
+	CreateMagicLinkToken(ctx context.Context, authID int64, token, deviceHash string, duration time.Duration) (err error)
+	UseMagicLinkToken(ctx context.Context, token, deviceHash string) (authID int64, err error)
+	CreateOneTimeCode(ctx context.Context, purpose, subject string, authID int64, payload, codeHash string, duration time.Duration) (err error)
+	UseOneTimeCode(ctx context.Context, purpose, subject, codeHash string, maxAttempts int) (authID int64, payload string, err error)
+	CreateSignInReportToken(ctx context.Context, authID, sessionID int64, token string, duration time.Duration) (err error)
+	GetSignInReportToken(ctx context.Context, token string) (authID, sessionID int64, err error)
+	UseSignInReportToken(ctx context.Context, token string) (authID, sessionID int64, err error)
 }
 
 type authCache struct {
@@ -137,18 +145,24 @@ func (c *authCache) UseVerificationToken(ctx context.Context, token string) (int
 	defer span.End()
 
 	tokenKey := fmt.Sprintf("%s:%s", constants.CachePrefixVerification, token)
+	codePrefix := fmt.Sprintf("%s:%s", constants.CachePrefixOTP, constants.OTPPurposeVerification)
 
+	// the code sent along with the link is retired too
 	script := `
 		local authID = redis.call("GET", KEYS[1])
 		if authID then
 			redis.call("DEL", KEYS[1])
 			redis.call("DEL", KEYS[2] .. ":" .. authID)
+			redis.call("DEL", KEYS[3] .. ":" .. authID)
 			return authID
 		end
 		return nil
 	`
 
-	result, err := c.cache.Evaluate(ctx, "hs:uvt", script, []string{tokenKey, constants.CachePrefixVerification})
+	result, err := c.cache.Evaluate(
+		ctx, "hs:uvt", script,
+		[]string{tokenKey, constants.CachePrefixVerification, codePrefix},
+	)
 	if err != nil {
 		wErr := fmt.Errorf("failed to use verification token: %w", err)
 		if errors.Is(err, ce.ErrCacheNil) {
@@ -214,8 +228,10 @@ func (c *authCache) UseEmailChangeToken(ctx context.Context, token string) (int6
 	defer span.End()
 
 	tokenKey := fmt.Sprintf("%s:%s", constants.CachePrefixEmailChange, token)
+	codePrefix := fmt.Sprintf("%s:%s", constants.CachePrefixOTP, constants.OTPPurposeEmailChange)
 
 	// id: authID, ne: newEmail
+	// the code sent along with the link is retired too
 	script := `
 		local data = redis.call("HMGET", KEYS[1], "id", "ne")
 		if data and data[1] then
@@ -223,12 +239,16 @@ func (c *authCache) UseEmailChangeToken(ctx context.Context, token string) (int6
 			local email = data[2]
 			redis.call("DEL", KEYS[1])
 			redis.call("DEL", KEYS[2] .. ":" .. authID)
+			redis.call("DEL", KEYS[3] .. ":" .. authID)
 			return {authID, email}
 		end
 		return nil
 	`
 
-	result, err := c.cache.Evaluate(ctx, "hs:uect", script, []string{tokenKey, constants.CachePrefixEmailChange})
+	result, err := c.cache.Evaluate(
+		ctx, "hs:uect", script,
+		[]string{tokenKey, constants.CachePrefixEmailChange, codePrefix},
+	)
 	if err != nil {
 		wErr := fmt.Errorf("failed to use email change token: %w", err)
 		if errors.Is(err, ce.ErrCacheNil) {
@@ -273,6 +293,35 @@ func (c *authCache) UnreserveEmail(ctx context.Context, email string) error {
 	return nil
 }
 
+func (c *authCache) GetResetToken(ctx context.Context, token string) (int64, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "GetResetToken")
+	defer span.End()
+
+	tokenKey := fmt.Sprintf("%s:%s", constants.CachePrefixReset, token)
+
+	// unlike UseResetToken, the token stays usable
+	script := `
+		return redis.call("GET", KEYS[1])
+	`
+
+	result, err := c.cache.Evaluate(ctx, "hs:grt", script, []string{tokenKey})
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch reset token: %w", err)
+		if errors.Is(err, ce.ErrCacheNil) {
+			return 0, ce.NewError(span, ce.CodeCacheValueNotFound, ce.MsgInvalidToken, wErr)
+		}
+		return 0, ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	authID, err := utils.ToInt64Any(result)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch reset token: %w", err)
+		return 0, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	return authID, nil
+}
+
 func (c *authCache) ResetTokenExists(ctx context.Context, token string) (bool, error) {
 	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "ResetTokenExists")
 	defer span.End()
@@ -302,3 +351,309 @@ func (c *authCache) IsEmailReserved(ctx context.Context, email string) (bool, er
 
 	return exists, nil
 }
+
+func (c *authCache) CreateMagicLinkToken(ctx context.Context, authID int64, token, deviceHash string, duration time.Duration) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "CreateMagicLinkToken")
+	defer span.End()
+
+	authKey := fmt.Sprintf("%s:%d", constants.CachePrefixMagicLink, authID)
+	tokenKey := fmt.Sprintf("%s:%s", constants.CachePrefixMagicLink, token)
+
+	// id: authID, dv: deviceHash
+	// a new link replaces the one still outstanding
+	script := `
+		local token = redis.call("GET", KEYS[1])
+		if token then
+			redis.call("DEL", KEYS[1])
+			redis.call("DEL", KEYS[3] .. ":" .. token)
+		end
+		redis.call("SET", KEYS[1], ARGV[1], "EX", ARGV[4])
+		redis.call("HSET", KEYS[2], "id", ARGV[2], "dv", ARGV[3])
+		redis.call("EXPIRE", KEYS[2], ARGV[4])
+		return 1
+	`
+
+	_, err := c.cache.Evaluate(
+		ctx, "hs:cmlt", script,
+		[]string{authKey, tokenKey, constants.CachePrefixMagicLink},
+		token, strconv.FormatInt(authID, 10), deviceHash, int(duration.Seconds()),
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to create magic link token: %w", err)
+		return ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *authCache) UseMagicLinkToken(ctx context.Context, token, deviceHash string) (int64, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "UseMagicLinkToken")
+	defer span.End()
+
+	tokenKey := fmt.Sprintf("%s:%s", constants.CachePrefixMagicLink, token)
+
+	// id: authID, dv: deviceHash
+	// opened on another device, the link is left intact for the one that requested it
+	script := `
+		local data = redis.call("HMGET", KEYS[1], "id", "dv")
+		if not data[1] then
+			return nil
+		end
+		if data[2] ~= ARGV[1] then
+			return "0"
+		end
+		redis.call("DEL", KEYS[1])
+		redis.call("DEL", KEYS[2] .. ":" .. data[1])
+		return data[1]
+	`
+
+	result, err := c.cache.Evaluate(
+		ctx, "hs:umlt", script,
+		[]string{tokenKey, constants.CachePrefixMagicLink},
+		deviceHash,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to use magic link token: %w", err)
+		if errors.Is(err, ce.ErrCacheNil) {
+			return 0, ce.NewError(span, ce.CodeCacheValueNotFound, ce.MsgInvalidToken, wErr)
+		}
+		return 0, ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	authID, err := utils.ToInt64Any(result)
+	if err != nil {
+		wErr := fmt.Errorf("failed to use magic link token: %w", err)
+		return 0, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+	if authID == 0 {
+		err := fmt.Errorf("failed to use magic link token: %w", errors.New("device mismatch"))
+		return 0, ce.NewError(span, ce.CodeMagicLinkDeviceMismatch, ce.MsgMagicLinkDeviceMismatch, err)
+	}
+
+	return authID, nil
+}
+
+func (c *authCache) CreateOneTimeCode(ctx context.Context, purpose, subject string, authID int64, payload, codeHash string, duration time.Duration) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "CreateOneTimeCode")
+	defer span.End()
+
+	codeKey := fmt.Sprintf("%s:%s:%s", constants.CachePrefixOTP, purpose, subject)
+
+	// ch: codeHash, id: authID, pl: payload, at: attempts
+	// a new code replaces the one still outstanding
+	script := `
+		redis.call("DEL", KEYS[1])
+		redis.call("HSET", KEYS[1], "ch", ARGV[1], "id", ARGV[2], "pl", ARGV[3], "at", 0)
+		redis.call("EXPIRE", KEYS[1], ARGV[4])
+		return 1
+	`
+
+	_, err := c.cache.Evaluate(
+		ctx, "hs:cotc", script, []string{codeKey},
+		codeHash, strconv.FormatInt(authID, 10), payload, int(duration.Seconds()),
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to create one-time code: %w", err)
+		return ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *authCache) UseOneTimeCode(ctx context.Context, purpose, subject, codeHash string, maxAttempts int) (int64, string, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "UseOneTimeCode")
+	defer span.End()
+
+	codeKey := fmt.Sprintf("%s:%s:%s", constants.CachePrefixOTP, purpose, subject)
+	keys := []string{codeKey}
+
+	// codes sent along with a link retire that link too
+	switch purpose {
+	case constants.OTPPurposeVerification:
+		keys = append(keys, constants.CachePrefixVerification)
+	case constants.OTPPurposeEmailChange:
+		keys = append(keys, constants.CachePrefixEmailChange)
+	}
+
+	// ch: codeHash, id: authID, pl: payload, at: attempts
+	// a wrong code counts as an attempt, the code is discarded once attempts are exhausted
+	script := `
+		local data = redis.call("HMGET", KEYS[1], "ch", "id", "pl")
+		if not data[1] then
+			return nil
+		end
+		if data[1] ~= ARGV[1] then
+			local attempts = redis.call("HINCRBY", KEYS[1], "at", 1)
+			if attempts >= tonumber(ARGV[2]) then
+				redis.call("DEL", KEYS[1])
+				return {"-1", ""}
+			end
+			return {"0", ""}
+		end
+		redis.call("DEL", KEYS[1])
+		if KEYS[2] then
+			local token = redis.call("GET", KEYS[2] .. ":" .. data[2])
+			if token then
+				redis.call("DEL", KEYS[2] .. ":" .. data[2])
+				redis.call("DEL", KEYS[2] .. ":" .. token)
+			end
+		end
+		return {data[2], data[3]}
+	`
+
+	result, err := c.cache.Evaluate(ctx, "hs:uotc", script, keys, codeHash, maxAttempts)
+	if err != nil {
+		wErr := fmt.Errorf("failed to use one-time code: %w", err)
+		if errors.Is(err, ce.ErrCacheNil) {
+			return 0, "", ce.NewError(span, ce.CodeOTPInvalid, ce.MsgInvalidOTP, wErr)
+		}
+		return 0, "", ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		err := fmt.Errorf("failed to use one-time code: %w", ce.ErrTypeAssertionFailed)
+		return 0, "", ce.NewError(span, ce.CodeTypeAssertionFailed, ce.MsgInternalServer, err)
+	}
+
+	authStr, ok1 := values[0].(string)
+	payload, ok2 := values[1].(string)
+	if !ok1 || !ok2 {
+		err := fmt.Errorf("failed to use one-time code: %w", ce.ErrTypeAssertionFailed)
+		return 0, "", ce.NewError(span, ce.CodeTypeAssertionFailed, ce.MsgInternalServer, err)
+	}
+
+	authID, err := utils.ToInt64(authStr)
+	if err != nil {
+		wErr := fmt.Errorf("failed to use one-time code: %w", err)
+		return 0, "", ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	switch authID {
+	case -1:
+		err := fmt.Errorf("failed to use one-time code: %w", errors.New("attempts exhausted"))
+		return 0, "", ce.NewError(span, ce.CodeOTPAttemptsExceeded, "Too many wrong codes, please request a new one", err)
+	case 0:
+		err := fmt.Errorf("failed to use one-time code: %w", errors.New("code mismatch"))
+		return 0, "", ce.NewError(span, ce.CodeOTPInvalid, ce.MsgInvalidOTP, err)
+	}
+
+	return authID, payload, nil
+}
+
+func (c *authCache) CreateSignInReportToken(ctx context.Context, authID, sessionID int64, token string, duration time.Duration) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "CreateSignInReportToken")
+	defer span.End()
+
+	tokenKey := fmt.Sprintf("%s:%s", constants.CachePrefixSignInReport, token)
+
+	// id: authID, sid: sessionID
+	// every flagged sign-in gets its own link, so none replaces another
+	script := `
+		redis.call("HSET", KEYS[1], "id", ARGV[1], "sid", ARGV[2])
+		redis.call("EXPIRE", KEYS[1], ARGV[3])
+		return 1
+	`
+
+	_, err := c.cache.Evaluate(
+		ctx, "hs:csrt", script,
+		[]string{tokenKey},
+		strconv.FormatInt(authID, 10), strconv.FormatInt(sessionID, 10), int(duration.Seconds()),
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to create sign-in report token: %w", err)
+		return ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *authCache) GetSignInReportToken(ctx context.Context, token string) (int64, int64, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "GetSignInReportToken")
+	defer span.End()
+
+	tokenKey := fmt.Sprintf("%s:%s", constants.CachePrefixSignInReport, token)
+
+	// id: authID, sid: sessionID
+	// unlike UseSignInReportToken, the token stays usable
+	script := `
+		local data = redis.call("HMGET", KEYS[1], "id", "sid")
+		if not data[1] then
+			return nil
+		end
+		return data
+	`
+
+	result, err := c.cache.Evaluate(ctx, "hs:gsrt", script, []string{tokenKey})
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch sign-in report token: %w", err)
+		if errors.Is(err, ce.ErrCacheNil) {
+			return 0, 0, ce.NewError(span, ce.CodeCacheValueNotFound, ce.MsgInvalidToken, wErr)
+		}
+		return 0, 0, ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		err := fmt.Errorf("failed to fetch sign-in report token: %w", ce.ErrTypeAssertionFailed)
+		return 0, 0, ce.NewError(span, ce.CodeTypeAssertionFailed, ce.MsgInternalServer, err)
+	}
+
+	authID, err := utils.ToInt64Any(values[0])
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch sign-in report token: %w", err)
+		return 0, 0, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+	sessionID, err := utils.ToInt64Any(values[1])
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch sign-in report token: %w", err)
+		return 0, 0, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	return authID, sessionID, nil
+}
+
+func (c *authCache) UseSignInReportToken(ctx context.Context, token string) (int64, int64, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "UseSignInReportToken")
+	defer span.End()
+
+	tokenKey := fmt.Sprintf("%s:%s", constants.CachePrefixSignInReport, token)
+
+	// id: authID, sid: sessionID
+	script := `
+		local data = redis.call("HMGET", KEYS[1], "id", "sid")
+		if not data[1] then
+			return nil
+		end
+		redis.call("DEL", KEYS[1])
+		return data
+	`
+
+	result, err := c.cache.Evaluate(ctx, "hs:usrt", script, []string{tokenKey})
+	if err != nil {
+		wErr := fmt.Errorf("failed to use sign-in report token: %w", err)
+		if errors.Is(err, ce.ErrCacheNil) {
+			return 0, 0, ce.NewError(span, ce.CodeCacheValueNotFound, ce.MsgInvalidToken, wErr)
+		}
+		return 0, 0, ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		err := fmt.Errorf("failed to use sign-in report token: %w", ce.ErrTypeAssertionFailed)
+		return 0, 0, ce.NewError(span, ce.CodeTypeAssertionFailed, ce.MsgInternalServer, err)
+	}
+
+	authID, err := utils.ToInt64Any(values[0])
+	if err != nil {
+		wErr := fmt.Errorf("failed to use sign-in report token: %w", err)
+		return 0, 0, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+	sessionID, err := utils.ToInt64Any(values[1])
+	if err != nil {
+		wErr := fmt.Errorf("failed to use sign-in report token: %w", err)
+		return 0, 0, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	return authID, sessionID, nil
+}
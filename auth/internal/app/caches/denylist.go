@@ -0,0 +1,295 @@
+package caches
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/cache"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+)
+
+const denylistErrorTracer string = "cache.denylist"
+
+type TokenDenylistCache interface {
+	Track(ctx context.Context, authID int64, sessionToken, parentToken string, token *entities.AccessToken) (err error)
+	DenySession(ctx context.Context, authID int64, sessionToken string) (err error)
+	DenyAll(ctx context.Context, authID int64, exceptSessionToken string) (err error)
+	TrackClient(ctx context.Context, clientID, secretHash string, token *entities.AccessToken) (err error)
+	DenyClient(ctx context.Context, clientID string) (err error)
+	DenyClientSecret(ctx context.Context, clientID, secretHash string) (err error)
+	IsDenied(ctx context.Context, jti string, expiresAt time.Time) (isDenied bool, err error)
+}
+
+type tokenDenylistCache struct {
+	cache *cache.Cache
+
+	// in-process view of the denylist, so not every request costs a redis hit
+	localTTL        time.Duration
+	localMaxEntries int
+
+	mu    sync.Mutex
+	local map[string]denylistEntry
+}
+
+type denylistEntry struct {
+	isDenied  bool
+	expiresAt time.Time
+}
+
+func NewTokenDenylistCache(cache *cache.Cache, localTTL time.Duration, localMaxEntries int) TokenDenylistCache {
+	return &tokenDenylistCache{
+		cache:           cache,
+		localTTL:        localTTL,
+		localMaxEntries: localMaxEntries,
+		local:           make(map[string]denylistEntry),
+	}
+}
+
+func (c *tokenDenylistCache) Track(ctx context.Context, authID int64, sessionToken, parentToken string, token *entities.AccessToken) error {
+	ctx, span := otel.Tracer(denylistErrorTracer).Start(ctx, "Track")
+	defer span.End()
+
+	// members are "<jti>|<session token>" scored by expiry, tokens issued
+	// for a rotated session are moved over to its replacement
+	script := `
+		local time = redis.call("TIME")
+		local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+		redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now)
+		if ARGV[4] ~= "" then
+			local suffix = "|" .. ARGV[4]
+			local members = redis.call("ZRANGE", KEYS[1], 0, -1, "WITHSCORES")
+			for i = 1, #members, 2 do
+				local member = members[i]
+				if string.sub(member, -#suffix) == suffix then
+					local jti = string.sub(member, 1, #member - #suffix)
+					redis.call("ZREM", KEYS[1], member)
+					redis.call("ZADD", KEYS[1], members[i + 1], jti .. "|" .. ARGV[3])
+				end
+			end
+		end
+		local expiresAt = tonumber(ARGV[2])
+		redis.call("ZADD", KEYS[1], expiresAt, ARGV[1] .. "|" .. ARGV[3])
+		if redis.call("PTTL", KEYS[1]) < expiresAt - now then
+			redis.call("PEXPIRE", KEYS[1], expiresAt - now)
+		end
+		return 1
+	`
+
+	_, err := c.cache.Evaluate(
+		ctx, "hs:tat", script,
+		[]string{c.trackKey(authID)},
+		token.ID, token.ExpiresAt.UnixMilli(), sessionToken, parentToken,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to track access token: %w", err)
+		return ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *tokenDenylistCache) TrackClient(ctx context.Context, clientID, secretHash string, token *entities.AccessToken) error {
+	ctx, span := otel.Tracer(denylistErrorTracer).Start(ctx, "TrackClient")
+	defer span.End()
+
+	// members are "<jti>|<secret hash>" scored by expiry, so the tokens of a
+	// rotated-out secret can be told apart from those of the current one
+	script := `
+		local time = redis.call("TIME")
+		local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+		redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now)
+		local expiresAt = tonumber(ARGV[2])
+		redis.call("ZADD", KEYS[1], expiresAt, ARGV[1] .. "|" .. ARGV[3])
+		if redis.call("PTTL", KEYS[1]) < expiresAt - now then
+			redis.call("PEXPIRE", KEYS[1], expiresAt - now)
+		end
+		return 1
+	`
+
+	_, err := c.cache.Evaluate(
+		ctx, "hs:tct", script,
+		[]string{c.clientTrackKey(clientID)},
+		token.ID, token.ExpiresAt.UnixMilli(), secretHash,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to track client access token: %w", err)
+		return ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *tokenDenylistCache) DenySession(ctx context.Context, authID int64, sessionToken string) error {
+	ctx, span := otel.Tracer(denylistErrorTracer).Start(ctx, "DenySession")
+	defer span.End()
+
+	if err := c.deny(ctx, c.trackKey(authID), sessionToken, false); err != nil {
+		wErr := fmt.Errorf("failed to deny session access tokens: %w", err)
+		return ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *tokenDenylistCache) DenyAll(ctx context.Context, authID int64, exceptSessionToken string) error {
+	ctx, span := otel.Tracer(denylistErrorTracer).Start(ctx, "DenyAll")
+	defer span.End()
+
+	if err := c.deny(ctx, c.trackKey(authID), exceptSessionToken, true); err != nil {
+		wErr := fmt.Errorf("failed to deny access tokens: %w", err)
+		return ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *tokenDenylistCache) DenyClient(ctx context.Context, clientID string) error {
+	ctx, span := otel.Tracer(denylistErrorTracer).Start(ctx, "DenyClient")
+	defer span.End()
+
+	// no token is tracked without a secret hash, so excluding the empty one denies them all
+	if err := c.deny(ctx, c.clientTrackKey(clientID), "", true); err != nil {
+		wErr := fmt.Errorf("failed to deny client access tokens: %w", err)
+		return ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *tokenDenylistCache) DenyClientSecret(ctx context.Context, clientID, secretHash string) error {
+	ctx, span := otel.Tracer(denylistErrorTracer).Start(ctx, "DenyClientSecret")
+	defer span.End()
+
+	if err := c.deny(ctx, c.clientTrackKey(clientID), secretHash, false); err != nil {
+		wErr := fmt.Errorf("failed to deny client secret access tokens: %w", err)
+		return ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *tokenDenylistCache) IsDenied(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	ctx, span := otel.Tracer(denylistErrorTracer).Start(ctx, "IsDenied")
+	defer span.End()
+
+	if jti == "" {
+		// issued before tokens carried an id, these simply run out
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+
+	c.mu.Lock()
+	entry, ok := c.local[jti]
+	c.mu.Unlock()
+
+	if ok && now.Before(entry.expiresAt) {
+		return entry.isDenied, nil
+	}
+
+	isDenied, err := c.cache.Exists(ctx, fmt.Sprintf("%s:%s", constants.CachePrefixDenylist, jti))
+	if err != nil {
+		wErr := fmt.Errorf("failed to check token denylist: %w", err)
+		return false, ce.NewError(span, ce.CodeCacheQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	// a denial never expires before the token does, an allowance is only trusted briefly
+	entry = denylistEntry{isDenied: isDenied, expiresAt: expiresAt}
+	if !isDenied && now.Add(c.localTTL).Before(expiresAt) {
+		entry.expiresAt = now.Add(c.localTTL)
+	}
+	c.remember(jti, entry, now)
+
+	return isDenied, nil
+}
+
+// denies the tracked tokens whose tag (session token or secret hash) matches, or all others when excepting
+func (c *tokenDenylistCache) deny(ctx context.Context, trackKey, tag string, isExcept bool) error {
+	mode := "only"
+	if isExcept {
+		mode = "except"
+	}
+
+	script := `
+		local time = redis.call("TIME")
+		local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+		redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now)
+		local denied = {}
+		local members = redis.call("ZRANGE", KEYS[1], 0, -1, "WITHSCORES")
+		for i = 1, #members, 2 do
+			local jti, session = string.match(members[i], "^(.-)|(.*)$")
+			local isMatch = session == ARGV[1]
+			if (ARGV[2] == "only" and isMatch) or (ARGV[2] == "except" and not isMatch) then
+				local ttl = tonumber(members[i + 1]) - now
+				redis.call("SET", KEYS[2] .. ":" .. jti, 1, "PX", ttl)
+				redis.call("ZREM", KEYS[1], members[i])
+				table.insert(denied, jti)
+				table.insert(denied, members[i + 1])
+			end
+		end
+		return denied
+	`
+
+	result, err := c.cache.Evaluate(
+		ctx, "hs:dat", script,
+		[]string{trackKey, constants.CachePrefixDenylist},
+		tag, mode,
+	)
+	if err != nil {
+		return err
+	}
+
+	values, ok := result.([]interface{})
+	if !ok {
+		return ce.ErrTypeAssertionFailed
+	}
+
+	// denials take effect in this instance right away, others pick them up from redis
+	now := time.Now().UTC()
+	for i := 0; i+1 < len(values); i += 2 {
+		jti, ok := values[i].(string)
+		if !ok {
+			continue
+		}
+		expiresAt, err := utils.ToInt64Any(values[i+1])
+		if err != nil {
+			continue
+		}
+		c.remember(jti, denylistEntry{isDenied: true, expiresAt: time.UnixMilli(expiresAt)}, now)
+	}
+
+	return nil
+}
+
+func (c *tokenDenylistCache) remember(jti string, entry denylistEntry, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.local) >= c.localMaxEntries {
+		for key, e := range c.local {
+			if !now.Before(e.expiresAt) {
+				delete(c.local, key)
+			}
+		}
+		if len(c.local) >= c.localMaxEntries {
+			// still full of live entries, start over rather than grow unbounded
+			c.local = make(map[string]denylistEntry)
+		}
+	}
+
+	c.local[jti] = entry
+}
+
+func (c *tokenDenylistCache) trackKey(authID int64) string {
+	return fmt.Sprintf("%s:%d", constants.CachePrefixAccessToken, authID)
+}
+
+func (c *tokenDenylistCache) clientTrackKey(clientID string) string {
+	return fmt.Sprintf("%s:%s", constants.CachePrefixClientToken, clientID)
+}
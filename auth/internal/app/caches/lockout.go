@@ -0,0 +1,260 @@
+package caches
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/cache"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+)
+
+const lockoutErrorTracer string = "cache.lockout"
+
+type LockoutCache interface {
+	GetLockout(ctx context.Context, authID int64, ipAddress string) (lockout *entities.Lockout, err error)
+	RecordAccountFailure(ctx context.Context, authID int64, policy *entities.LockoutPolicy) (isLocked bool, err error)
+	RecordIPFailure(ctx context.Context, ipAddress string, policy *entities.LockoutPolicy) (isLocked bool, err error)
+	ResetAccount(ctx context.Context, authID int64) (err error)
+	CreateUnlockToken(ctx context.Context, authID int64, token string, duration time.Duration) (err error)
+	UseUnlockToken(ctx context.Context, token string) (authID int64, err error)
+}
+
+type lockoutCache struct {
+	cache *cache.Cache
+}
+
+func NewLockoutCache(cache *cache.Cache) LockoutCache {
+	return &lockoutCache{cache}
+}
+
+func (c *lockoutCache) GetLockout(ctx context.Context, authID int64, ipAddress string) (*entities.Lockout, error) {
+	ctx, span := otel.Tracer(lockoutErrorTracer).Start(ctx, "GetLockout")
+	defer span.End()
+
+	// keys come in (lock, delay) pairs, either subject may be omitted
+	keys := make([]string, 0, 4)
+	if authID != 0 {
+		subject := c.accountSubject(authID)
+		keys = append(keys, c.lockKey(subject), c.delayKey(subject))
+	}
+	if ipAddress != "" {
+		subject := c.ipSubject(ipAddress)
+		keys = append(keys, c.lockKey(subject), c.delayKey(subject))
+	}
+
+	script := `
+		local locked = 0
+		local wait = 0
+		for i, key in ipairs(KEYS) do
+			local ttl = redis.call("PTTL", key)
+			if ttl > 0 then
+				if i % 2 == 1 then
+					locked = 1
+				end
+				if ttl > wait then
+					wait = ttl
+				end
+			end
+		end
+		return {locked, wait}
+	`
+
+	result, err := c.cache.Evaluate(ctx, "hs:lgl", script, keys)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch lockout: %w", err)
+		return nil, ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		err := fmt.Errorf("failed to fetch lockout: %w", ce.ErrTypeAssertionFailed)
+		return nil, ce.NewError(span, ce.CodeTypeAssertionFailed, ce.MsgInternalServer, err)
+	}
+
+	locked, ok1 := values[0].(int64)
+	wait, ok2 := values[1].(int64)
+	if !ok1 || !ok2 {
+		err := fmt.Errorf("failed to fetch lockout: %w", ce.ErrTypeAssertionFailed)
+		return nil, ce.NewError(span, ce.CodeTypeAssertionFailed, ce.MsgInternalServer, err)
+	}
+
+	lockout := entities.Lockout{
+		IsLocked:   locked == 1,
+		RetryAfter: time.Duration(wait) * time.Millisecond,
+	}
+
+	return &lockout, nil
+}
+
+func (c *lockoutCache) RecordAccountFailure(ctx context.Context, authID int64, policy *entities.LockoutPolicy) (bool, error) {
+	ctx, span := otel.Tracer(lockoutErrorTracer).Start(ctx, "RecordAccountFailure")
+	defer span.End()
+
+	isLocked, err := c.recordFailure(ctx, c.accountSubject(authID), policy)
+	if err != nil {
+		wErr := fmt.Errorf("failed to record account failure: %w", err)
+		return false, ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return isLocked, nil
+}
+
+func (c *lockoutCache) RecordIPFailure(ctx context.Context, ipAddress string, policy *entities.LockoutPolicy) (bool, error) {
+	ctx, span := otel.Tracer(lockoutErrorTracer).Start(ctx, "RecordIPFailure")
+	defer span.End()
+
+	isLocked, err := c.recordFailure(ctx, c.ipSubject(ipAddress), policy)
+	if err != nil {
+		wErr := fmt.Errorf("failed to record ip failure: %w", err)
+		return false, ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return isLocked, nil
+}
+
+func (c *lockoutCache) ResetAccount(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(lockoutErrorTracer).Start(ctx, "ResetAccount")
+	defer span.End()
+
+	subject := c.accountSubject(authID)
+
+	if err := c.cache.Delete(ctx, c.failureKey(subject), c.delayKey(subject), c.lockKey(subject)); err != nil {
+		wErr := fmt.Errorf("failed to reset account lockout: %w", err)
+		return ce.NewError(span, ce.CodeCacheQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *lockoutCache) CreateUnlockToken(ctx context.Context, authID int64, token string, duration time.Duration) error {
+	ctx, span := otel.Tracer(lockoutErrorTracer).Start(ctx, "CreateUnlockToken")
+	defer span.End()
+
+	authKey := fmt.Sprintf("%s:%d", constants.CachePrefixUnlock, authID)
+	tokenKey := fmt.Sprintf("%s:%s", constants.CachePrefixUnlock, token)
+
+	script := `
+		local token = redis.call("GET", KEYS[1])
+		if token then
+			redis.call("DEL", KEYS[1])
+			redis.call("DEL", KEYS[3] .. ":" .. token)
+		end
+		redis.call("SET", KEYS[1], ARGV[1], "EX", ARGV[3])
+		redis.call("SET", KEYS[2], ARGV[2], "EX", ARGV[3])
+		return 1
+	`
+
+	_, err := c.cache.Evaluate(
+		ctx, "hs:cut", script,
+		[]string{authKey, tokenKey, constants.CachePrefixUnlock},
+		token, strconv.FormatInt(authID, 10), int(duration.Seconds()),
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to create unlock token: %w", err)
+		return ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *lockoutCache) UseUnlockToken(ctx context.Context, token string) (int64, error) {
+	ctx, span := otel.Tracer(lockoutErrorTracer).Start(ctx, "UseUnlockToken")
+	defer span.End()
+
+	tokenKey := fmt.Sprintf("%s:%s", constants.CachePrefixUnlock, token)
+
+	script := `
+		local authID = redis.call("GET", KEYS[1])
+		if authID then
+			redis.call("DEL", KEYS[1])
+			redis.call("DEL", KEYS[2] .. ":" .. authID)
+			return authID
+		end
+		return nil
+	`
+
+	result, err := c.cache.Evaluate(ctx, "hs:uut", script, []string{tokenKey, constants.CachePrefixUnlock})
+	if err != nil {
+		wErr := fmt.Errorf("failed to use unlock token: %w", err)
+		if errors.Is(err, ce.ErrCacheNil) {
+			return 0, ce.NewError(span, ce.CodeCacheValueNotFound, ce.MsgInvalidToken, wErr)
+		}
+		return 0, ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	authID, err := utils.ToInt64Any(result)
+	if err != nil {
+		wErr := fmt.Errorf("failed to use unlock token: %w", err)
+		return 0, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	return authID, nil
+}
+
+func (c *lockoutCache) recordFailure(ctx context.Context, subject string, policy *entities.LockoutPolicy) (bool, error) {
+	// every failure doubles the delay before the next attempt is accepted,
+	// reaching the threshold replaces the delay with a lock
+	script := `
+		local count = redis.call("INCR", KEYS[1])
+		if count == 1 then
+			redis.call("PEXPIRE", KEYS[1], ARGV[2])
+		end
+		if count >= tonumber(ARGV[1]) then
+			redis.call("DEL", KEYS[1], KEYS[2])
+			redis.call("SET", KEYS[3], 1, "PX", ARGV[3])
+			return 1
+		end
+		local delay = tonumber(ARGV[4]) * 2 ^ (count - 1)
+		if delay > tonumber(ARGV[5]) then
+			delay = tonumber(ARGV[5])
+		end
+		if delay > 0 then
+			redis.call("SET", KEYS[2], 1, "PX", math.floor(delay))
+		end
+		return 0
+	`
+
+	result, err := c.cache.Evaluate(
+		ctx, "hs:lrf", script,
+		[]string{c.failureKey(subject), c.delayKey(subject), c.lockKey(subject)},
+		policy.MaxAttempts, policy.Window.Milliseconds(), policy.Duration.Milliseconds(),
+		policy.BaseDelay.Milliseconds(), policy.MaxDelay.Milliseconds(),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	isLocked, ok := result.(int64)
+	if !ok {
+		return false, ce.ErrTypeAssertionFailed
+	}
+
+	return isLocked == 1, nil
+}
+
+func (c *lockoutCache) accountSubject(authID int64) string {
+	return fmt.Sprintf("a:%d", authID)
+}
+
+func (c *lockoutCache) ipSubject(ipAddress string) string {
+	return fmt.Sprintf("i:%s", ipAddress)
+}
+
+func (c *lockoutCache) failureKey(subject string) string {
+	return fmt.Sprintf("%s:%s", constants.CachePrefixLockoutFailure, subject)
+}
+
+func (c *lockoutCache) delayKey(subject string) string {
+	return fmt.Sprintf("%s:%s", constants.CachePrefixLockoutDelay, subject)
+}
+
+func (c *lockoutCache) lockKey(subject string) string {
+	return fmt.Sprintf("%s:%s", constants.CachePrefixLockout, subject)
+}
@@ -0,0 +1,125 @@
+package caches
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/cache"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+)
+
+const mfaErrorTracer string = "cache.mfa"
+
+type MFACache interface {
+	CreatePendingToken(ctx context.Context, authID int64, token string, duration time.Duration) (err error)
+	GetPendingToken(ctx context.Context, token string, maxAttempts int) (authID int64, err error)
+	DeletePendingToken(ctx context.Context, token string) (err error)
+	MarkCodeUsed(ctx context.Context, authID int64, code string, duration time.Duration) (isFirstUse bool, err error)
+}
+
+type mfaCache struct {
+	cache *cache.Cache
+}
+
+func NewMFACache(cache *cache.Cache) MFACache {
+	return &mfaCache{cache}
+}
+
+func (c *mfaCache) CreatePendingToken(ctx context.Context, authID int64, token string, duration time.Duration) error {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "CreatePendingToken")
+	defer span.End()
+
+	tokenKey := fmt.Sprintf("%s:%s", constants.CachePrefixMFAPending, token)
+
+	// id: authID, at: attempts
+	script := `
+		redis.call("DEL", KEYS[1])
+		redis.call("HSET", KEYS[1], "id", ARGV[1], "at", 0)
+		redis.call("EXPIRE", KEYS[1], ARGV[2])
+		return 1
+	`
+
+	_, err := c.cache.Evaluate(
+		ctx, "hs:mcpt", script, []string{tokenKey},
+		strconv.FormatInt(authID, 10), int(duration.Seconds()),
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to create mfa pending token: %w", err)
+		return ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *mfaCache) GetPendingToken(ctx context.Context, token string, maxAttempts int) (int64, error) {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "GetPendingToken")
+	defer span.End()
+
+	tokenKey := fmt.Sprintf("%s:%s", constants.CachePrefixMFAPending, token)
+
+	// every lookup counts as an attempt, the token is discarded once attempts are exhausted
+	script := `
+		local authID = redis.call("HGET", KEYS[1], "id")
+		if authID then
+			local attempts = redis.call("HINCRBY", KEYS[1], "at", 1)
+			if attempts > tonumber(ARGV[1]) then
+				redis.call("DEL", KEYS[1])
+				return nil
+			end
+			return authID
+		end
+		return nil
+	`
+
+	result, err := c.cache.Evaluate(ctx, "hs:mgpt", script, []string{tokenKey}, maxAttempts)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch mfa pending token: %w", err)
+		if errors.Is(err, ce.ErrCacheNil) {
+			return 0, ce.NewError(span, ce.CodeCacheValueNotFound, ce.MsgInvalidToken, wErr)
+		}
+		return 0, ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	authID, err := utils.ToInt64Any(result)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch mfa pending token: %w", err)
+		return 0, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	return authID, nil
+}
+
+func (c *mfaCache) DeletePendingToken(ctx context.Context, token string) error {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "DeletePendingToken")
+	defer span.End()
+
+	tokenKey := fmt.Sprintf("%s:%s", constants.CachePrefixMFAPending, token)
+
+	if err := c.cache.Delete(ctx, tokenKey); err != nil {
+		wErr := fmt.Errorf("failed to delete mfa pending token: %w", err)
+		return ce.NewError(span, ce.CodeCacheQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *mfaCache) MarkCodeUsed(ctx context.Context, authID int64, code string, duration time.Duration) (bool, error) {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "MarkCodeUsed")
+	defer span.End()
+
+	key := fmt.Sprintf("%s:%d:%s", constants.CachePrefixMFAUsedCode, authID, code)
+
+	isFirstUse, err := c.cache.SetNX(ctx, key, 1, duration)
+	if err != nil {
+		wErr := fmt.Errorf("failed to mark mfa code used: %w", err)
+		return false, ce.NewError(span, ce.CodeCacheQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return isFirstUse, nil
+}
@@ -20,6 +20,8 @@ const oAuthErrorTracer string = "cache.oauth"
 type OAuthCache interface {
 	StoreAuth(ctx context.Context, code string, auth *entities.Auth, duration time.Duration) (err error)
 	GetAuth(ctx context.Context, code string) (auth *entities.Auth, err error)
+	StoreState(ctx context.Context, state *entities.OAuthState, duration time.Duration) (err error)
+	UseState(ctx context.Context, state string) (oauthState *entities.OAuthState, err error)
 }
 
 type oAuthCache struct {
@@ -36,10 +38,11 @@ func (c *oAuthCache) StoreAuth(ctx context.Context, code string, auth *entities.
 
 	codeKey := fmt.Sprintf("%s:%s", constants.CachePrefixOAuthStore, code)
 	isVerified := strconv.FormatBool(auth.IsVerified)
+	isApproved := strconv.FormatBool(auth.IsApproved)
 	createdAt := auth.CreatedAt.Format(time.RFC3339)
 	updatedAt := auth.UpdatedAt.Format(time.RFC3339)
 
-	// id: authID, em: email, rid: roleID, iv: isVerified, cat: createdAt, uat: updatedAt
+	// id: authID, em: email, rid: roleID, iv: isVerified, ia: isApproved, cat: createdAt, uat: updatedAt
 	script := `
 		redis.call("DEL", KEYS[1])
 		redis.call("HSET", KEYS[1],
@@ -47,16 +50,17 @@ func (c *oAuthCache) StoreAuth(ctx context.Context, code string, auth *entities.
 			"em", ARGV[2],
 			"rid", ARGV[3],
 			"iv", ARGV[4],
-			"cat", ARGV[5],
-			"uat", ARGV[6]
+			"ia", ARGV[5],
+			"cat", ARGV[6],
+			"uat", ARGV[7]
 		)
-		redis.call("EXPIRE", KEYS[1], ARGV[7])
+		redis.call("EXPIRE", KEYS[1], ARGV[8])
 		return 1
 	`
 
 	_, err := c.cache.Evaluate(
 		ctx, "hs:oasa", script, []string{codeKey},
-		auth.ID, auth.Email, auth.RoleID, isVerified,
+		auth.ID, auth.Email, auth.RoleID, isVerified, isApproved,
 		createdAt, updatedAt, int(duration.Seconds()),
 	)
 	if err != nil {
@@ -73,18 +77,19 @@ func (c *oAuthCache) GetAuth(ctx context.Context, code string) (*entities.Auth,
 
 	codeKey := fmt.Sprintf("%s:%s", constants.CachePrefixOAuthStore, code)
 
-	// id: authID, em: email, rid: roleID, iv: isVerified, cat: createdAt, uat: updatedAt
+	// id: authID, em: email, rid: roleID, iv: isVerified, ia: isApproved, cat: createdAt, uat: updatedAt
 	script := `
-		local data = redis.call("HMGET", KEYS[1], "id", "em", "rid", "iv", "cat", "uat")
+		local data = redis.call("HMGET", KEYS[1], "id", "em", "rid", "iv", "ia", "cat", "uat")
 		if data and data[1] then
 			local authID = data[1]
 			local email = data[2]
 			local roleID = data[3]
 			local isVerified = data[4]
-			local createdAt = data[5]
-			local updatedAt = data[6]
+			local isApproved = data[5]
+			local createdAt = data[6]
+			local updatedAt = data[7]
 			redis.call("DEL", KEYS[1])
-			return {authID, email, roleID, isVerified, createdAt, updatedAt}
+			return {authID, email, roleID, isVerified, isApproved, createdAt, updatedAt}
 		end
 		return nil
 	`
@@ -99,7 +104,7 @@ func (c *oAuthCache) GetAuth(ctx context.Context, code string) (*entities.Auth,
 	}
 
 	values, ok := result.([]interface{})
-	if !ok || len(values) != 6 {
+	if !ok || len(values) != 7 {
 		err := fmt.Errorf("failed to fetch auth: %w", ce.ErrTypeAssertionFailed)
 		return nil, ce.NewError(span, ce.CodeTypeAssertionFailed, ce.MsgInternalServer, err)
 	}
@@ -108,9 +113,10 @@ func (c *oAuthCache) GetAuth(ctx context.Context, code string) (*entities.Auth,
 	email, ok2 := values[1].(string)
 	roleStr, ok3 := values[2].(string)
 	isVerifiedStr, ok4 := values[3].(string)
-	createdAtStr, ok5 := values[4].(string)
-	updatedAtStr, ok6 := values[5].(string)
-	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || !ok6 {
+	isApprovedStr, ok5 := values[4].(string)
+	createdAtStr, ok6 := values[5].(string)
+	updatedAtStr, ok7 := values[6].(string)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || !ok6 || !ok7 {
 		err := fmt.Errorf("failed to fetch auth: %w", ce.ErrTypeAssertionFailed)
 		return nil, ce.NewError(span, ce.CodeTypeAssertionFailed, ce.MsgInternalServer, err)
 	}
@@ -133,6 +139,12 @@ func (c *oAuthCache) GetAuth(ctx context.Context, code string) (*entities.Auth,
 		return nil, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
 	}
 
+	isApproved, err := strconv.ParseBool(isApprovedStr)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch auth: %w", err)
+		return nil, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
 	createdAt, err := time.Parse(time.RFC3339, createdAtStr)
 	if err != nil {
 		wErr := fmt.Errorf("failed to fetch auth: %w", err)
@@ -150,9 +162,109 @@ func (c *oAuthCache) GetAuth(ctx context.Context, code string) (*entities.Auth,
 		Email:      email,
 		RoleID:     int16(roleID),
 		IsVerified: isVerified,
+		IsApproved: isApproved,
 		CreatedAt:  createdAt,
 		UpdatedAt:  updatedAt,
 	}
 
 	return &auth, nil
 }
+
+func (c *oAuthCache) StoreState(ctx context.Context, state *entities.OAuthState, duration time.Duration) error {
+	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "StoreState")
+	defer span.End()
+
+	stateKey := fmt.Sprintf("%s:%s", constants.CachePrefixOAuthState, state.State)
+
+	// p: provider, v: pkce verifier, n: nonce, r: redirectTo, a: authID
+	script := `
+		redis.call("DEL", KEYS[1])
+		redis.call("HSET", KEYS[1],
+			"p", ARGV[1],
+			"v", ARGV[2],
+			"n", ARGV[3],
+			"r", ARGV[4],
+			"a", ARGV[5]
+		)
+		redis.call("EXPIRE", KEYS[1], ARGV[6])
+		return 1
+	`
+
+	_, err := c.cache.Evaluate(
+		ctx, "hs:oast", script, []string{stateKey},
+		state.Provider, state.Verifier, state.Nonce, state.RedirectTo,
+		state.AuthID, int(duration.Seconds()),
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to store oauth state: %w", err)
+		return ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *oAuthCache) UseState(ctx context.Context, state string) (*entities.OAuthState, error) {
+	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "UseState")
+	defer span.End()
+
+	stateKey := fmt.Sprintf("%s:%s", constants.CachePrefixOAuthState, state)
+
+	// a state is consumed on first use, a replayed callback finds nothing
+	// p: provider, v: pkce verifier, n: nonce, r: redirectTo, a: authID
+	script := `
+		local data = redis.call("HMGET", KEYS[1], "p", "v", "n", "r", "a")
+		if data and data[1] then
+			redis.call("DEL", KEYS[1])
+			return {data[1], data[2], data[3], data[4], data[5]}
+		end
+		return nil
+	`
+
+	result, err := c.cache.Evaluate(ctx, "hs:oaut", script, []string{stateKey})
+	if err != nil {
+		wErr := fmt.Errorf("failed to use oauth state: %w", err)
+		if errors.Is(err, ce.ErrCacheNil) {
+			return nil, ce.NewError(span, ce.CodeOAuthStateInvalid, ce.MsgInvalidOAuthState, wErr)
+		}
+		return nil, ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 5 {
+		err := fmt.Errorf("failed to use oauth state: %w", ce.ErrTypeAssertionFailed)
+		return nil, ce.NewError(span, ce.CodeTypeAssertionFailed, ce.MsgInternalServer, err)
+	}
+
+	providerStr, ok1 := values[0].(string)
+	verifier, ok2 := values[1].(string)
+	nonce, ok3 := values[2].(string)
+	redirectTo, ok4 := values[3].(string)
+	authStr, ok5 := values[4].(string)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 {
+		err := fmt.Errorf("failed to use oauth state: %w", ce.ErrTypeAssertionFailed)
+		return nil, ce.NewError(span, ce.CodeTypeAssertionFailed, ce.MsgInternalServer, err)
+	}
+
+	provider, err := utils.ToInt64(providerStr)
+	if err != nil {
+		wErr := fmt.Errorf("failed to use oauth state: %w", err)
+		return nil, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	authID, err := utils.ToInt64(authStr)
+	if err != nil {
+		wErr := fmt.Errorf("failed to use oauth state: %w", err)
+		return nil, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	oauthState := entities.OAuthState{
+		State:      state,
+		Provider:   int16(provider),
+		Verifier:   verifier,
+		Nonce:      nonce,
+		RedirectTo: redirectTo,
+		AuthID:     authID,
+	}
+
+	return &oauthState, nil
+}
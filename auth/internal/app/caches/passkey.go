@@ -0,0 +1,119 @@
+package caches
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/cache"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"go.opentelemetry.io/otel"
+)
+
+const passkeyErrorTracer string = "cache.passkey"
+
+type PasskeyCache interface {
+	CreateRegistrationChallenge(ctx context.Context, authID int64, session []byte, duration time.Duration) (err error)
+	UseRegistrationChallenge(ctx context.Context, authID int64) (session []byte, err error)
+	CreateLoginChallenge(ctx context.Context, challengeID string, session []byte, duration time.Duration) (err error)
+	UseLoginChallenge(ctx context.Context, challengeID string) (session []byte, err error)
+}
+
+type passkeyCache struct {
+	cache *cache.Cache
+}
+
+func NewPasskeyCache(cache *cache.Cache) PasskeyCache {
+	return &passkeyCache{cache}
+}
+
+func (c *passkeyCache) CreateRegistrationChallenge(ctx context.Context, authID int64, session []byte, duration time.Duration) error {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "CreateRegistrationChallenge")
+	defer span.End()
+
+	// a newer registration ceremony replaces any pending one
+	key := fmt.Sprintf("%s:%d", constants.CachePrefixPasskeyRegister, authID)
+
+	if err := c.cache.Set(ctx, key, session, duration); err != nil {
+		wErr := fmt.Errorf("failed to create passkey registration challenge: %w", err)
+		return ce.NewError(span, ce.CodeCacheQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *passkeyCache) UseRegistrationChallenge(ctx context.Context, authID int64) ([]byte, error) {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "UseRegistrationChallenge")
+	defer span.End()
+
+	key := fmt.Sprintf("%s:%d", constants.CachePrefixPasskeyRegister, authID)
+
+	session, err := c.use(ctx, "hs:purc", key)
+	if err != nil {
+		wErr := fmt.Errorf("failed to use passkey registration challenge: %w", err)
+		if errors.Is(err, ce.ErrCacheNil) {
+			return nil, ce.NewError(span, ce.CodeCacheValueNotFound, ce.MsgInvalidToken, wErr)
+		}
+		return nil, ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return session, nil
+}
+
+func (c *passkeyCache) CreateLoginChallenge(ctx context.Context, challengeID string, session []byte, duration time.Duration) error {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "CreateLoginChallenge")
+	defer span.End()
+
+	key := fmt.Sprintf("%s:%s", constants.CachePrefixPasskeyLogin, challengeID)
+
+	if err := c.cache.Set(ctx, key, session, duration); err != nil {
+		wErr := fmt.Errorf("failed to create passkey login challenge: %w", err)
+		return ce.NewError(span, ce.CodeCacheQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (c *passkeyCache) UseLoginChallenge(ctx context.Context, challengeID string) ([]byte, error) {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "UseLoginChallenge")
+	defer span.End()
+
+	key := fmt.Sprintf("%s:%s", constants.CachePrefixPasskeyLogin, challengeID)
+
+	session, err := c.use(ctx, "hs:pulc", key)
+	if err != nil {
+		wErr := fmt.Errorf("failed to use passkey login challenge: %w", err)
+		if errors.Is(err, ce.ErrCacheNil) {
+			return nil, ce.NewError(span, ce.CodeCacheValueNotFound, ce.MsgInvalidToken, wErr)
+		}
+		return nil, ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return session, nil
+}
+
+func (c *passkeyCache) use(ctx context.Context, hashKey, key string) ([]byte, error) {
+	// challenges are single-use, so they are deleted on read
+	script := `
+		local session = redis.call("GET", KEYS[1])
+		if session then
+			redis.call("DEL", KEYS[1])
+			return session
+		end
+		return nil
+	`
+
+	result, err := c.cache.Evaluate(ctx, hashKey, script, []string{key})
+	if err != nil {
+		return nil, err
+	}
+
+	session, ok := result.(string)
+	if !ok {
+		return nil, ce.ErrTypeAssertionFailed
+	}
+
+	return []byte(session), nil
+}
@@ -0,0 +1,107 @@
+package caches
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/cache"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+)
+
+const rateLimitErrorTracer string = "cache.ratelimit"
+
+type RateLimitCache interface {
+	Allow(ctx context.Context, route, ipAddress, account string, policy *entities.RateLimitPolicy) (rateLimit *entities.RateLimit, err error)
+}
+
+type rateLimitCache struct {
+	cache *cache.Cache
+}
+
+func NewRateLimitCache(cache *cache.Cache) RateLimitCache {
+	return &rateLimitCache{cache}
+}
+
+func (c *rateLimitCache) Allow(ctx context.Context, route, ipAddress, account string, policy *entities.RateLimitPolicy) (*entities.RateLimit, error) {
+	ctx, span := otel.Tracer(rateLimitErrorTracer).Start(ctx, "Allow")
+	defer span.End()
+
+	keys := make([]string, 0, 2)
+	args := []interface{}{policy.Window.Milliseconds(), utils.NewUUID().String()}
+	if policy.IPLimit > 0 && ipAddress != "" {
+		keys = append(keys, fmt.Sprintf("%s:%s:i:%s", constants.CachePrefixRateLimit, route, ipAddress))
+		args = append(args, policy.IPLimit)
+	}
+	if policy.AccountLimit > 0 && account != "" {
+		keys = append(keys, fmt.Sprintf("%s:%s:a:%s", constants.CachePrefixRateLimit, route, account))
+		args = append(args, policy.AccountLimit)
+	}
+	if len(keys) == 0 {
+		return &entities.RateLimit{IsAllowed: true}, nil
+	}
+
+	// sliding window log, the most restrictive key decides the outcome
+	// and a request is only recorded when every key still has room
+	script := `
+		local time = redis.call("TIME")
+		local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+		local window = tonumber(ARGV[1])
+		local limit, remaining, reset = 0, 0, 0
+		for i, key in ipairs(KEYS) do
+			redis.call("ZREMRANGEBYSCORE", key, "-inf", now - window)
+			local max = tonumber(ARGV[i + 2])
+			local left = max - redis.call("ZCARD", key)
+			local wait = window
+			local oldest = redis.call("ZRANGE", key, 0, 0, "WITHSCORES")
+			if #oldest == 2 then
+				wait = tonumber(oldest[2]) + window - now
+			end
+			if i == 1 or left < remaining or (left == remaining and wait > reset) then
+				limit, remaining, reset = max, left, wait
+			end
+		end
+		if remaining <= 0 then
+			return {0, limit, 0, reset}
+		end
+		for _, key in ipairs(KEYS) do
+			redis.call("ZADD", key, now, ARGV[2])
+			redis.call("PEXPIRE", key, window)
+		end
+		return {1, limit, remaining - 1, reset}
+	`
+
+	result, err := c.cache.Evaluate(ctx, "hs:rla", script, keys, args...)
+	if err != nil {
+		wErr := fmt.Errorf("failed to evaluate rate limit: %w", err)
+		return nil, ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalServer, wErr)
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 4 {
+		err := fmt.Errorf("failed to evaluate rate limit: %w", ce.ErrTypeAssertionFailed)
+		return nil, ce.NewError(span, ce.CodeTypeAssertionFailed, ce.MsgInternalServer, err)
+	}
+
+	allowed, ok1 := values[0].(int64)
+	limit, ok2 := values[1].(int64)
+	remaining, ok3 := values[2].(int64)
+	reset, ok4 := values[3].(int64)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		err := fmt.Errorf("failed to evaluate rate limit: %w", ce.ErrTypeAssertionFailed)
+		return nil, ce.NewError(span, ce.CodeTypeAssertionFailed, ce.MsgInternalServer, err)
+	}
+
+	rateLimit := entities.RateLimit{
+		IsAllowed:  allowed == 1,
+		Limit:      int(limit),
+		Remaining:  int(remaining),
+		ResetAfter: time.Duration(reset) * time.Millisecond,
+	}
+
+	return &rateLimit, nil
+}
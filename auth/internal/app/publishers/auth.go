@@ -3,63 +3,340 @@ package publishers
 import (
 	"context"
 	"fmt"
-	"log"
 	"time"
 
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/services/broker"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
 	"github.com/ritchieridanko/apotekly-api/auth/pkg/events"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 )
 
 const authErrorTracer string = "publisher.auth"
 
 type AuthEventPublisher interface {
-	PublishAuthRegistered(ctx context.Context, authID int64, email, token string) (err error)
+	PublishAuthRegistered(ctx context.Context, authID int64, email, token, code string) (err error)
+	PublishSessionReuseDetected(ctx context.Context, authID int64, email string, session *entities.Session) (err error)
+	PublishVerificationRequested(ctx context.Context, authID int64, email, token, code string) (err error)
+	PublishEmailChangeRequested(ctx context.Context, authID int64, currentEmail, newEmail, token, code string) (err error)
+	PublishEmailChanged(ctx context.Context, authID int64, oldEmail, newEmail string) (err error)
+	PublishPasswordResetRequested(ctx context.Context, authID int64, email, token string) (err error)
+	PublishPasswordChanged(ctx context.Context, authID int64, email string) (err error)
+	PublishAccountVerified(ctx context.Context, authID int64, email string) (err error)
+	PublishUnlockRequested(ctx context.Context, authID int64, email, token string) (err error)
+	PublishMagicLinkRequested(ctx context.Context, authID int64, email, token string, request *entities.Request) (err error)
+	PublishLoginCodeRequested(ctx context.Context, authID int64, email, code string, request *entities.Request) (err error)
+	PublishSessionCreated(ctx context.Context, authID int64, session *entities.CreateSession) (err error)
+	PublishSessionRevoked(ctx context.Context, authID int64, reason string) (err error)
+	PublishNewSignInDetected(ctx context.Context, authID int64, email, token string, alert *entities.SignInAlert) (err error)
+	PublishOAuthLinked(ctx context.Context, authID int64, email, provider string) (err error)
+	PublishDeletionScheduled(ctx context.Context, authID int64, email string, scheduledAt time.Time) (err error)
+	PublishDeletionCancelled(ctx context.Context, authID int64, email string) (err error)
+	PublishAccountDeleted(ctx context.Context, authID int64, email string) (err error)
 }
 
+// events are written to the outbox, within the caller's transaction when there is one,
+// and relayed to the broker by the outbox worker
 type authEventPublisher struct {
-	producer *broker.Producer
-	appName  string
+	or      repositories.OutboxRepository
+	appName string
 }
 
-func NewAuthEventPublisher(producer *broker.Producer, appName string) AuthEventPublisher {
-	return &authEventPublisher{producer, appName}
+func NewAuthEventPublisher(or repositories.OutboxRepository, appName string) AuthEventPublisher {
+	return &authEventPublisher{or, appName}
 }
 
-func (e *authEventPublisher) PublishAuthRegistered(ctx context.Context, authID int64, email, token string) error {
+func (e *authEventPublisher) PublishAuthRegistered(ctx context.Context, authID int64, email, token, code string) error {
 	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishAuthRegistered")
 	defer span.End()
 
-	et := constants.EventTypeAuthRegistered
 	data := events.AuthRegistered{
 		Recipient: email,
 		Token:     token,
+		AuthId:    authID,
+		Code:      code,
 	}
 
-	bytes, err := proto.Marshal(&data)
+	return e.publish(ctx, span, authID, constants.EventTypeAuthRegistered, &data)
+}
+
+func (e *authEventPublisher) PublishSessionReuseDetected(ctx context.Context, authID int64, email string, session *entities.Session) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishSessionReuseDetected")
+	defer span.End()
+
+	data := events.SessionReuseDetected{
+		Recipient:  email,
+		UserAgent:  session.UserAgent,
+		IpAddress:  session.IPAddress,
+		DetectedAt: time.Now().UTC().UnixMilli(),
+		AuthId:     authID,
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeSessionReuseDetected, &data)
+}
+
+func (e *authEventPublisher) PublishVerificationRequested(ctx context.Context, authID int64, email, token, code string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishVerificationRequested")
+	defer span.End()
+
+	data := events.VerificationRequested{
+		AuthId:    authID,
+		Recipient: email,
+		Token:     token,
+		Code:      code,
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeVerificationRequested, &data)
+}
+
+func (e *authEventPublisher) PublishEmailChangeRequested(ctx context.Context, authID int64, currentEmail, newEmail, token, code string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishEmailChangeRequested")
+	defer span.End()
+
+	// the token goes to the new address, proving it can receive mail
+	data := events.EmailChangeRequested{
+		AuthId:       authID,
+		Recipient:    newEmail,
+		Token:        token,
+		CurrentEmail: currentEmail,
+		Code:         code,
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeEmailChangeRequested, &data)
+}
+
+func (e *authEventPublisher) PublishEmailChanged(ctx context.Context, authID int64, oldEmail, newEmail string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishEmailChanged")
+	defer span.End()
+
+	// the old address is told, in case the change was not made by its owner
+	data := events.EmailChanged{
+		AuthId:    authID,
+		Recipient: oldEmail,
+		NewEmail:  newEmail,
+		ChangedAt: time.Now().UTC().UnixMilli(),
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeEmailChanged, &data)
+}
+
+func (e *authEventPublisher) PublishPasswordResetRequested(ctx context.Context, authID int64, email, token string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishPasswordResetRequested")
+	defer span.End()
+
+	data := events.PasswordResetRequested{
+		AuthId:    authID,
+		Recipient: email,
+		Token:     token,
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypePasswordResetRequested, &data)
+}
+
+func (e *authEventPublisher) PublishPasswordChanged(ctx context.Context, authID int64, email string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishPasswordChanged")
+	defer span.End()
+
+	data := events.PasswordChanged{
+		AuthId:    authID,
+		Recipient: email,
+		ChangedAt: time.Now().UTC().UnixMilli(),
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypePasswordChanged, &data)
+}
+
+func (e *authEventPublisher) PublishAccountVerified(ctx context.Context, authID int64, email string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishAccountVerified")
+	defer span.End()
+
+	data := events.AccountVerified{
+		AuthId:     authID,
+		Recipient:  email,
+		VerifiedAt: time.Now().UTC().UnixMilli(),
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeAccountVerified, &data)
+}
+
+func (e *authEventPublisher) PublishUnlockRequested(ctx context.Context, authID int64, email, token string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishUnlockRequested")
+	defer span.End()
+
+	data := events.UnlockRequested{
+		AuthId:    authID,
+		Recipient: email,
+		Token:     token,
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeUnlockRequested, &data)
+}
+
+func (e *authEventPublisher) PublishMagicLinkRequested(ctx context.Context, authID int64, email, token string, request *entities.Request) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishMagicLinkRequested")
+	defer span.End()
+
+	data := events.MagicLinkRequested{
+		AuthId:    authID,
+		Recipient: email,
+		Token:     token,
+		UserAgent: request.UserAgent,
+		IpAddress: request.IPAddress,
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeMagicLinkRequested, &data)
+}
+
+func (e *authEventPublisher) PublishLoginCodeRequested(ctx context.Context, authID int64, email, code string, request *entities.Request) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishLoginCodeRequested")
+	defer span.End()
+
+	data := events.LoginCodeRequested{
+		AuthId:    authID,
+		Recipient: email,
+		Code:      code,
+		UserAgent: request.UserAgent,
+		IpAddress: request.IPAddress,
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeLoginCodeRequested, &data)
+}
+
+func (e *authEventPublisher) PublishSessionCreated(ctx context.Context, authID int64, session *entities.CreateSession) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishSessionCreated")
+	defer span.End()
+
+	data := events.SessionCreated{
+		AuthId:    authID,
+		UserAgent: session.UserAgent,
+		IpAddress: session.IPAddress,
+		CreatedAt: time.Now().UTC().UnixMilli(),
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeSessionCreated, &data)
+}
+
+func (e *authEventPublisher) PublishSessionRevoked(ctx context.Context, authID int64, reason string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishSessionRevoked")
+	defer span.End()
+
+	data := events.SessionRevoked{
+		AuthId:    authID,
+		Reason:    reason,
+		RevokedAt: time.Now().UTC().UnixMilli(),
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeSessionRevoked, &data)
+}
+
+func (e *authEventPublisher) PublishNewSignInDetected(ctx context.Context, authID int64, email, token string, alert *entities.SignInAlert) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishNewSignInDetected")
+	defer span.End()
+
+	data := events.NewSignInDetected{
+		AuthId:           authID,
+		Recipient:        email,
+		Token:            token,
+		UserAgent:        alert.UserAgent,
+		IpAddress:        alert.IPAddress,
+		Location:         alert.Location,
+		ImpossibleTravel: alert.IsImpossibleTravel,
+		SignedInAt:       alert.SignedInAt.UnixMilli(),
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeNewSignInDetected, &data)
+}
+
+func (e *authEventPublisher) PublishOAuthLinked(ctx context.Context, authID int64, email, provider string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishOAuthLinked")
+	defer span.End()
+
+	data := events.OAuthLinked{
+		AuthId:    authID,
+		Recipient: email,
+		Provider:  provider,
+		LinkedAt:  time.Now().UTC().UnixMilli(),
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeOAuthLinked, &data)
+}
+
+func (e *authEventPublisher) PublishDeletionScheduled(ctx context.Context, authID int64, email string, scheduledAt time.Time) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishDeletionScheduled")
+	defer span.End()
+
+	data := events.AccountDeletionScheduled{
+		AuthId:      authID,
+		Recipient:   email,
+		ScheduledAt: scheduledAt.UTC().UnixMilli(),
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeDeletionScheduled, &data)
+}
+
+func (e *authEventPublisher) PublishDeletionCancelled(ctx context.Context, authID int64, email string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishDeletionCancelled")
+	defer span.End()
+
+	data := events.AccountDeletionCancelled{
+		AuthId:      authID,
+		Recipient:   email,
+		CancelledAt: time.Now().UTC().UnixMilli(),
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeDeletionCancelled, &data)
+}
+
+func (e *authEventPublisher) PublishAccountDeleted(ctx context.Context, authID int64, email string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "PublishAccountDeleted")
+	defer span.End()
+
+	data := events.AccountDeleted{
+		AuthId:    authID,
+		Recipient: email,
+		DeletedAt: time.Now().UTC().UnixMilli(),
+	}
+
+	return e.publish(ctx, span, authID, constants.EventTypeAccountDeleted, &data)
+}
+
+func (e *authEventPublisher) publish(ctx context.Context, span trace.Span, authID int64, et string, data proto.Message) error {
+	bytes, err := proto.Marshal(data)
 	if err != nil {
 		wErr := fmt.Errorf("failed to publish event %s: %w", et, err)
 		return ce.NewError(span, ce.CodeEventPublishingFailed, ce.MsgInternalServer, wErr)
 	}
 
-	key := fmt.Sprintf("auth-%d", authID)
 	event := events.Event{
 		EventId:       utils.NewUUID().String(),
 		EventType:     et,
 		SourceService: e.appName,
 		Timestamp:     time.Now().UTC().UnixMilli(),
 		Data:          bytes,
+		Locale:        utils.CtxGetLocale(ctx),
 	}
 
-	if err := e.producer.Publish(ctx, "auth-events", key, &event); err != nil {
+	payload, err := proto.Marshal(&event)
+	if err != nil {
 		wErr := fmt.Errorf("failed to publish event %s: %w", et, err)
 		return ce.NewError(span, ce.CodeEventPublishingFailed, ce.MsgInternalServer, wErr)
 	}
 
-	log.Printf("event %s published successfully", et)
-	return nil
+	traceID, correlationID := broker.TraceHeaders(ctx)
+	message := entities.CreateOutboxMessage{
+		EventID:       event.EventId,
+		EventType:     et,
+		Topic:         constants.TopicAuthEvents,
+		Key:           fmt.Sprintf("auth-%d", authID),
+		Payload:       payload,
+		TraceID:       traceID,
+		CorrelationID: correlationID,
+	}
+
+	return e.or.Create(ctx, &message)
 }
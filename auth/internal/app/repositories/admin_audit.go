@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"go.opentelemetry.io/otel"
+)
+
+const adminAuditErrorTracer string = "repository.admin_audit"
+
+type AdminAuditRepository interface {
+	Create(ctx context.Context, adminID *int64, data *entities.CreateAdminAudit) (err error)
+}
+
+type adminAuditRepository struct {
+	database *database.Database
+}
+
+func NewAdminAuditRepository(database *database.Database) AdminAuditRepository {
+	return &adminAuditRepository{database}
+}
+
+func (r *adminAuditRepository) Create(ctx context.Context, adminID *int64, data *entities.CreateAdminAudit) error {
+	ctx, span := otel.Tracer(adminAuditErrorTracer).Start(ctx, "Create")
+	defer span.End()
+
+	details := data.Details
+	if details == nil {
+		details = map[string]any{}
+	}
+
+	payload, err := json.Marshal(details)
+	if err != nil {
+		wErr := fmt.Errorf("failed to create admin audit log: %w", err)
+		return ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	query := `
+		INSERT INTO admin_audit_logs (admin_id, target_id, action, details, user_agent, ip_address)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if err := r.database.Execute(ctx, query, adminID, data.TargetID, data.Action, payload, data.UserAgent, data.IPAddress); err != nil {
+		wErr := fmt.Errorf("failed to create admin audit log: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
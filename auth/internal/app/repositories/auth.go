@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
@@ -20,8 +22,21 @@ type AuthRepository interface {
 	GetForOAuth(ctx context.Context, email string) (exists bool, auth *entities.Auth, err error)
 	UpdateEmail(ctx context.Context, authID int64, email string) (updatedAuth *entities.Auth, err error)
 	UpdatePassword(ctx context.Context, authID int64, password string) (err error)
+	UpdatePasswordHash(ctx context.Context, authID int64, oldHash, newHash string) (err error)
 	SetVerified(ctx context.Context, authID int64) (verifiedAuth *entities.Auth, err error)
 	Exists(ctx context.Context, email string) (exists bool, err error)
+	ScheduleDeletion(ctx context.Context, authID int64, scheduledAt time.Time) (deletion *entities.AccountDeletion, err error)
+	GetDeletion(ctx context.Context, authID int64) (deletion *entities.AccountDeletion, err error)
+	CancelDeletion(ctx context.Context, authID int64) (err error)
+	GetDueForDeletion(ctx context.Context, before time.Time) (isDue bool, authID int64, err error)
+	SoftDelete(ctx context.Context, authID int64) (err error)
+	Search(ctx context.Context, filter *entities.AccountFilter) (accounts []entities.Account, total int64, err error)
+	GetAccount(ctx context.Context, authID int64) (account *entities.Account, err error)
+	Suspend(ctx context.Context, authID int64, reason string) (err error)
+	Unsuspend(ctx context.Context, authID int64) (err error)
+	RequirePasswordReset(ctx context.Context, authID int64) (err error)
+	Approve(ctx context.Context, authID int64) (approvedAuth *entities.Auth, err error)
+	SetRole(ctx context.Context, email string, roleID int16) (authID int64, err error)
 }
 
 type authRepository struct {
@@ -37,17 +52,17 @@ func (r *authRepository) Create(ctx context.Context, data *entities.CreateAuth)
 	defer span.End()
 
 	query := `
-		INSERT INTO auth (email, password, role)
-		VALUES ($1, $2, $3)
-		RETURNING auth_id, email, role, is_verified, created_at, updated_at
+		INSERT INTO auth (email, password, role, is_approved)
+		VALUES ($1, $2, $3, $4)
+		RETURNING auth_id, email, role, is_verified, is_approved, created_at, updated_at
 	`
 
-	row := r.database.QueryRow(ctx, query, data.Email, data.Password, data.RoleID)
+	row := r.database.QueryRow(ctx, query, data.Email, data.Password, data.RoleID, data.IsApproved)
 
 	var auth entities.Auth
 	err := row.Scan(
 		&auth.ID, &auth.Email, &auth.RoleID, &auth.IsVerified,
-		&auth.CreatedAt, &auth.UpdatedAt,
+		&auth.IsApproved, &auth.CreatedAt, &auth.UpdatedAt,
 	)
 	if err != nil {
 		wErr := fmt.Errorf("failed to create auth: %w", err)
@@ -63,8 +78,8 @@ func (r *authRepository) GetByEmail(ctx context.Context, email string) (*entitie
 
 	query := `
 		SELECT
-			auth_id, email, password, role, is_verified,
-			created_at, updated_at
+			auth_id, email, password, role, is_verified, is_approved,
+			suspended_at, password_reset_required, created_at, updated_at
 		FROM auth
 		WHERE email = $1 AND deleted_at IS NULL
 	`
@@ -76,8 +91,8 @@ func (r *authRepository) GetByEmail(ctx context.Context, email string) (*entitie
 
 	var auth entities.Auth
 	err := row.Scan(
-		&auth.ID, &auth.Email, &auth.Password, &auth.RoleID,
-		&auth.IsVerified, &auth.CreatedAt, &auth.UpdatedAt,
+		&auth.ID, &auth.Email, &auth.Password, &auth.RoleID, &auth.IsVerified, &auth.IsApproved,
+		&auth.SuspendedAt, &auth.PasswordResetRequired, &auth.CreatedAt, &auth.UpdatedAt,
 	)
 	if err != nil {
 		wErr := fmt.Errorf("failed to fetch auth by email: %w", err)
@@ -96,8 +111,8 @@ func (r *authRepository) GetByID(ctx context.Context, authID int64) (*entities.A
 
 	query := `
 		SELECT
-			auth_id, email, password, role, is_verified,
-			created_at, updated_at
+			auth_id, email, password, role, is_verified, is_approved,
+			suspended_at, password_reset_required, created_at, updated_at
 		FROM auth
 		WHERE auth_id = $1 AND deleted_at IS NULL
 	`
@@ -109,8 +124,8 @@ func (r *authRepository) GetByID(ctx context.Context, authID int64) (*entities.A
 
 	var auth entities.Auth
 	err := row.Scan(
-		&auth.ID, &auth.Email, &auth.Password, &auth.RoleID,
-		&auth.IsVerified, &auth.CreatedAt, &auth.UpdatedAt,
+		&auth.ID, &auth.Email, &auth.Password, &auth.RoleID, &auth.IsVerified, &auth.IsApproved,
+		&auth.SuspendedAt, &auth.PasswordResetRequired, &auth.CreatedAt, &auth.UpdatedAt,
 	)
 	if err != nil {
 		wErr := fmt.Errorf("failed to fetch auth by id: %w", err)
@@ -129,8 +144,8 @@ func (r *authRepository) GetForOAuth(ctx context.Context, email string) (bool, *
 
 	query := `
 		SELECT
-			auth_id, email, password, role, is_verified,
-			created_at, updated_at
+			auth_id, email, password, role, is_verified, is_approved,
+			suspended_at, password_reset_required, created_at, updated_at
 		FROM auth
 		WHERE email = $1 AND deleted_at IS NULL
 	`
@@ -142,8 +157,8 @@ func (r *authRepository) GetForOAuth(ctx context.Context, email string) (bool, *
 
 	var auth entities.Auth
 	err := row.Scan(
-		&auth.ID, &auth.Email, &auth.Password, &auth.RoleID,
-		&auth.IsVerified, &auth.CreatedAt, &auth.UpdatedAt,
+		&auth.ID, &auth.Email, &auth.Password, &auth.RoleID, &auth.IsVerified, &auth.IsApproved,
+		&auth.SuspendedAt, &auth.PasswordResetRequired, &auth.CreatedAt, &auth.UpdatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, ce.ErrDBQueryNoRows) {
@@ -164,7 +179,7 @@ func (r *authRepository) UpdateEmail(ctx context.Context, authID int64, email st
 		UPDATE auth
 		SET email = $1, email_changed_at = NOW(), updated_at = NOW()
 		WHERE auth_id = $2 AND deleted_at IS NULL
-		RETURNING auth_id, email, role, is_verified, created_at, updated_at
+		RETURNING auth_id, email, role, is_verified, is_approved, created_at, updated_at
 	`
 
 	row := r.database.QueryRow(ctx, query, email, authID)
@@ -172,7 +187,7 @@ func (r *authRepository) UpdateEmail(ctx context.Context, authID int64, email st
 	var auth entities.Auth
 	err := row.Scan(
 		&auth.ID, &auth.Email, &auth.RoleID, &auth.IsVerified,
-		&auth.CreatedAt, &auth.UpdatedAt,
+		&auth.IsApproved, &auth.CreatedAt, &auth.UpdatedAt,
 	)
 	if err != nil {
 		wErr := fmt.Errorf("failed to update email: %w", err)
@@ -191,7 +206,9 @@ func (r *authRepository) UpdatePassword(ctx context.Context, authID int64, passw
 
 	query := `
 		UPDATE auth
-		SET password = $1, password_changed_at = NOW(), updated_at = NOW()
+		SET
+			password = $1, password_changed_at = NOW(),
+			password_reset_required = FALSE, updated_at = NOW()
 		WHERE auth_id = $2 AND deleted_at IS NULL
 	`
 
@@ -205,6 +222,28 @@ func (r *authRepository) UpdatePassword(ctx context.Context, authID int64, passw
 	return nil
 }
 
+func (r *authRepository) UpdatePasswordHash(ctx context.Context, authID int64, oldHash, newHash string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "UpdatePasswordHash")
+	defer span.End()
+
+	// same password, new hash: password_changed_at stays so sessions are not invalidated
+	query := `
+		UPDATE auth
+		SET password = $1, updated_at = NOW()
+		WHERE auth_id = $2 AND password = $3 AND deleted_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, newHash, authID, oldHash); err != nil {
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			// password was changed in the meantime, nothing to upgrade
+			return nil
+		}
+		wErr := fmt.Errorf("failed to update password hash: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
 func (r *authRepository) SetVerified(ctx context.Context, authID int64) (*entities.Auth, error) {
 	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "SetVerified")
 	defer span.End()
@@ -213,7 +252,7 @@ func (r *authRepository) SetVerified(ctx context.Context, authID int64) (*entiti
 		UPDATE auth
 		SET is_verified = TRUE, updated_at = NOW()
 		WHERE auth_id = $1 AND is_verified = FALSE AND deleted_at IS NULL
-		RETURNING auth_id, email, role, is_verified, created_at, updated_at
+		RETURNING auth_id, email, role, is_verified, is_approved, created_at, updated_at
 	`
 
 	row := r.database.QueryRow(ctx, query, authID)
@@ -221,7 +260,7 @@ func (r *authRepository) SetVerified(ctx context.Context, authID int64) (*entiti
 	var auth entities.Auth
 	err := row.Scan(
 		&auth.ID, &auth.Email, &auth.RoleID, &auth.IsVerified,
-		&auth.CreatedAt, &auth.UpdatedAt,
+		&auth.IsApproved, &auth.CreatedAt, &auth.UpdatedAt,
 	)
 	if err != nil {
 		wErr := fmt.Errorf("failed to set auth verified: %w", err)
@@ -256,3 +295,347 @@ func (r *authRepository) Exists(ctx context.Context, email string) (bool, error)
 
 	return true, nil
 }
+
+func (r *authRepository) ScheduleDeletion(ctx context.Context, authID int64, scheduledAt time.Time) (*entities.AccountDeletion, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "ScheduleDeletion")
+	defer span.End()
+
+	query := `
+		UPDATE auth
+		SET deletion_requested_at = NOW(), deletion_scheduled_at = $1, updated_at = NOW()
+		WHERE auth_id = $2 AND deletion_scheduled_at IS NULL AND deleted_at IS NULL
+		RETURNING deletion_requested_at, deletion_scheduled_at
+	`
+
+	row := r.database.QueryRow(ctx, query, scheduledAt, authID)
+
+	var deletion entities.AccountDeletion
+	if err := row.Scan(&deletion.RequestedAt, &deletion.ScheduledAt); err != nil {
+		wErr := fmt.Errorf("failed to schedule account deletion: %w", err)
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return nil, ce.NewError(span, ce.CodeAccountDeletionExists, ce.MsgAccountDeletionScheduled, wErr)
+		}
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return &deletion, nil
+}
+
+func (r *authRepository) GetDeletion(ctx context.Context, authID int64) (*entities.AccountDeletion, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "GetDeletion")
+	defer span.End()
+
+	query := `
+		SELECT deletion_requested_at, deletion_scheduled_at
+		FROM auth
+		WHERE auth_id = $1 AND deletion_scheduled_at IS NOT NULL AND deleted_at IS NULL
+	`
+
+	row := r.database.QueryRow(ctx, query, authID)
+
+	var deletion entities.AccountDeletion
+	if err := row.Scan(&deletion.RequestedAt, &deletion.ScheduledAt); err != nil {
+		wErr := fmt.Errorf("failed to fetch account deletion: %w", err)
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return nil, ce.NewError(span, ce.CodeAccountDeletionNotFound, ce.MsgAccountDeletionNotFound, wErr)
+		}
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return &deletion, nil
+}
+
+func (r *authRepository) CancelDeletion(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "CancelDeletion")
+	defer span.End()
+
+	query := `
+		UPDATE auth
+		SET deletion_requested_at = NULL, deletion_scheduled_at = NULL, updated_at = NOW()
+		WHERE auth_id = $1 AND deletion_scheduled_at IS NOT NULL AND deleted_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, authID); err != nil {
+		wErr := fmt.Errorf("failed to cancel account deletion: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodeAccountDeletionNotFound, ce.MsgAccountDeletionNotFound, wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *authRepository) GetDueForDeletion(ctx context.Context, before time.Time) (bool, int64, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "GetDueForDeletion")
+	defer span.End()
+
+	// rows held by another instance are skipped, so no account is erased twice
+	query := `
+		SELECT auth_id
+		FROM auth
+		WHERE deletion_scheduled_at <= $1 AND deleted_at IS NULL
+		ORDER BY deletion_scheduled_at ASC
+		LIMIT 1
+	`
+	if r.database.InTx(ctx) {
+		query += " FOR UPDATE SKIP LOCKED"
+	}
+
+	row := r.database.QueryRow(ctx, query, before)
+
+	var authID int64
+	if err := row.Scan(&authID); err != nil {
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return false, 0, nil
+		}
+		wErr := fmt.Errorf("failed to fetch auth due for deletion: %w", err)
+		return false, 0, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return true, authID, nil
+}
+
+func (r *authRepository) SoftDelete(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "SoftDelete")
+	defer span.End()
+
+	// the row stays for the records referencing it, but nothing in it points to the person anymore
+	query := `
+		UPDATE auth
+		SET
+			email = 'deleted-' || auth_id || '@deleted.invalid', password = NULL,
+			deletion_scheduled_at = NULL, deleted_at = NOW(), updated_at = NOW()
+		WHERE auth_id = $1 AND deleted_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, authID); err != nil {
+		wErr := fmt.Errorf("failed to delete auth: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodeAuthNotFound, ce.MsgInvalidCredentials, wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *authRepository) Search(ctx context.Context, filter *entities.AccountFilter) ([]entities.Account, int64, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "Search")
+	defer span.End()
+
+	conditions := []string{"deleted_at IS NULL"}
+	args := []any{}
+	if filter.Email != "" {
+		args = append(args, filter.Email)
+		conditions = append(conditions, fmt.Sprintf("email ILIKE '%%' || $%d || '%%'", len(args)))
+	}
+	if filter.RoleID != nil {
+		args = append(args, *filter.RoleID)
+		conditions = append(conditions, fmt.Sprintf("role = $%d", len(args)))
+	}
+	if filter.IsVerified != nil {
+		args = append(args, *filter.IsVerified)
+		conditions = append(conditions, fmt.Sprintf("is_verified = $%d", len(args)))
+	}
+	if filter.IsApproved != nil {
+		args = append(args, *filter.IsApproved)
+		conditions = append(conditions, fmt.Sprintf("is_approved = $%d", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	row := r.database.QueryRow(ctx, "SELECT COUNT(*) FROM auth WHERE "+where, args...)
+
+	var total int64
+	if err := row.Scan(&total); err != nil {
+		wErr := fmt.Errorf("failed to count accounts: %w", err)
+		return nil, 0, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT
+			auth_id, email, role, is_verified, is_approved, password IS NOT NULL,
+			suspended_at, suspension_reason, password_reset_required,
+			deletion_scheduled_at, created_at, updated_at
+		FROM auth
+		WHERE %s
+		ORDER BY auth_id ASC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := r.database.QueryAll(ctx, query, args...)
+	if err != nil {
+		wErr := fmt.Errorf("failed to search accounts: %w", err)
+		return nil, 0, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	defer rows.Close()
+
+	accounts := make([]entities.Account, 0)
+	for rows.Next() {
+		var account entities.Account
+		err := rows.Scan(
+			&account.ID, &account.Email, &account.RoleID, &account.IsVerified, &account.IsApproved, &account.HasPassword,
+			&account.SuspendedAt, &account.SuspensionReason, &account.PasswordResetRequired,
+			&account.DeletionScheduledAt, &account.CreatedAt, &account.UpdatedAt,
+		)
+		if err != nil {
+			wErr := fmt.Errorf("failed to search accounts: %w", err)
+			return nil, 0, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+		}
+		accounts = append(accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		wErr := fmt.Errorf("failed to search accounts: %w", err)
+		return nil, 0, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return accounts, total, nil
+}
+
+func (r *authRepository) GetAccount(ctx context.Context, authID int64) (*entities.Account, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "GetAccount")
+	defer span.End()
+
+	query := `
+		SELECT
+			auth_id, email, role, is_verified, is_approved, password IS NOT NULL,
+			suspended_at, suspension_reason, password_reset_required,
+			deletion_scheduled_at, created_at, updated_at
+		FROM auth
+		WHERE auth_id = $1 AND deleted_at IS NULL
+	`
+	if r.database.InTx(ctx) {
+		query += " FOR UPDATE"
+	}
+
+	row := r.database.QueryRow(ctx, query, authID)
+
+	var account entities.Account
+	err := row.Scan(
+		&account.ID, &account.Email, &account.RoleID, &account.IsVerified, &account.IsApproved, &account.HasPassword,
+		&account.SuspendedAt, &account.SuspensionReason, &account.PasswordResetRequired,
+		&account.DeletionScheduledAt, &account.CreatedAt, &account.UpdatedAt,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch account: %w", err)
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return nil, ce.NewError(span, ce.CodeAccountNotFound, ce.MsgAccountNotFound, wErr)
+		}
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return &account, nil
+}
+
+func (r *authRepository) Suspend(ctx context.Context, authID int64, reason string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "Suspend")
+	defer span.End()
+
+	query := `
+		UPDATE auth
+		SET suspended_at = NOW(), suspension_reason = $1, updated_at = NOW()
+		WHERE auth_id = $2 AND suspended_at IS NULL AND deleted_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, reason, authID); err != nil {
+		wErr := fmt.Errorf("failed to suspend auth: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodeAccountSuspendState, "Account is already suspended", wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *authRepository) Unsuspend(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "Unsuspend")
+	defer span.End()
+
+	query := `
+		UPDATE auth
+		SET suspended_at = NULL, suspension_reason = NULL, updated_at = NOW()
+		WHERE auth_id = $1 AND suspended_at IS NOT NULL AND deleted_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, authID); err != nil {
+		wErr := fmt.Errorf("failed to unsuspend auth: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodeAccountSuspendState, "Account is not suspended", wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *authRepository) RequirePasswordReset(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "RequirePasswordReset")
+	defer span.End()
+
+	query := `
+		UPDATE auth
+		SET password_reset_required = TRUE, updated_at = NOW()
+		WHERE auth_id = $1 AND deleted_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, authID); err != nil {
+		wErr := fmt.Errorf("failed to require password reset: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodeAccountNotFound, ce.MsgAccountNotFound, wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *authRepository) Approve(ctx context.Context, authID int64) (*entities.Auth, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "Approve")
+	defer span.End()
+
+	query := `
+		UPDATE auth
+		SET is_approved = TRUE, updated_at = NOW()
+		WHERE auth_id = $1 AND is_approved = FALSE AND deleted_at IS NULL
+		RETURNING auth_id, email, role, is_verified, is_approved, created_at, updated_at
+	`
+
+	row := r.database.QueryRow(ctx, query, authID)
+
+	var auth entities.Auth
+	err := row.Scan(
+		&auth.ID, &auth.Email, &auth.RoleID, &auth.IsVerified,
+		&auth.IsApproved, &auth.CreatedAt, &auth.UpdatedAt,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to approve auth: %w", err)
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return nil, ce.NewError(span, ce.CodeAccountApproved, "Account is already approved", wErr)
+		}
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return &auth, nil
+}
+
+func (r *authRepository) SetRole(ctx context.Context, email string, roleID int16) (int64, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "SetRole")
+	defer span.End()
+
+	query := `
+		UPDATE auth
+		SET role = $1, updated_at = NOW()
+		WHERE email = $2 AND deleted_at IS NULL
+		RETURNING auth_id
+	`
+
+	row := r.database.QueryRow(ctx, query, roleID, email)
+
+	var authID int64
+	if err := row.Scan(&authID); err != nil {
+		wErr := fmt.Errorf("failed to set auth role: %w", err)
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return 0, ce.NewError(span, ce.CodeAccountNotFound, ce.MsgAccountNotFound, wErr)
+		}
+		return 0, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return authID, nil
+}
@@ -0,0 +1,200 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"go.opentelemetry.io/otel"
+)
+
+const mfaErrorTracer string = "repository.mfa"
+
+type MFARepository interface {
+	Upsert(ctx context.Context, authID int64, secret string) (err error)
+	GetByAuthID(ctx context.Context, authID int64) (mfa *entities.MFA, err error)
+	Enable(ctx context.Context, authID int64) (err error)
+	CreateRecoveryCodes(ctx context.Context, authID int64, codeHashes []string) (err error)
+	UseRecoveryCode(ctx context.Context, authID int64, codeHash string) (err error)
+	IsEnabled(ctx context.Context, authID int64) (isEnabled bool, err error)
+	DeleteAllByAuthID(ctx context.Context, authID int64) (err error)
+}
+
+type mfaRepository struct {
+	database *database.Database
+}
+
+func NewMFARepository(database *database.Database) MFARepository {
+	return &mfaRepository{database}
+}
+
+func (r *mfaRepository) Upsert(ctx context.Context, authID int64, secret string) error {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "Upsert")
+	defer span.End()
+
+	// an enabled mfa must be disabled before it can be re-enrolled
+	query := `
+		INSERT INTO mfa (auth_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (auth_id) DO UPDATE
+		SET secret = EXCLUDED.secret, updated_at = NOW()
+		WHERE mfa.is_enabled = FALSE
+	`
+
+	if err := r.database.Execute(ctx, query, authID, secret); err != nil {
+		wErr := fmt.Errorf("failed to upsert mfa: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodeMFAEnabled, "Two-factor authentication is already enabled", wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *mfaRepository) GetByAuthID(ctx context.Context, authID int64) (*entities.MFA, error) {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "GetByAuthID")
+	defer span.End()
+
+	query := `
+		SELECT
+			mfa_id, auth_id, secret, is_enabled, enabled_at,
+			created_at, updated_at
+		FROM mfa
+		WHERE auth_id = $1
+	`
+	if r.database.InTx(ctx) {
+		query += " FOR UPDATE"
+	}
+
+	row := r.database.QueryRow(ctx, query, authID)
+
+	var mfa entities.MFA
+	err := row.Scan(
+		&mfa.ID, &mfa.AuthID, &mfa.Secret, &mfa.IsEnabled,
+		&mfa.EnabledAt, &mfa.CreatedAt, &mfa.UpdatedAt,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch mfa by auth id: %w", err)
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return nil, ce.NewError(span, ce.CodeMFANotEnrolled, "Two-factor authentication is not enrolled", wErr)
+		}
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return &mfa, nil
+}
+
+func (r *mfaRepository) Enable(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "Enable")
+	defer span.End()
+
+	query := `
+		UPDATE mfa
+		SET is_enabled = TRUE, enabled_at = NOW(), updated_at = NOW()
+		WHERE auth_id = $1 AND is_enabled = FALSE
+	`
+
+	if err := r.database.Execute(ctx, query, authID); err != nil {
+		wErr := fmt.Errorf("failed to enable mfa: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodeMFAEnabled, "Two-factor authentication is already enabled", wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *mfaRepository) CreateRecoveryCodes(ctx context.Context, authID int64, codeHashes []string) error {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "CreateRecoveryCodes")
+	defer span.End()
+
+	// previously issued codes are replaced entirely
+	deleteQuery := "DELETE FROM mfa_recovery_codes WHERE auth_id = $1"
+	if err := r.database.Execute(ctx, deleteQuery, authID); err != nil && !errors.Is(err, ce.ErrDBAffectNoRows) {
+		wErr := fmt.Errorf("failed to create recovery codes: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	values := make([]string, 0, len(codeHashes))
+	args := make([]any, 0, len(codeHashes)+1)
+	args = append(args, authID)
+	for i, codeHash := range codeHashes {
+		values = append(values, fmt.Sprintf("($1, $%d)", i+2))
+		args = append(args, codeHash)
+	}
+
+	query := `
+		INSERT INTO mfa_recovery_codes (auth_id, code_hash)
+		VALUES ` + strings.Join(values, ", ")
+
+	if err := r.database.Execute(ctx, query, args...); err != nil {
+		wErr := fmt.Errorf("failed to create recovery codes: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, authID int64, codeHash string) error {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "UseRecoveryCode")
+	defer span.End()
+
+	query := `
+		UPDATE mfa_recovery_codes
+		SET used_at = NOW()
+		WHERE auth_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, authID, codeHash); err != nil {
+		wErr := fmt.Errorf("failed to use recovery code: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodeMFAInvalidCode, ce.MsgInvalidMFACode, wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *mfaRepository) IsEnabled(ctx context.Context, authID int64) (bool, error) {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "IsEnabled")
+	defer span.End()
+
+	query := `
+		SELECT 1 FROM mfa
+		WHERE auth_id = $1 AND is_enabled = TRUE
+	`
+
+	row := r.database.QueryRow(ctx, query, authID)
+
+	var exists int
+	if err := row.Scan(&exists); err != nil {
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return false, nil
+		}
+		wErr := fmt.Errorf("failed to check mfa status: %w", err)
+		return false, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return true, nil
+}
+
+func (r *mfaRepository) DeleteAllByAuthID(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "DeleteAllByAuthID")
+	defer span.End()
+
+	// having nothing to delete is fine, not every account has mfa set up
+	queries := []string{
+		"DELETE FROM mfa_recovery_codes WHERE auth_id = $1",
+		"DELETE FROM mfa WHERE auth_id = $1",
+	}
+	for _, query := range queries {
+		if err := r.database.Execute(ctx, query, authID); err != nil && !errors.Is(err, ce.ErrDBAffectNoRows) {
+			wErr := fmt.Errorf("failed to delete mfa: %w", err)
+			return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
@@ -14,6 +15,10 @@ const oAuthErrorTracer string = "repository.oauth"
 
 type OAuthRepository interface {
 	Create(ctx context.Context, authID int64, data *entities.OAuth) (err error)
+	GetAuthIDByProviderUID(ctx context.Context, provider int16, uid string) (exists bool, authID int64, err error)
+	GetAllByAuthID(ctx context.Context, authID int64) (identities []entities.OAuthIdentity, err error)
+	Delete(ctx context.Context, authID int64, provider int16) (err error)
+	DeleteAllByAuthID(ctx context.Context, authID int64) (err error)
 }
 
 type oAuthRepository struct {
@@ -39,3 +44,103 @@ func (r *oAuthRepository) Create(ctx context.Context, authID int64, data *entiti
 	}
 	return nil
 }
+
+func (r *oAuthRepository) GetAuthIDByProviderUID(ctx context.Context, provider int16, uid string) (bool, int64, error) {
+	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "GetAuthIDByProviderUID")
+	defer span.End()
+
+	query := `
+		SELECT auth_id
+		FROM oauth
+		WHERE provider = $1 AND provider_uid = $2 AND deleted_at IS NULL
+	`
+
+	row := r.database.QueryRow(ctx, query, provider, uid)
+
+	var authID int64
+	if err := row.Scan(&authID); err != nil {
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return false, 0, nil
+		}
+		wErr := fmt.Errorf("failed to fetch oauth by provider uid: %w", err)
+		return false, 0, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return true, authID, nil
+}
+
+func (r *oAuthRepository) GetAllByAuthID(ctx context.Context, authID int64) ([]entities.OAuthIdentity, error) {
+	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "GetAllByAuthID")
+	defer span.End()
+
+	query := `
+		SELECT provider, provider_uid, created_at
+		FROM oauth
+		WHERE auth_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+	if r.database.InTx(ctx) {
+		query += " FOR UPDATE"
+	}
+
+	rows, err := r.database.QueryAll(ctx, query, authID)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch oauth identities by auth id: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	defer rows.Close()
+
+	identities := make([]entities.OAuthIdentity, 0)
+	for rows.Next() {
+		var identity entities.OAuthIdentity
+		if err := rows.Scan(&identity.Provider, &identity.UID, &identity.CreatedAt); err != nil {
+			wErr := fmt.Errorf("failed to fetch oauth identities by auth id: %w", err)
+			return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+		}
+		identities = append(identities, identity)
+	}
+	if err := rows.Err(); err != nil {
+		wErr := fmt.Errorf("failed to fetch oauth identities by auth id: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return identities, nil
+}
+
+func (r *oAuthRepository) Delete(ctx context.Context, authID int64, provider int16) error {
+	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "Delete")
+	defer span.End()
+
+	query := `
+		UPDATE oauth
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE auth_id = $1 AND provider = $2 AND deleted_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, authID, provider); err != nil {
+		wErr := fmt.Errorf("failed to delete oauth: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodeOAuthIdentityNotFound, "Identity not found", wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *oAuthRepository) DeleteAllByAuthID(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "DeleteAllByAuthID")
+	defer span.End()
+
+	// the provider uid is cleared so the identity can neither sign in nor be traced back
+	query := `
+		UPDATE oauth
+		SET provider_uid = '', deleted_at = NOW(), updated_at = NOW()
+		WHERE auth_id = $1 AND deleted_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, authID); err != nil && !errors.Is(err, ce.ErrDBAffectNoRows) {
+		wErr := fmt.Errorf("failed to delete oauth: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"go.opentelemetry.io/otel"
+)
+
+const outboxErrorTracer string = "repository.outbox"
+
+// arbitrary but fixed, identifies the relay's advisory lock
+const outboxRelayLockID int64 = 7_010_001
+
+type OutboxRepository interface {
+	Create(ctx context.Context, data *entities.CreateOutboxMessage) (err error)
+	TryLockRelay(ctx context.Context) (isLocked bool, err error)
+	ClaimPending(ctx context.Context, limit int, claimedUntil time.Time) (messages []entities.OutboxMessage, err error)
+	MarkSent(ctx context.Context, outboxID int64) (err error)
+	MarkFailed(ctx context.Context, outboxID int64, lastError string, retryAt time.Time) (err error)
+	Park(ctx context.Context, outboxID int64, lastError string) (err error)
+	DeleteSent(ctx context.Context, before time.Time) (err error)
+}
+
+type outboxRepository struct {
+	database *database.Database
+}
+
+func NewOutboxRepository(database *database.Database) OutboxRepository {
+	return &outboxRepository{database}
+}
+
+func (r *outboxRepository) Create(ctx context.Context, data *entities.CreateOutboxMessage) error {
+	ctx, span := otel.Tracer(outboxErrorTracer).Start(ctx, "Create")
+	defer span.End()
+
+	query := `
+		INSERT INTO outbox (
+			event_id, event_type, topic, message_key,
+			payload, trace_id, correlation_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	err := r.database.Execute(
+		ctx, query,
+		data.EventID, data.EventType, data.Topic, data.Key,
+		data.Payload, data.TraceID, data.CorrelationID,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to create outbox message: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (r *outboxRepository) TryLockRelay(ctx context.Context) (bool, error) {
+	ctx, span := otel.Tracer(outboxErrorTracer).Start(ctx, "TryLockRelay")
+	defer span.End()
+
+	// released on commit/rollback, only one instance relays at a time so per-key order holds
+	query := "SELECT pg_try_advisory_xact_lock($1)"
+
+	var isLocked bool
+	if err := r.database.QueryRow(ctx, query, outboxRelayLockID).Scan(&isLocked); err != nil {
+		wErr := fmt.Errorf("failed to lock outbox relay: %w", err)
+		return false, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return isLocked, nil
+}
+
+func (r *outboxRepository) ClaimPending(ctx context.Context, limit int, claimedUntil time.Time) ([]entities.OutboxMessage, error) {
+	ctx, span := otel.Tracer(outboxErrorTracer).Start(ctx, "ClaimPending")
+	defer span.End()
+
+	// a message waits while an earlier one with the same key is backing off or claimed,
+	// claiming pushes available_at out so the batch can be published outside the transaction
+	query := `
+		WITH claimed AS (
+			UPDATE outbox
+			SET available_at = $2
+			WHERE outbox_id IN (
+				SELECT o.outbox_id
+				FROM outbox o
+				WHERE o.sent_at IS NULL AND o.parked_at IS NULL AND o.available_at <= NOW()
+				AND NOT EXISTS (
+					SELECT 1 FROM outbox p
+					WHERE p.message_key = o.message_key
+					AND p.sent_at IS NULL
+					AND p.parked_at IS NULL
+					AND p.outbox_id < o.outbox_id
+					AND p.available_at > NOW()
+				)
+				ORDER BY o.outbox_id ASC
+				LIMIT $1
+			)
+			RETURNING
+				outbox_id, event_id, event_type, topic, message_key,
+				payload, trace_id, correlation_id, attempts, created_at
+		)
+		SELECT * FROM claimed ORDER BY outbox_id ASC
+	`
+
+	rows, err := r.database.QueryAll(ctx, query, limit, claimedUntil)
+	if err != nil {
+		wErr := fmt.Errorf("failed to claim pending outbox messages: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	defer rows.Close()
+
+	messages := make([]entities.OutboxMessage, 0)
+	for rows.Next() {
+		var message entities.OutboxMessage
+		err := rows.Scan(
+			&message.ID, &message.EventID, &message.EventType, &message.Topic, &message.Key,
+			&message.Payload, &message.TraceID, &message.CorrelationID, &message.Attempts, &message.CreatedAt,
+		)
+		if err != nil {
+			wErr := fmt.Errorf("failed to claim pending outbox messages: %w", err)
+			return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		wErr := fmt.Errorf("failed to claim pending outbox messages: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return messages, nil
+}
+
+func (r *outboxRepository) MarkSent(ctx context.Context, outboxID int64) error {
+	ctx, span := otel.Tracer(outboxErrorTracer).Start(ctx, "MarkSent")
+	defer span.End()
+
+	query := `
+		UPDATE outbox
+		SET sent_at = NOW(), attempts = attempts + 1, last_error = NULL
+		WHERE outbox_id = $1
+	`
+
+	if err := r.database.Execute(ctx, query, outboxID); err != nil {
+		wErr := fmt.Errorf("failed to mark outbox message sent: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, outboxID int64, lastError string, retryAt time.Time) error {
+	ctx, span := otel.Tracer(outboxErrorTracer).Start(ctx, "MarkFailed")
+	defer span.End()
+
+	query := `
+		UPDATE outbox
+		SET attempts = attempts + 1, last_error = $1, available_at = $2
+		WHERE outbox_id = $3
+	`
+
+	if err := r.database.Execute(ctx, query, lastError, retryAt, outboxID); err != nil {
+		wErr := fmt.Errorf("failed to mark outbox message failed: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *outboxRepository) Park(ctx context.Context, outboxID int64, lastError string) error {
+	ctx, span := otel.Tracer(outboxErrorTracer).Start(ctx, "Park")
+	defer span.End()
+
+	query := `
+		UPDATE outbox
+		SET attempts = attempts + 1, last_error = $1, parked_at = NOW()
+		WHERE outbox_id = $2
+	`
+
+	if err := r.database.Execute(ctx, query, lastError, outboxID); err != nil {
+		wErr := fmt.Errorf("failed to park outbox message: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *outboxRepository) DeleteSent(ctx context.Context, before time.Time) error {
+	ctx, span := otel.Tracer(outboxErrorTracer).Start(ctx, "DeleteSent")
+	defer span.End()
+
+	query := "DELETE FROM outbox WHERE sent_at IS NOT NULL AND sent_at <= $1"
+
+	if err := r.database.Execute(ctx, query, before); err != nil {
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return nil
+		}
+		wErr := fmt.Errorf("failed to delete sent outbox messages: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
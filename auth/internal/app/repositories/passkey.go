@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"go.opentelemetry.io/otel"
+)
+
+const passkeyErrorTracer string = "repository.passkey"
+
+type PasskeyRepository interface {
+	Create(ctx context.Context, authID int64, data *entities.CreatePasskey) (createdPasskey *entities.Passkey, err error)
+	GetAllByAuthID(ctx context.Context, authID int64) (passkeys []entities.Passkey, err error)
+	GetByCredentialID(ctx context.Context, credentialID []byte) (passkey *entities.Passkey, err error)
+	UpdateCredential(ctx context.Context, passkeyID int64, credential []byte) (err error)
+	UpdateName(ctx context.Context, authID, passkeyID int64, name string) (updatedPasskey *entities.Passkey, err error)
+	Delete(ctx context.Context, authID, passkeyID int64) (err error)
+	DeleteAllByAuthID(ctx context.Context, authID int64) (err error)
+}
+
+type passkeyRepository struct {
+	database *database.Database
+}
+
+func NewPasskeyRepository(database *database.Database) PasskeyRepository {
+	return &passkeyRepository{database}
+}
+
+func (r *passkeyRepository) Create(ctx context.Context, authID int64, data *entities.CreatePasskey) (*entities.Passkey, error) {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "Create")
+	defer span.End()
+
+	query := `
+		INSERT INTO passkeys (auth_id, credential_id, credential, name)
+		VALUES ($1, $2, $3, $4)
+		RETURNING passkey_id, auth_id, name, last_used_at, created_at, updated_at
+	`
+
+	row := r.database.QueryRow(ctx, query, authID, data.CredentialID, data.Credential, data.Name)
+
+	var passkey entities.Passkey
+	err := row.Scan(
+		&passkey.ID, &passkey.AuthID, &passkey.Name,
+		&passkey.LastUsedAt, &passkey.CreatedAt, &passkey.UpdatedAt,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to create passkey: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return &passkey, nil
+}
+
+func (r *passkeyRepository) GetAllByAuthID(ctx context.Context, authID int64) ([]entities.Passkey, error) {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "GetAllByAuthID")
+	defer span.End()
+
+	query := `
+		SELECT
+			passkey_id, auth_id, credential_id, credential, name,
+			last_used_at, created_at, updated_at
+		FROM passkeys
+		WHERE auth_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.database.QueryAll(ctx, query, authID)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch passkeys by auth id: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	defer rows.Close()
+
+	passkeys := make([]entities.Passkey, 0)
+	for rows.Next() {
+		var passkey entities.Passkey
+		err := rows.Scan(
+			&passkey.ID, &passkey.AuthID, &passkey.CredentialID, &passkey.Credential,
+			&passkey.Name, &passkey.LastUsedAt, &passkey.CreatedAt, &passkey.UpdatedAt,
+		)
+		if err != nil {
+			wErr := fmt.Errorf("failed to fetch passkeys by auth id: %w", err)
+			return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+		}
+		passkeys = append(passkeys, passkey)
+	}
+	if err := rows.Err(); err != nil {
+		wErr := fmt.Errorf("failed to fetch passkeys by auth id: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return passkeys, nil
+}
+
+func (r *passkeyRepository) GetByCredentialID(ctx context.Context, credentialID []byte) (*entities.Passkey, error) {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "GetByCredentialID")
+	defer span.End()
+
+	query := `
+		SELECT
+			passkey_id, auth_id, credential_id, credential, name,
+			last_used_at, created_at, updated_at
+		FROM passkeys
+		WHERE credential_id = $1
+	`
+	if r.database.InTx(ctx) {
+		query += " FOR UPDATE"
+	}
+
+	row := r.database.QueryRow(ctx, query, credentialID)
+
+	var passkey entities.Passkey
+	err := row.Scan(
+		&passkey.ID, &passkey.AuthID, &passkey.CredentialID, &passkey.Credential,
+		&passkey.Name, &passkey.LastUsedAt, &passkey.CreatedAt, &passkey.UpdatedAt,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch passkey by credential id: %w", err)
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return nil, ce.NewError(span, ce.CodePasskeyNotFound, ce.MsgInvalidCredentials, wErr)
+		}
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return &passkey, nil
+}
+
+func (r *passkeyRepository) UpdateCredential(ctx context.Context, passkeyID int64, credential []byte) error {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "UpdateCredential")
+	defer span.End()
+
+	query := `
+		UPDATE passkeys
+		SET credential = $1, last_used_at = NOW(), updated_at = NOW()
+		WHERE passkey_id = $2
+	`
+
+	if err := r.database.Execute(ctx, query, credential, passkeyID); err != nil {
+		wErr := fmt.Errorf("failed to update passkey credential: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodePasskeyNotFound, ce.MsgInvalidCredentials, wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *passkeyRepository) UpdateName(ctx context.Context, authID, passkeyID int64, name string) (*entities.Passkey, error) {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "UpdateName")
+	defer span.End()
+
+	query := `
+		UPDATE passkeys
+		SET name = $1, updated_at = NOW()
+		WHERE passkey_id = $2 AND auth_id = $3
+		RETURNING passkey_id, auth_id, name, last_used_at, created_at, updated_at
+	`
+
+	row := r.database.QueryRow(ctx, query, name, passkeyID, authID)
+
+	var passkey entities.Passkey
+	err := row.Scan(
+		&passkey.ID, &passkey.AuthID, &passkey.Name,
+		&passkey.LastUsedAt, &passkey.CreatedAt, &passkey.UpdatedAt,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to update passkey name: %w", err)
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return nil, ce.NewError(span, ce.CodePasskeyNotFound, ce.MsgPasskeyNotFound, wErr)
+		}
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return &passkey, nil
+}
+
+func (r *passkeyRepository) Delete(ctx context.Context, authID, passkeyID int64) error {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "Delete")
+	defer span.End()
+
+	query := "DELETE FROM passkeys WHERE passkey_id = $1 AND auth_id = $2"
+
+	if err := r.database.Execute(ctx, query, passkeyID, authID); err != nil {
+		wErr := fmt.Errorf("failed to delete passkey: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodePasskeyNotFound, ce.MsgPasskeyNotFound, wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *passkeyRepository) DeleteAllByAuthID(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "DeleteAllByAuthID")
+	defer span.End()
+
+	query := "DELETE FROM passkeys WHERE auth_id = $1"
+
+	if err := r.database.Execute(ctx, query, authID); err != nil && !errors.Is(err, ce.ErrDBAffectNoRows) {
+		wErr := fmt.Errorf("failed to delete passkeys: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
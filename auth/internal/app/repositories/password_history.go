@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"go.opentelemetry.io/otel"
+)
+
+const passwordHistoryErrorTracer string = "repository.password_history"
+
+type PasswordHistoryRepository interface {
+	Create(ctx context.Context, authID int64, password string) (err error)
+	GetRecent(ctx context.Context, authID int64, limit int) (passwords []string, err error)
+	Prune(ctx context.Context, authID int64, keep int) (err error)
+	DeleteAllByAuthID(ctx context.Context, authID int64) (err error)
+}
+
+type passwordHistoryRepository struct {
+	database *database.Database
+}
+
+func NewPasswordHistoryRepository(database *database.Database) PasswordHistoryRepository {
+	return &passwordHistoryRepository{database}
+}
+
+func (r *passwordHistoryRepository) Create(ctx context.Context, authID int64, password string) error {
+	ctx, span := otel.Tracer(passwordHistoryErrorTracer).Start(ctx, "Create")
+	defer span.End()
+
+	query := "INSERT INTO password_history (auth_id, password) VALUES ($1, $2)"
+
+	if err := r.database.Execute(ctx, query, authID, password); err != nil {
+		wErr := fmt.Errorf("failed to create password history: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *passwordHistoryRepository) GetRecent(ctx context.Context, authID int64, limit int) ([]string, error) {
+	ctx, span := otel.Tracer(passwordHistoryErrorTracer).Start(ctx, "GetRecent")
+	defer span.End()
+
+	query := `
+		SELECT password
+		FROM password_history
+		WHERE auth_id = $1
+		ORDER BY created_at DESC, password_history_id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.database.QueryAll(ctx, query, authID, limit)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch password history: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	defer rows.Close()
+
+	passwords := make([]string, 0, limit)
+	for rows.Next() {
+		var password string
+		if err := rows.Scan(&password); err != nil {
+			wErr := fmt.Errorf("failed to fetch password history: %w", err)
+			return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+		}
+		passwords = append(passwords, password)
+	}
+	if err := rows.Err(); err != nil {
+		wErr := fmt.Errorf("failed to fetch password history: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return passwords, nil
+}
+
+func (r *passwordHistoryRepository) Prune(ctx context.Context, authID int64, keep int) error {
+	ctx, span := otel.Tracer(passwordHistoryErrorTracer).Start(ctx, "Prune")
+	defer span.End()
+
+	query := `
+		DELETE FROM password_history
+		WHERE auth_id = $1 AND password_history_id NOT IN (
+			SELECT password_history_id
+			FROM password_history
+			WHERE auth_id = $1
+			ORDER BY created_at DESC, password_history_id DESC
+			LIMIT $2
+		)
+	`
+
+	if err := r.database.Execute(ctx, query, authID, keep); err != nil && !errors.Is(err, ce.ErrDBAffectNoRows) {
+		wErr := fmt.Errorf("failed to prune password history: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *passwordHistoryRepository) DeleteAllByAuthID(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(passwordHistoryErrorTracer).Start(ctx, "DeleteAllByAuthID")
+	defer span.End()
+
+	query := "DELETE FROM password_history WHERE auth_id = $1"
+
+	if err := r.database.Execute(ctx, query, authID); err != nil && !errors.Is(err, ce.ErrDBAffectNoRows) {
+		wErr := fmt.Errorf("failed to delete password history: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
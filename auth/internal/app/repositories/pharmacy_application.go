@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"go.opentelemetry.io/otel"
+)
+
+const pharmacyApplicationErrorTracer string = "repository.pharmacy_application"
+
+type PharmacyApplicationRepository interface {
+	Create(ctx context.Context, authID int64, data *entities.CreatePharmacyApplication) (err error)
+	GetByAuthID(ctx context.Context, authID int64) (application *entities.PharmacyApplication, err error)
+	IsLicenseRegistered(ctx context.Context, licenseNumber string) (isRegistered bool, err error)
+	MarkReviewed(ctx context.Context, authID, adminID int64) (err error)
+	DeleteByAuthID(ctx context.Context, authID int64) (err error)
+}
+
+type pharmacyApplicationRepository struct {
+	database *database.Database
+}
+
+func NewPharmacyApplicationRepository(database *database.Database) PharmacyApplicationRepository {
+	return &pharmacyApplicationRepository{database}
+}
+
+func (r *pharmacyApplicationRepository) Create(ctx context.Context, authID int64, data *entities.CreatePharmacyApplication) error {
+	ctx, span := otel.Tracer(pharmacyApplicationErrorTracer).Start(ctx, "Create")
+	defer span.End()
+
+	query := `
+		INSERT INTO pharmacy_applications (auth_id, legal_name, license_number, license_authority, license_expiry)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	err := r.database.Execute(ctx, query, authID, data.LegalName, data.LicenseNumber, data.LicenseAuthority, data.LicenseExpiry)
+	if err != nil {
+		wErr := fmt.Errorf("failed to create pharmacy application: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *pharmacyApplicationRepository) GetByAuthID(ctx context.Context, authID int64) (*entities.PharmacyApplication, error) {
+	ctx, span := otel.Tracer(pharmacyApplicationErrorTracer).Start(ctx, "GetByAuthID")
+	defer span.End()
+
+	query := `
+		SELECT
+			auth_id, legal_name, license_number, license_authority, license_expiry,
+			reviewed_by, reviewed_at, created_at, updated_at
+		FROM pharmacy_applications
+		WHERE auth_id = $1
+	`
+
+	row := r.database.QueryRow(ctx, query, authID)
+
+	var application entities.PharmacyApplication
+	err := row.Scan(
+		&application.AuthID, &application.LegalName, &application.LicenseNumber, &application.LicenseAuthority, &application.LicenseExpiry,
+		&application.ReviewedBy, &application.ReviewedAt, &application.CreatedAt, &application.UpdatedAt,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch pharmacy application: %w", err)
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return nil, ce.NewError(span, ce.CodePharmacyAppNotFound, ce.MsgPharmacyAppNotFound, wErr)
+		}
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return &application, nil
+}
+
+func (r *pharmacyApplicationRepository) IsLicenseRegistered(ctx context.Context, licenseNumber string) (bool, error) {
+	ctx, span := otel.Tracer(pharmacyApplicationErrorTracer).Start(ctx, "IsLicenseRegistered")
+	defer span.End()
+
+	query := "SELECT 1 FROM pharmacy_applications WHERE license_number = $1"
+
+	row := r.database.QueryRow(ctx, query, licenseNumber)
+
+	var exists int
+	if err := row.Scan(&exists); err != nil {
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return false, nil
+		}
+		wErr := fmt.Errorf("failed to check license registration: %w", err)
+		return false, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return true, nil
+}
+
+func (r *pharmacyApplicationRepository) MarkReviewed(ctx context.Context, authID, adminID int64) error {
+	ctx, span := otel.Tracer(pharmacyApplicationErrorTracer).Start(ctx, "MarkReviewed")
+	defer span.End()
+
+	query := `
+		UPDATE pharmacy_applications
+		SET reviewed_by = $1, reviewed_at = NOW(), updated_at = NOW()
+		WHERE auth_id = $2
+	`
+
+	if err := r.database.Execute(ctx, query, adminID, authID); err != nil {
+		wErr := fmt.Errorf("failed to mark pharmacy application as reviewed: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodePharmacyAppNotFound, ce.MsgPharmacyAppNotFound, wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *pharmacyApplicationRepository) DeleteByAuthID(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(pharmacyApplicationErrorTracer).Start(ctx, "DeleteByAuthID")
+	defer span.End()
+
+	query := "DELETE FROM pharmacy_applications WHERE auth_id = $1"
+
+	if err := r.database.Execute(ctx, query, authID); err != nil && !errors.Is(err, ce.ErrDBAffectNoRows) {
+		wErr := fmt.Errorf("failed to delete pharmacy application: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"go.opentelemetry.io/otel"
+)
+
+const securityEventErrorTracer string = "repository.security_event"
+
+type SecurityEventRepository interface {
+	Create(ctx context.Context, authID *int64, data *entities.CreateSecurityEvent) (err error)
+	Search(ctx context.Context, filter *entities.SecurityEventFilter) (events []entities.SecurityEvent, err error)
+	AnonymizeByAuthID(ctx context.Context, authID int64, emailHash string) (err error)
+}
+
+type securityEventRepository struct {
+	database *database.Database
+}
+
+func NewSecurityEventRepository(database *database.Database) SecurityEventRepository {
+	return &securityEventRepository{database}
+}
+
+func (r *securityEventRepository) Create(ctx context.Context, authID *int64, data *entities.CreateSecurityEvent) error {
+	ctx, span := otel.Tracer(securityEventErrorTracer).Start(ctx, "Create")
+	defer span.End()
+
+	details := data.Details
+	if details == nil {
+		details = map[string]any{}
+	}
+
+	payload, err := json.Marshal(details)
+	if err != nil {
+		wErr := fmt.Errorf("failed to create security event: %w", err)
+		return ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	query := `
+		INSERT INTO auth_events (auth_id, event_type, details, user_agent, ip_address, request_id, trace_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	err = r.database.Execute(
+		ctx, query,
+		authID, data.EventType, payload, data.UserAgent,
+		data.IPAddress, data.RequestID, data.TraceID,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to create security event: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (r *securityEventRepository) Search(ctx context.Context, filter *entities.SecurityEventFilter) ([]entities.SecurityEvent, error) {
+	ctx, span := otel.Tracer(securityEventErrorTracer).Start(ctx, "Search")
+	defer span.End()
+
+	conditions := []string{"TRUE"}
+	args := []any{}
+	if filter.AuthID != nil {
+		args = append(args, *filter.AuthID)
+		conditions = append(conditions, fmt.Sprintf("auth_id = $%d", len(args)))
+	}
+	if filter.EventType != "" {
+		args = append(args, filter.EventType)
+		conditions = append(conditions, fmt.Sprintf("event_type = $%d", len(args)))
+	}
+	if filter.IPAddress != "" {
+		args = append(args, filter.IPAddress)
+		conditions = append(conditions, fmt.Sprintf("ip_address = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+	if filter.BeforeID > 0 {
+		args = append(args, filter.BeforeID)
+		conditions = append(conditions, fmt.Sprintf("auth_event_id < $%d", len(args)))
+	}
+
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT
+			auth_event_id, auth_id, event_type, details, user_agent,
+			ip_address, request_id, trace_id, created_at
+		FROM auth_events
+		WHERE %s
+		ORDER BY auth_event_id DESC
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), len(args))
+
+	rows, err := r.database.QueryAll(ctx, query, args...)
+	if err != nil {
+		wErr := fmt.Errorf("failed to search security events: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	defer rows.Close()
+
+	events := make([]entities.SecurityEvent, 0)
+	for rows.Next() {
+		var event entities.SecurityEvent
+		var details []byte
+		err := rows.Scan(
+			&event.ID, &event.AuthID, &event.EventType, &details, &event.UserAgent,
+			&event.IPAddress, &event.RequestID, &event.TraceID, &event.CreatedAt,
+		)
+		if err != nil {
+			wErr := fmt.Errorf("failed to search security events: %w", err)
+			return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+		}
+		if err := json.Unmarshal(details, &event.Details); err != nil {
+			wErr := fmt.Errorf("failed to search security events: %w", err)
+			return nil, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		wErr := fmt.Errorf("failed to search security events: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return events, nil
+}
+
+// the log is append-only, the trigger lets this through only inside a transaction
+// that declares the erasure, and only to blank the personal columns
+func (r *securityEventRepository) AnonymizeByAuthID(ctx context.Context, authID int64, emailHash string) error {
+	ctx, span := otel.Tracer(securityEventErrorTracer).Start(ctx, "AnonymizeByAuthID")
+	defer span.End()
+
+	if !r.database.InTx(ctx) {
+		err := fmt.Errorf("failed to anonymize security events: %w", errors.New("not in a transaction"))
+		return ce.NewError(span, ce.CodeDBTransaction, ce.MsgInternalServer, err)
+	}
+
+	var setting string
+	if err := r.database.QueryRow(ctx, "SELECT set_config('auth_events.erasure', 'on', true)").Scan(&setting); err != nil {
+		wErr := fmt.Errorf("failed to anonymize security events: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	// failed sign-ins against the email before it was an account only carry its hash
+	query := `
+		UPDATE auth_events
+		SET user_agent = '', ip_address = '', details = '{}'
+		WHERE auth_id = $1 OR (auth_id IS NULL AND details->>'email_hash' = $2)
+	`
+
+	if err := r.database.Execute(ctx, query, authID, emailHash); err != nil && !errors.Is(err, ce.ErrDBAffectNoRows) {
+		wErr := fmt.Errorf("failed to anonymize security events: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	if err := r.database.QueryRow(ctx, "SELECT set_config('auth_events.erasure', 'off', true)").Scan(&setting); err != nil {
+		wErr := fmt.Errorf("failed to anonymize security events: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
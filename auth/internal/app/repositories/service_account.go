@@ -0,0 +1,284 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"go.opentelemetry.io/otel"
+)
+
+const serviceAccountErrorTracer string = "repository.service_account"
+
+type ServiceAccountRepository interface {
+	Create(ctx context.Context, createdBy *int64, clientID, secretHash string, data *entities.CreateServiceAccount) (serviceAccount *entities.ServiceAccount, err error)
+	GetAll(ctx context.Context) (serviceAccounts []entities.ServiceAccount, err error)
+	GetByID(ctx context.Context, serviceAccountID int64) (serviceAccount *entities.ServiceAccount, err error)
+	GetByClientID(ctx context.Context, clientID string) (serviceAccount *entities.ServiceAccount, err error)
+	RotateSecret(ctx context.Context, serviceAccountID int64, secretHash string, previousExpiresAt time.Time) (err error)
+	GetExpiredPreviousSecrets(ctx context.Context) (serviceAccounts []entities.ServiceAccount, err error)
+	ClearPreviousSecret(ctx context.Context, serviceAccountID int64, previousSecretHash string) (err error)
+	Disable(ctx context.Context, serviceAccountID int64) (err error)
+}
+
+type serviceAccountRepository struct {
+	database *database.Database
+}
+
+func NewServiceAccountRepository(database *database.Database) ServiceAccountRepository {
+	return &serviceAccountRepository{database}
+}
+
+func (r *serviceAccountRepository) Create(ctx context.Context, createdBy *int64, clientID, secretHash string, data *entities.CreateServiceAccount) (*entities.ServiceAccount, error) {
+	ctx, span := otel.Tracer(serviceAccountErrorTracer).Start(ctx, "Create")
+	defer span.End()
+
+	query := `
+		INSERT INTO service_accounts (created_by, client_id, name, secret_hash, scopes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING
+			service_account_id, client_id, name, secret_hash, scopes, previous_secret_hash,
+			previous_secret_expires_at, secret_rotated_at, disabled_at, created_by, created_at, updated_at
+	`
+
+	row := r.database.QueryRow(ctx, query, createdBy, clientID, data.Name, secretHash, strings.Join(data.Scopes, " "))
+
+	var serviceAccount entities.ServiceAccount
+	var scopes string
+	err := row.Scan(
+		&serviceAccount.ID, &serviceAccount.ClientID, &serviceAccount.Name, &serviceAccount.SecretHash,
+		&scopes, &serviceAccount.PreviousSecretHash, &serviceAccount.PreviousSecretExpiresAt,
+		&serviceAccount.SecretRotatedAt, &serviceAccount.DisabledAt, &serviceAccount.CreatedBy,
+		&serviceAccount.CreatedAt, &serviceAccount.UpdatedAt,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to create service account: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	serviceAccount.Scopes = strings.Fields(scopes)
+	return &serviceAccount, nil
+}
+
+func (r *serviceAccountRepository) GetAll(ctx context.Context) ([]entities.ServiceAccount, error) {
+	ctx, span := otel.Tracer(serviceAccountErrorTracer).Start(ctx, "GetAll")
+	defer span.End()
+
+	query := `
+		SELECT
+			service_account_id, client_id, name, secret_hash, scopes, previous_secret_hash,
+			previous_secret_expires_at, secret_rotated_at, disabled_at, created_by, created_at, updated_at
+		FROM service_accounts
+		ORDER BY service_account_id
+	`
+
+	rows, err := r.database.QueryAll(ctx, query)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch all service accounts: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	defer rows.Close()
+
+	serviceAccounts := make([]entities.ServiceAccount, 0)
+	for rows.Next() {
+		var serviceAccount entities.ServiceAccount
+		var scopes string
+		err := rows.Scan(
+			&serviceAccount.ID, &serviceAccount.ClientID, &serviceAccount.Name, &serviceAccount.SecretHash,
+			&scopes, &serviceAccount.PreviousSecretHash, &serviceAccount.PreviousSecretExpiresAt,
+			&serviceAccount.SecretRotatedAt, &serviceAccount.DisabledAt, &serviceAccount.CreatedBy,
+			&serviceAccount.CreatedAt, &serviceAccount.UpdatedAt,
+		)
+		if err != nil {
+			wErr := fmt.Errorf("failed to fetch all service accounts: %w", err)
+			return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+		}
+		serviceAccount.Scopes = strings.Fields(scopes)
+		serviceAccounts = append(serviceAccounts, serviceAccount)
+	}
+	if err := rows.Err(); err != nil {
+		wErr := fmt.Errorf("failed to fetch all service accounts: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return serviceAccounts, nil
+}
+
+func (r *serviceAccountRepository) GetByID(ctx context.Context, serviceAccountID int64) (*entities.ServiceAccount, error) {
+	ctx, span := otel.Tracer(serviceAccountErrorTracer).Start(ctx, "GetByID")
+	defer span.End()
+
+	query := `
+		SELECT
+			service_account_id, client_id, name, secret_hash, scopes, previous_secret_hash,
+			previous_secret_expires_at, secret_rotated_at, disabled_at, created_by, created_at, updated_at
+		FROM service_accounts
+		WHERE service_account_id = $1
+	`
+	if r.database.InTx(ctx) {
+		query += " FOR UPDATE"
+	}
+
+	row := r.database.QueryRow(ctx, query, serviceAccountID)
+
+	var serviceAccount entities.ServiceAccount
+	var scopes string
+	err := row.Scan(
+		&serviceAccount.ID, &serviceAccount.ClientID, &serviceAccount.Name, &serviceAccount.SecretHash,
+		&scopes, &serviceAccount.PreviousSecretHash, &serviceAccount.PreviousSecretExpiresAt,
+		&serviceAccount.SecretRotatedAt, &serviceAccount.DisabledAt, &serviceAccount.CreatedBy,
+		&serviceAccount.CreatedAt, &serviceAccount.UpdatedAt,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch service account by id: %w", err)
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return nil, ce.NewError(span, ce.CodeServiceAccountNotFound, ce.MsgServiceAccountNotFound, wErr)
+		}
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	serviceAccount.Scopes = strings.Fields(scopes)
+	return &serviceAccount, nil
+}
+
+func (r *serviceAccountRepository) GetByClientID(ctx context.Context, clientID string) (*entities.ServiceAccount, error) {
+	ctx, span := otel.Tracer(serviceAccountErrorTracer).Start(ctx, "GetByClientID")
+	defer span.End()
+
+	query := `
+		SELECT
+			service_account_id, client_id, name, secret_hash, scopes, previous_secret_hash,
+			previous_secret_expires_at, secret_rotated_at, disabled_at, created_by, created_at, updated_at
+		FROM service_accounts
+		WHERE client_id = $1
+	`
+
+	row := r.database.QueryRow(ctx, query, clientID)
+
+	var serviceAccount entities.ServiceAccount
+	var scopes string
+	err := row.Scan(
+		&serviceAccount.ID, &serviceAccount.ClientID, &serviceAccount.Name, &serviceAccount.SecretHash,
+		&scopes, &serviceAccount.PreviousSecretHash, &serviceAccount.PreviousSecretExpiresAt,
+		&serviceAccount.SecretRotatedAt, &serviceAccount.DisabledAt, &serviceAccount.CreatedBy,
+		&serviceAccount.CreatedAt, &serviceAccount.UpdatedAt,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch service account by client id: %w", err)
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return nil, ce.NewError(span, ce.CodeClientInvalid, ce.MsgInvalidClient, wErr)
+		}
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	serviceAccount.Scopes = strings.Fields(scopes)
+	return &serviceAccount, nil
+}
+
+func (r *serviceAccountRepository) RotateSecret(ctx context.Context, serviceAccountID int64, secretHash string, previousExpiresAt time.Time) error {
+	ctx, span := otel.Tracer(serviceAccountErrorTracer).Start(ctx, "RotateSecret")
+	defer span.End()
+
+	// the replaced secret keeps working for a while, so callers can be redeployed with the new one
+	query := `
+		UPDATE service_accounts
+		SET
+			previous_secret_hash = secret_hash, previous_secret_expires_at = $1,
+			secret_hash = $2, secret_rotated_at = NOW(), updated_at = NOW()
+		WHERE service_account_id = $3 AND disabled_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, previousExpiresAt, secretHash, serviceAccountID); err != nil {
+		wErr := fmt.Errorf("failed to rotate service account secret: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodeServiceAccountDisabled, ce.MsgServiceAccountDisabled, wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *serviceAccountRepository) GetExpiredPreviousSecrets(ctx context.Context) ([]entities.ServiceAccount, error) {
+	ctx, span := otel.Tracer(serviceAccountErrorTracer).Start(ctx, "GetExpiredPreviousSecrets")
+	defer span.End()
+
+	query := `
+		SELECT service_account_id, client_id, previous_secret_hash, previous_secret_expires_at
+		FROM service_accounts
+		WHERE previous_secret_hash IS NOT NULL AND previous_secret_expires_at <= NOW() AND disabled_at IS NULL
+		ORDER BY service_account_id
+	`
+
+	rows, err := r.database.QueryAll(ctx, query)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch expired previous secrets: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	defer rows.Close()
+
+	serviceAccounts := make([]entities.ServiceAccount, 0)
+	for rows.Next() {
+		var serviceAccount entities.ServiceAccount
+		err := rows.Scan(
+			&serviceAccount.ID, &serviceAccount.ClientID,
+			&serviceAccount.PreviousSecretHash, &serviceAccount.PreviousSecretExpiresAt,
+		)
+		if err != nil {
+			wErr := fmt.Errorf("failed to fetch expired previous secrets: %w", err)
+			return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+		}
+		serviceAccounts = append(serviceAccounts, serviceAccount)
+	}
+	if err := rows.Err(); err != nil {
+		wErr := fmt.Errorf("failed to fetch expired previous secrets: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return serviceAccounts, nil
+}
+
+func (r *serviceAccountRepository) ClearPreviousSecret(ctx context.Context, serviceAccountID int64, previousSecretHash string) error {
+	ctx, span := otel.Tracer(serviceAccountErrorTracer).Start(ctx, "ClearPreviousSecret")
+	defer span.End()
+
+	// matched on the hash, so a rotation in the meantime is left alone
+	query := `
+		UPDATE service_accounts
+		SET previous_secret_hash = NULL, updated_at = NOW()
+		WHERE service_account_id = $1 AND previous_secret_hash = $2
+	`
+
+	err := r.database.Execute(ctx, query, serviceAccountID, previousSecretHash)
+	if err != nil && !errors.Is(err, ce.ErrDBAffectNoRows) {
+		wErr := fmt.Errorf("failed to clear previous service account secret: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *serviceAccountRepository) Disable(ctx context.Context, serviceAccountID int64) error {
+	ctx, span := otel.Tracer(serviceAccountErrorTracer).Start(ctx, "Disable")
+	defer span.End()
+
+	query := `
+		UPDATE service_accounts
+		SET
+			disabled_at = NOW(), previous_secret_hash = NULL,
+			previous_secret_expires_at = NULL, updated_at = NOW()
+		WHERE service_account_id = $1 AND disabled_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, serviceAccountID); err != nil {
+		wErr := fmt.Errorf("failed to disable service account: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodeServiceAccountDisabled, ce.MsgServiceAccountDisabled, wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
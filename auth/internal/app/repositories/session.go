@@ -17,9 +17,15 @@ const sessionErrorTracer string = "repository.session"
 type SessionRepository interface {
 	Create(ctx context.Context, authID int64, data *entities.CreateSession) (err error)
 	GetByToken(ctx context.Context, token string) (session *entities.Session, err error)
-	RevokeActive(ctx context.Context, authID int64) (revokedSessionID int64, err error)
+	GetByParentID(ctx context.Context, parentID int64) (session *entities.Session, err error)
+	GetAllActiveByAuthID(ctx context.Context, authID int64) (sessions []entities.Session, err error)
+	GetRecentByAuthID(ctx context.Context, authID, excludeID int64, limit int) (sessions []entities.Session, err error)
 	RevokeByID(ctx context.Context, sessionID int64) (err error)
 	RevokeByToken(ctx context.Context, token string) (err error)
+	RevokeOwned(ctx context.Context, authID, sessionID int64) (token string, err error)
+	RevokeOthers(ctx context.Context, authID int64, token string) (err error)
+	RevokeExcess(ctx context.Context, authID int64, keep int) (tokens []string, err error)
+	RevokeFamily(ctx context.Context, sessionID int64) (err error)
 }
 
 type sessionRepository struct {
@@ -34,11 +40,15 @@ func (r *sessionRepository) Create(ctx context.Context, authID int64, data *enti
 	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "Create")
 	defer span.End()
 
+	// a rotated session keeps the sign-in time of the session it replaces
 	query := `
 		INSERT INTO sessions (
-			auth_id, parent_id, token, user_agent, ip_address, expires_at
+			auth_id, parent_id, token, user_agent, ip_address, expires_at, signed_in_at
+		)
+		VALUES (
+			$1, $2, $3, $4, $5, $6,
+			COALESCE((SELECT signed_in_at FROM sessions WHERE session_id = $2), NOW())
 		)
-		VALUES ($1, $2, $3, $4, $5, $6)
 	`
 
 	err := r.database.Execute(
@@ -60,9 +70,9 @@ func (r *sessionRepository) GetByToken(ctx context.Context, token string) (*enti
 	query := `
 		SELECT
 			session_id, auth_id, parent_id, token, user_agent, ip_address,
-			created_at, expires_at, revoked_at
+			signed_in_at, created_at, expires_at, revoked_at
 		FROM sessions
-		WHERE token = $1 AND revoked_at IS NULL
+		WHERE token = $1
 	`
 	if r.database.InTx(ctx) {
 		query += " FOR UPDATE"
@@ -73,7 +83,7 @@ func (r *sessionRepository) GetByToken(ctx context.Context, token string) (*enti
 	var session entities.Session
 	err := row.Scan(
 		&session.ID, &session.AuthID, &session.ParentID,
-		&session.Token, &session.UserAgent, &session.IPAddress,
+		&session.Token, &session.UserAgent, &session.IPAddress, &session.SignedInAt,
 		&session.CreatedAt, &session.ExpiresAt, &session.RevokedAt,
 	)
 	if err != nil {
@@ -87,29 +97,90 @@ func (r *sessionRepository) GetByToken(ctx context.Context, token string) (*enti
 	return &session, nil
 }
 
-func (r *sessionRepository) RevokeActive(ctx context.Context, authID int64) (int64, error) {
-	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "RevokeActive")
+func (r *sessionRepository) GetAllActiveByAuthID(ctx context.Context, authID int64) ([]entities.Session, error) {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "GetAllActiveByAuthID")
 	defer span.End()
 
 	query := `
-		UPDATE sessions
-		SET revoked_at = NOW()
+		SELECT
+			session_id, auth_id, parent_id, token, user_agent, ip_address,
+			signed_in_at, created_at, expires_at, revoked_at
+		FROM sessions
 		WHERE auth_id = $1 AND revoked_at IS NULL AND expires_at >= $2
-		RETURNING session_id
+		ORDER BY created_at DESC
 	`
 
-	row := r.database.QueryRow(ctx, query, authID, time.Now().UTC())
+	rows, err := r.database.QueryAll(ctx, query, authID, time.Now().UTC())
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch active sessions by auth id: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	defer rows.Close()
 
-	var sessionID int64
-	if err := row.Scan(&sessionID); err != nil {
-		if errors.Is(err, ce.ErrDBQueryNoRows) {
-			return 0, nil
+	sessions := make([]entities.Session, 0)
+	for rows.Next() {
+		var session entities.Session
+		err := rows.Scan(
+			&session.ID, &session.AuthID, &session.ParentID,
+			&session.Token, &session.UserAgent, &session.IPAddress, &session.SignedInAt,
+			&session.CreatedAt, &session.ExpiresAt, &session.RevokedAt,
+		)
+		if err != nil {
+			wErr := fmt.Errorf("failed to fetch active sessions by auth id: %w", err)
+			return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
 		}
-		wErr := fmt.Errorf("failed to revoke active session: %w", err)
-		return 0, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		wErr := fmt.Errorf("failed to fetch active sessions by auth id: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
 	}
 
-	return sessionID, nil
+	return sessions, nil
+}
+
+// revoked and expired sessions included, a device stays known after signing out
+func (r *sessionRepository) GetRecentByAuthID(ctx context.Context, authID, excludeID int64, limit int) ([]entities.Session, error) {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "GetRecentByAuthID")
+	defer span.End()
+
+	query := `
+		SELECT
+			session_id, auth_id, parent_id, token, user_agent, ip_address,
+			signed_in_at, created_at, expires_at, revoked_at
+		FROM sessions
+		WHERE auth_id = $1 AND session_id <> $2
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.database.QueryAll(ctx, query, authID, excludeID, limit)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch recent sessions by auth id: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	defer rows.Close()
+
+	sessions := make([]entities.Session, 0)
+	for rows.Next() {
+		var session entities.Session
+		err := rows.Scan(
+			&session.ID, &session.AuthID, &session.ParentID,
+			&session.Token, &session.UserAgent, &session.IPAddress, &session.SignedInAt,
+			&session.CreatedAt, &session.ExpiresAt, &session.RevokedAt,
+		)
+		if err != nil {
+			wErr := fmt.Errorf("failed to fetch recent sessions by auth id: %w", err)
+			return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		wErr := fmt.Errorf("failed to fetch recent sessions by auth id: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return sessions, nil
 }
 
 func (r *sessionRepository) RevokeByID(ctx context.Context, sessionID int64) error {
@@ -151,3 +222,158 @@ func (r *sessionRepository) RevokeByToken(ctx context.Context, token string) err
 	}
 	return nil
 }
+
+func (r *sessionRepository) RevokeOwned(ctx context.Context, authID, sessionID int64) (string, error) {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "RevokeOwned")
+	defer span.End()
+
+	query := `
+		UPDATE sessions
+		SET revoked_at = NOW()
+		WHERE session_id = $1 AND auth_id = $2 AND revoked_at IS NULL
+		RETURNING token
+	`
+
+	row := r.database.QueryRow(ctx, query, sessionID, authID)
+
+	var token string
+	if err := row.Scan(&token); err != nil {
+		wErr := fmt.Errorf("failed to revoke owned session: %w", err)
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return "", ce.NewError(span, ce.CodeSessionIDNotFound, ce.MsgSessionNotFound, wErr)
+		}
+		return "", ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return token, nil
+}
+
+func (r *sessionRepository) RevokeOthers(ctx context.Context, authID int64, token string) error {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "RevokeOthers")
+	defer span.End()
+
+	query := `
+		UPDATE sessions
+		SET revoked_at = NOW()
+		WHERE auth_id = $1 AND token <> $2 AND revoked_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, authID, token); err != nil {
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return nil
+		}
+		wErr := fmt.Errorf("failed to revoke other sessions: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
+
+func (r *sessionRepository) RevokeExcess(ctx context.Context, authID int64, keep int) ([]string, error) {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "RevokeExcess")
+	defer span.End()
+
+	// keeps the most recently used sessions, revoking everything beyond them
+	query := `
+		UPDATE sessions
+		SET revoked_at = NOW()
+		WHERE session_id IN (
+			SELECT session_id
+			FROM sessions
+			WHERE auth_id = $1 AND revoked_at IS NULL AND expires_at >= $2
+			ORDER BY created_at DESC
+			OFFSET $3
+		)
+		RETURNING token
+	`
+
+	rows, err := r.database.QueryAll(ctx, query, authID, time.Now().UTC(), keep)
+	if err != nil {
+		wErr := fmt.Errorf("failed to revoke excess sessions: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	defer rows.Close()
+
+	tokens := make([]string, 0)
+	for rows.Next() {
+		var token string
+		if err := rows.Scan(&token); err != nil {
+			wErr := fmt.Errorf("failed to revoke excess sessions: %w", err)
+			return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		wErr := fmt.Errorf("failed to revoke excess sessions: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return tokens, nil
+}
+
+func (r *sessionRepository) GetByParentID(ctx context.Context, parentID int64) (*entities.Session, error) {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "GetByParentID")
+	defer span.End()
+
+	query := `
+		SELECT
+			session_id, auth_id, parent_id, token, user_agent, ip_address,
+			signed_in_at, created_at, expires_at, revoked_at
+		FROM sessions
+		WHERE parent_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	row := r.database.QueryRow(ctx, query, parentID)
+
+	var session entities.Session
+	err := row.Scan(
+		&session.ID, &session.AuthID, &session.ParentID,
+		&session.Token, &session.UserAgent, &session.IPAddress, &session.SignedInAt,
+		&session.CreatedAt, &session.ExpiresAt, &session.RevokedAt,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch session by parent id: %w", err)
+		if errors.Is(err, ce.ErrDBQueryNoRows) {
+			return nil, ce.NewError(span, ce.CodeSessionNotFound, ce.MsgInvalidCredentials, wErr)
+		}
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return &session, nil
+}
+
+func (r *sessionRepository) RevokeFamily(ctx context.Context, sessionID int64) error {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "RevokeFamily")
+	defer span.End()
+
+	// walks up the parent chain to the root session, then revokes every descendant of it
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT session_id, parent_id FROM sessions WHERE session_id = $1
+			UNION ALL
+			SELECT s.session_id, s.parent_id
+			FROM sessions s
+			JOIN ancestors a ON s.session_id = a.parent_id
+		),
+		family AS (
+			SELECT session_id FROM ancestors WHERE parent_id IS NULL
+			UNION ALL
+			SELECT s.session_id
+			FROM sessions s
+			JOIN family f ON s.parent_id = f.session_id
+		)
+		UPDATE sessions
+		SET revoked_at = NOW()
+		WHERE session_id IN (SELECT session_id FROM family) AND revoked_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, sessionID); err != nil {
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return nil
+		}
+		wErr := fmt.Errorf("failed to revoke session family: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"go.opentelemetry.io/otel"
+)
+
+const signingKeyErrorTracer string = "repository.signing_key"
+
+type SigningKeyRepository interface {
+	Create(ctx context.Context, data *entities.CreateSigningKey) (err error)
+	GetAllActive(ctx context.Context) (keys []entities.SigningKey, err error)
+	DeleteExpired(ctx context.Context) (err error)
+}
+
+type signingKeyRepository struct {
+	database *database.Database
+}
+
+func NewSigningKeyRepository(database *database.Database) SigningKeyRepository {
+	return &signingKeyRepository{database}
+}
+
+func (r *signingKeyRepository) Create(ctx context.Context, data *entities.CreateSigningKey) error {
+	ctx, span := otel.Tracer(signingKeyErrorTracer).Start(ctx, "Create")
+	defer span.End()
+
+	query := `
+		INSERT INTO signing_keys (kid, algorithm, private_key, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	if err := r.database.Execute(ctx, query, data.KID, data.Algorithm, data.PrivateKey, data.ExpiresAt); err != nil {
+		wErr := fmt.Errorf("failed to create signing key: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (r *signingKeyRepository) GetAllActive(ctx context.Context) ([]entities.SigningKey, error) {
+	ctx, span := otel.Tracer(signingKeyErrorTracer).Start(ctx, "GetAllActive")
+	defer span.End()
+
+	query := `
+		SELECT signing_key_id, kid, algorithm, private_key, created_at, expires_at
+		FROM signing_keys
+		WHERE expires_at > $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.database.QueryAll(ctx, query, time.Now().UTC())
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch active signing keys: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	defer rows.Close()
+
+	keys := make([]entities.SigningKey, 0)
+	for rows.Next() {
+		var key entities.SigningKey
+		err := rows.Scan(
+			&key.ID, &key.KID, &key.Algorithm, &key.PrivateKey,
+			&key.CreatedAt, &key.ExpiresAt,
+		)
+		if err != nil {
+			wErr := fmt.Errorf("failed to fetch active signing keys: %w", err)
+			return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		wErr := fmt.Errorf("failed to fetch active signing keys: %w", err)
+		return nil, ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return keys, nil
+}
+
+func (r *signingKeyRepository) DeleteExpired(ctx context.Context) error {
+	ctx, span := otel.Tracer(signingKeyErrorTracer).Start(ctx, "DeleteExpired")
+	defer span.End()
+
+	query := "DELETE FROM signing_keys WHERE expires_at <= $1"
+
+	if err := r.database.Execute(ctx, query, time.Now().UTC()); err != nil {
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return nil
+		}
+		wErr := fmt.Errorf("failed to delete expired signing keys: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+	return nil
+}
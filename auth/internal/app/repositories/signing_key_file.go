@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"go.opentelemetry.io/otel"
+)
+
+type fileSigningKey struct {
+	KID        string    `json:"kid"`
+	Algorithm  string    `json:"algorithm"`
+	PrivateKey string    `json:"private_key"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type fileSigningKeyRepository struct {
+	dir string
+}
+
+func NewFileSigningKeyRepository(dir string) SigningKeyRepository {
+	return &fileSigningKeyRepository{dir}
+}
+
+func (r *fileSigningKeyRepository) Create(ctx context.Context, data *entities.CreateSigningKey) error {
+	_, span := otel.Tracer(signingKeyErrorTracer).Start(ctx, "Create")
+	defer span.End()
+
+	key := fileSigningKey{
+		KID:        data.KID,
+		Algorithm:  data.Algorithm,
+		PrivateKey: data.PrivateKey,
+		CreatedAt:  time.Now().UTC(),
+		ExpiresAt:  data.ExpiresAt,
+	}
+
+	bytes, err := json.Marshal(&key)
+	if err != nil {
+		wErr := fmt.Errorf("failed to create signing key: %w", err)
+		return ce.NewError(span, ce.CodeFileOperationFailed, ce.MsgInternalServer, wErr)
+	}
+
+	if err := os.MkdirAll(r.dir, 0o700); err != nil {
+		wErr := fmt.Errorf("failed to create signing key: %w", err)
+		return ce.NewError(span, ce.CodeFileOperationFailed, ce.MsgInternalServer, wErr)
+	}
+	if err := os.WriteFile(r.path(key.KID), bytes, 0o600); err != nil {
+		wErr := fmt.Errorf("failed to create signing key: %w", err)
+		return ce.NewError(span, ce.CodeFileOperationFailed, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
+func (r *fileSigningKeyRepository) GetAllActive(ctx context.Context) ([]entities.SigningKey, error) {
+	_, span := otel.Tracer(signingKeyErrorTracer).Start(ctx, "GetAllActive")
+	defer span.End()
+
+	files, err := r.readAll()
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch active signing keys: %w", err)
+		return nil, ce.NewError(span, ce.CodeFileOperationFailed, ce.MsgInternalServer, wErr)
+	}
+
+	now := time.Now().UTC()
+	keys := make([]entities.SigningKey, 0, len(files))
+	for _, file := range files {
+		if !file.ExpiresAt.After(now) {
+			continue
+		}
+		keys = append(keys, entities.SigningKey{
+			KID:        file.KID,
+			Algorithm:  file.Algorithm,
+			PrivateKey: file.PrivateKey,
+			CreatedAt:  file.CreatedAt,
+			ExpiresAt:  file.ExpiresAt,
+		})
+	}
+
+	// newest first, matching the database store
+	slices.SortFunc(keys, func(a, b entities.SigningKey) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return keys, nil
+}
+
+func (r *fileSigningKeyRepository) DeleteExpired(ctx context.Context) error {
+	_, span := otel.Tracer(signingKeyErrorTracer).Start(ctx, "DeleteExpired")
+	defer span.End()
+
+	files, err := r.readAll()
+	if err != nil {
+		wErr := fmt.Errorf("failed to delete expired signing keys: %w", err)
+		return ce.NewError(span, ce.CodeFileOperationFailed, ce.MsgInternalServer, wErr)
+	}
+
+	now := time.Now().UTC()
+	for _, file := range files {
+		if file.ExpiresAt.After(now) {
+			continue
+		}
+		if err := os.Remove(r.path(file.KID)); err != nil && !os.IsNotExist(err) {
+			wErr := fmt.Errorf("failed to delete expired signing keys: %w", err)
+			return ce.NewError(span, ce.CodeFileOperationFailed, ce.MsgInternalServer, wErr)
+		}
+	}
+
+	return nil
+}
+
+func (r *fileSigningKeyRepository) readAll() ([]fileSigningKey, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	keys := make([]fileSigningKey, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		bytes, err := os.ReadFile(filepath.Join(r.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var key fileSigningKey
+		if err := json.Unmarshal(bytes, &key); err != nil {
+			return nil, fmt.Errorf("invalid key file %s: %w", entry.Name(), err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (r *fileSigningKeyRepository) path(kid string) string {
+	return filepath.Join(r.dir, kid+".json")
+}
@@ -0,0 +1,177 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/publishers"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+)
+
+const accountErrorTracer string = "usecase.account"
+
+type AccountUsecase interface {
+	RequestDeletion(ctx context.Context, authID int64, data *entities.ConfirmIdentity, request *entities.Request) (deletion *entities.AccountDeletion, err error)
+	GetDeletion(ctx context.Context, authID int64) (deletion *entities.AccountDeletion, err error)
+	CancelDeletion(ctx context.Context, authID int64) (err error)
+	PurgeDeletions(ctx context.Context) (err error)
+}
+
+type accountUsecase struct {
+	ar         repositories.AuthRepository
+	par        repositories.PharmacyApplicationRepository
+	oar        repositories.OAuthRepository
+	mr         repositories.MFARepository
+	pr         repositories.PasskeyRepository
+	phr        repositories.PasswordHistoryRepository
+	ser        repositories.SecurityEventRepository
+	au         AuthUsecase
+	su         SessionUsecase
+	aep        publishers.AuthEventPublisher
+	transactor *database.Transactor
+	cfg        *configs.Config
+}
+
+func NewAccountUsecase(
+	ar repositories.AuthRepository,
+	par repositories.PharmacyApplicationRepository,
+	oar repositories.OAuthRepository,
+	mr repositories.MFARepository,
+	pr repositories.PasskeyRepository,
+	phr repositories.PasswordHistoryRepository,
+	ser repositories.SecurityEventRepository,
+	au AuthUsecase,
+	su SessionUsecase,
+	aep publishers.AuthEventPublisher,
+	transactor *database.Transactor,
+	cfg *configs.Config,
+) AccountUsecase {
+	return &accountUsecase{ar, par, oar, mr, pr, phr, ser, au, su, aep, transactor, cfg}
+}
+
+func (u *accountUsecase) RequestDeletion(ctx context.Context, authID int64, data *entities.ConfirmIdentity, request *entities.Request) (*entities.AccountDeletion, error) {
+	ctx, span := otel.Tracer(accountErrorTracer).Start(ctx, "RequestDeletion")
+	defer span.End()
+
+	// confirmed outside the transaction, a failed attempt has to be recorded either way
+	auth, err := u.au.ConfirmIdentity(ctx, authID, data, request)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduledAt := time.Now().UTC().Add(u.cfg.Auth.Deletion.CoolingOff)
+
+	var deletion *entities.AccountDeletion
+	err = u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		deletion, err = u.ar.ScheduleDeletion(ctx, auth.ID, scheduledAt)
+		if err != nil {
+			return err
+		}
+		return u.aep.PublishDeletionScheduled(ctx, auth.ID, auth.Email, deletion.ScheduledAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deletion, nil
+}
+
+func (u *accountUsecase) GetDeletion(ctx context.Context, authID int64) (*entities.AccountDeletion, error) {
+	ctx, span := otel.Tracer(accountErrorTracer).Start(ctx, "GetDeletion")
+	defer span.End()
+
+	return u.ar.GetDeletion(ctx, authID)
+}
+
+func (u *accountUsecase) CancelDeletion(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(accountErrorTracer).Start(ctx, "CancelDeletion")
+	defer span.End()
+
+	return u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		auth, err := u.ar.GetByID(ctx, authID)
+		if err != nil {
+			return err
+		}
+
+		if err := u.ar.CancelDeletion(ctx, auth.ID); err != nil {
+			return err
+		}
+		return u.aep.PublishDeletionCancelled(ctx, auth.ID, auth.Email)
+	})
+}
+
+func (u *accountUsecase) PurgeDeletions(ctx context.Context) error {
+	ctx, span := otel.Tracer(accountErrorTracer).Start(ctx, "PurgeDeletions")
+	defer span.End()
+
+	for range u.cfg.Auth.Deletion.BatchSize {
+		isPurged, err := u.purgeNext(ctx)
+		if err != nil {
+			return err
+		}
+		if !isPurged {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (u *accountUsecase) purgeNext(ctx context.Context) (bool, error) {
+	isPurged := false
+
+	// one account per transaction, a failure leaves the others untouched
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		isDue, authID, err := u.ar.GetDueForDeletion(ctx, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if !isDue {
+			return nil
+		}
+
+		auth, err := u.ar.GetByID(ctx, authID)
+		if err != nil {
+			return err
+		}
+
+		if err := u.oar.DeleteAllByAuthID(ctx, auth.ID); err != nil {
+			return err
+		}
+		if err := u.mr.DeleteAllByAuthID(ctx, auth.ID); err != nil {
+			return err
+		}
+		if err := u.pr.DeleteAllByAuthID(ctx, auth.ID); err != nil {
+			return err
+		}
+		if err := u.phr.DeleteAllByAuthID(ctx, auth.ID); err != nil {
+			return err
+		}
+		if err := u.ser.AnonymizeByAuthID(ctx, auth.ID, utils.HashSHA256(auth.Email)); err != nil {
+			return err
+		}
+		if err := u.par.DeleteByAuthID(ctx, auth.ID); err != nil {
+			return err
+		}
+		if err := u.ar.SoftDelete(ctx, auth.ID); err != nil {
+			return err
+		}
+		if err := u.su.RevokeAllSessions(ctx, auth.ID, ""); err != nil {
+			return err
+		}
+
+		// the other services erase what they hold once this is relayed
+		if err := u.aep.PublishAccountDeleted(ctx, auth.ID, auth.Email); err != nil {
+			return err
+		}
+
+		isPurged = true
+		return nil
+	})
+
+	return isPurged, err
+}
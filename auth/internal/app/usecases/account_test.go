@@ -0,0 +1,159 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/publishers"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+)
+
+type purgeAuthRepository struct {
+	repositories.AuthRepository
+	log   *callLog
+	isDue bool
+	auth  *entities.Auth
+}
+
+func (r *purgeAuthRepository) GetDueForDeletion(ctx context.Context, before time.Time) (bool, int64, error) {
+	if !r.isDue {
+		return false, 0, nil
+	}
+	r.isDue = false
+	return true, r.auth.ID, nil
+}
+
+func (r *purgeAuthRepository) GetByID(ctx context.Context, authID int64) (*entities.Auth, error) {
+	return r.auth, nil
+}
+
+func (r *purgeAuthRepository) SoftDelete(ctx context.Context, authID int64) error {
+	r.log.add("auth.SoftDelete")
+	return nil
+}
+
+type purgePharmacyApplicationRepository struct {
+	repositories.PharmacyApplicationRepository
+	log *callLog
+	err error
+}
+
+func (r *purgePharmacyApplicationRepository) DeleteByAuthID(ctx context.Context, authID int64) error {
+	r.log.add("pharmacy_application.DeleteByAuthID")
+	return r.err
+}
+
+type purgeOAuthRepository struct {
+	repositories.OAuthRepository
+}
+
+func (r *purgeOAuthRepository) DeleteAllByAuthID(ctx context.Context, authID int64) error {
+	return nil
+}
+
+type purgeMFARepository struct {
+	repositories.MFARepository
+}
+
+func (r *purgeMFARepository) DeleteAllByAuthID(ctx context.Context, authID int64) error {
+	return nil
+}
+
+type purgePasskeyRepository struct {
+	repositories.PasskeyRepository
+}
+
+func (r *purgePasskeyRepository) DeleteAllByAuthID(ctx context.Context, authID int64) error {
+	return nil
+}
+
+type purgePasswordHistoryRepository struct {
+	repositories.PasswordHistoryRepository
+}
+
+func (r *purgePasswordHistoryRepository) DeleteAllByAuthID(ctx context.Context, authID int64) error {
+	return nil
+}
+
+type purgeSecurityEventRepository struct {
+	repositories.SecurityEventRepository
+}
+
+func (r *purgeSecurityEventRepository) AnonymizeByAuthID(ctx context.Context, authID int64, emailHash string) error {
+	return nil
+}
+
+type purgeSessionUsecase struct {
+	SessionUsecase
+}
+
+func (u *purgeSessionUsecase) RevokeAllSessions(ctx context.Context, authID int64, exceptToken string) error {
+	return nil
+}
+
+type purgeAuthEventPublisher struct {
+	publishers.AuthEventPublisher
+	log *callLog
+}
+
+func (p *purgeAuthEventPublisher) PublishAccountDeleted(ctx context.Context, authID int64, email string) error {
+	p.log.add("publisher.PublishAccountDeleted")
+	return nil
+}
+
+func newPurgeUsecase(t *testing.T, log *callLog, parErr error) AccountUsecase {
+	t.Helper()
+
+	cfg := &configs.Config{}
+	cfg.Auth.Deletion.BatchSize = 10
+
+	return NewAccountUsecase(
+		&purgeAuthRepository{log: log, isDue: true, auth: &entities.Auth{ID: 7, Email: "owner@pharmacy.test"}},
+		&purgePharmacyApplicationRepository{log: log, err: parErr},
+		&purgeOAuthRepository{},
+		&purgeMFARepository{},
+		&purgePasskeyRepository{},
+		&purgePasswordHistoryRepository{},
+		&purgeSecurityEventRepository{},
+		nil,
+		&purgeSessionUsecase{},
+		&purgeAuthEventPublisher{log: log},
+		newTestTransactor(t),
+		cfg,
+	)
+}
+
+func TestPurgeDeletionsErasesPharmacyApplication(t *testing.T) {
+	log := &callLog{}
+	u := newPurgeUsecase(t, log, nil)
+
+	if err := u.PurgeDeletions(context.Background()); err != nil {
+		t.Fatalf("PurgeDeletions: unexpected error: %v", err)
+	}
+
+	deleted := log.index("pharmacy_application.DeleteByAuthID")
+	if deleted < 0 {
+		t.Fatal("pharmacy application was not deleted")
+	}
+	if softDeleted := log.index("auth.SoftDelete"); softDeleted < 0 || deleted > softDeleted {
+		t.Errorf("pharmacy application must be deleted before the soft delete, calls: %v", log.calls)
+	}
+}
+
+func TestPurgeDeletionsStopsWhenPharmacyApplicationFails(t *testing.T) {
+	log := &callLog{}
+	parErr := errors.New("delete failed")
+	u := newPurgeUsecase(t, log, parErr)
+
+	if err := u.PurgeDeletions(context.Background()); !errors.Is(err, parErr) {
+		t.Fatalf("PurgeDeletions: got error %v, want %v", err, parErr)
+	}
+
+	if log.has("auth.SoftDelete") || log.has("publisher.PublishAccountDeleted") {
+		t.Errorf("purge must stop at the failed delete, calls: %v", log.calls)
+	}
+}
@@ -0,0 +1,572 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/caches"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/publishers"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const adminErrorTracer string = "usecase.admin"
+
+type AdminUsecase interface {
+	SearchAccounts(ctx context.Context, adminID int64, filter *entities.AccountFilter, request *entities.Request) (accounts []entities.Account, total int64, err error)
+	GetAccount(ctx context.Context, adminID, targetID int64, request *entities.Request) (account *entities.Account, err error)
+	GetSessions(ctx context.Context, adminID, targetID int64, request *entities.Request) (sessions []entities.Session, err error)
+	SearchSecurityEvents(ctx context.Context, adminID int64, filter *entities.SecurityEventFilter, cursor string, request *entities.Request) (events []entities.SecurityEvent, nextCursor string, err error)
+	VerifyAccount(ctx context.Context, adminID, targetID int64, request *entities.Request) (err error)
+	ApproveAccount(ctx context.Context, adminID, targetID int64, request *entities.Request) (err error)
+	SuspendAccount(ctx context.Context, adminID, targetID int64, reason string, request *entities.Request) (err error)
+	UnsuspendAccount(ctx context.Context, adminID, targetID int64, request *entities.Request) (err error)
+	ForcePasswordReset(ctx context.Context, adminID, targetID int64, request *entities.Request) (err error)
+	RevokeSessions(ctx context.Context, adminID, targetID int64, request *entities.Request) (err error)
+	Impersonate(ctx context.Context, adminID, targetID int64, reason string, request *entities.Request) (accessToken *entities.AccessToken, err error)
+	GetServiceAccounts(ctx context.Context, adminID int64, request *entities.Request) (serviceAccounts []entities.ServiceAccount, err error)
+	CreateServiceAccount(ctx context.Context, adminID int64, data *entities.CreateServiceAccount, request *entities.Request) (serviceAccount *entities.ServiceAccount, secret string, err error)
+	RotateServiceAccountSecret(ctx context.Context, adminID, serviceAccountID int64, request *entities.Request) (serviceAccount *entities.ServiceAccount, secret string, err error)
+	DisableServiceAccount(ctx context.Context, adminID, serviceAccountID int64, request *entities.Request) (err error)
+}
+
+type adminUsecase struct {
+	ar         repositories.AuthRepository
+	par        repositories.PharmacyApplicationRepository
+	aar        repositories.AdminAuditRepository
+	sar        repositories.ServiceAccountRepository
+	ac         caches.AuthCache
+	tdc        caches.TokenDenylistCache
+	su         SessionUsecase
+	seu        SecurityEventUsecase
+	aep        publishers.AuthEventPublisher
+	transactor *database.Transactor
+	jwt        *services.JWTService
+	cfg        *configs.Config
+}
+
+func NewAdminUsecase(
+	ar repositories.AuthRepository,
+	par repositories.PharmacyApplicationRepository,
+	aar repositories.AdminAuditRepository,
+	sar repositories.ServiceAccountRepository,
+	ac caches.AuthCache,
+	tdc caches.TokenDenylistCache,
+	su SessionUsecase,
+	seu SecurityEventUsecase,
+	aep publishers.AuthEventPublisher,
+	transactor *database.Transactor,
+	jwt *services.JWTService,
+	cfg *configs.Config,
+) AdminUsecase {
+	return &adminUsecase{ar, par, aar, sar, ac, tdc, su, seu, aep, transactor, jwt, cfg}
+}
+
+func (u *adminUsecase) SearchAccounts(ctx context.Context, adminID int64, filter *entities.AccountFilter, request *entities.Request) ([]entities.Account, int64, error) {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "SearchAccounts")
+	defer span.End()
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = u.cfg.Auth.Admin.PageSize
+	}
+	if filter.Limit > u.cfg.Auth.Admin.MaxPageSize {
+		filter.Limit = u.cfg.Auth.Admin.MaxPageSize
+	}
+
+	accounts, total, err := u.ar.Search(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	details := map[string]any{"page": filter.Page, "limit": filter.Limit}
+	if filter.Email != "" {
+		details["email"] = filter.Email
+	}
+	if filter.RoleID != nil {
+		details["role"] = *filter.RoleID
+	}
+	if filter.IsVerified != nil {
+		details["is_verified"] = *filter.IsVerified
+	}
+	if filter.IsApproved != nil {
+		details["is_approved"] = *filter.IsApproved
+	}
+	if err := u.audit(ctx, adminID, nil, constants.AdminActionAccountsSearched, details, request); err != nil {
+		return nil, 0, err
+	}
+
+	return accounts, total, nil
+}
+
+func (u *adminUsecase) GetAccount(ctx context.Context, adminID, targetID int64, request *entities.Request) (*entities.Account, error) {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "GetAccount")
+	defer span.End()
+
+	account, err := u.ar.GetAccount(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if account.RoleID == constants.RolePharmacy {
+		// the reviewer needs the licence details to decide on the approval
+		application, err := u.par.GetByAuthID(ctx, targetID)
+		if err != nil {
+			var cErr *ce.Error
+			if !errors.As(err, &cErr) || cErr.Code != ce.CodePharmacyAppNotFound {
+				return nil, err
+			}
+		}
+		account.Application = application
+	}
+	if err := u.audit(ctx, adminID, &targetID, constants.AdminActionAccountViewed, nil, request); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (u *adminUsecase) GetSessions(ctx context.Context, adminID, targetID int64, request *entities.Request) ([]entities.Session, error) {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "GetSessions")
+	defer span.End()
+
+	if _, err := u.ar.GetAccount(ctx, targetID); err != nil {
+		return nil, err
+	}
+
+	sessions, err := u.su.GetActiveSessions(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.audit(ctx, adminID, &targetID, constants.AdminActionSessionsViewed, nil, request); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (u *adminUsecase) SearchSecurityEvents(ctx context.Context, adminID int64, filter *entities.SecurityEventFilter, cursor string, request *entities.Request) ([]entities.SecurityEvent, string, error) {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "SearchSecurityEvents")
+	defer span.End()
+
+	events, nextCursor, err := u.seu.SearchEvents(ctx, filter, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	details := map[string]any{"limit": filter.Limit}
+	if cursor != "" {
+		details["cursor"] = cursor
+	}
+	if filter.EventType != "" {
+		details["event_type"] = filter.EventType
+	}
+	if filter.IPAddress != "" {
+		details["ip_address"] = filter.IPAddress
+	}
+	if filter.From != nil {
+		details["from"] = *filter.From
+	}
+	if filter.To != nil {
+		details["to"] = *filter.To
+	}
+	if err := u.audit(ctx, adminID, filter.AuthID, constants.AdminActionAuthEventsSearched, details, request); err != nil {
+		return nil, "", err
+	}
+
+	return events, nextCursor, nil
+}
+
+func (u *adminUsecase) VerifyAccount(ctx context.Context, adminID, targetID int64, request *entities.Request) error {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "VerifyAccount")
+	defer span.End()
+
+	return u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		account, err := u.ar.GetAccount(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if account.IsVerified {
+			err := fmt.Errorf("failed to verify account: %w", errors.New("account already verified"))
+			return ce.NewError(span, ce.CodeAuthVerified, "Email is already verified", err)
+		}
+
+		auth, err := u.ar.SetVerified(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if err := u.aep.PublishAccountVerified(ctx, auth.ID, auth.Email); err != nil {
+			return err
+		}
+
+		return u.audit(ctx, adminID, &targetID, constants.AdminActionAccountVerified, nil, request)
+	})
+}
+
+func (u *adminUsecase) ApproveAccount(ctx context.Context, adminID, targetID int64, request *entities.Request) error {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "ApproveAccount")
+	defer span.End()
+
+	return u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		account, err := u.ar.GetAccount(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if !account.IsVerified {
+			// the owner has to prove the email first, an approval is not a substitute
+			err := fmt.Errorf("failed to approve account: %w", errors.New("account not verified"))
+			return ce.NewError(span, ce.CodeAuthNotVerified, "Account email has to be verified before approval", err)
+		}
+
+		details := map[string]any{"role": account.RoleID}
+		if account.RoleID == constants.RolePharmacy {
+			// the licence is checked again here, it may have lapsed while the account was pending
+			application, err := u.par.GetByAuthID(ctx, targetID)
+			if err != nil {
+				return err
+			}
+			if !application.LicenseExpiry.After(time.Now().UTC()) {
+				err := fmt.Errorf("failed to approve account: %w", errors.New("license expired"))
+				return ce.NewError(span, ce.CodePharmacyLicenseExpired, ce.MsgPharmacyLicenseExpired, err)
+			}
+			if err := u.par.MarkReviewed(ctx, targetID, adminID); err != nil {
+				return err
+			}
+			details["license_number"] = application.LicenseNumber
+		}
+
+		if _, err := u.ar.Approve(ctx, targetID); err != nil {
+			return err
+		}
+
+		return u.audit(ctx, adminID, &targetID, constants.AdminActionAccountApproved, details, request)
+	})
+}
+
+func (u *adminUsecase) SuspendAccount(ctx context.Context, adminID, targetID int64, reason string, request *entities.Request) error {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "SuspendAccount")
+	defer span.End()
+
+	if err := checkSelfAction(span, adminID, targetID); err != nil {
+		return err
+	}
+
+	return u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		account, err := u.ar.GetAccount(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if err := checkTargetRole(span, account); err != nil {
+			return err
+		}
+		if err := u.ar.Suspend(ctx, targetID, reason); err != nil {
+			return err
+		}
+
+		// a suspended account must not keep any of its sessions
+		if err := u.su.RevokeAllSessions(ctx, targetID, ""); err != nil {
+			return err
+		}
+
+		details := map[string]any{"reason": reason}
+		return u.audit(ctx, adminID, &targetID, constants.AdminActionAccountSuspended, details, request)
+	})
+}
+
+func (u *adminUsecase) UnsuspendAccount(ctx context.Context, adminID, targetID int64, request *entities.Request) error {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "UnsuspendAccount")
+	defer span.End()
+
+	if err := checkSelfAction(span, adminID, targetID); err != nil {
+		return err
+	}
+
+	return u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := u.ar.GetAccount(ctx, targetID); err != nil {
+			return err
+		}
+		if err := u.ar.Unsuspend(ctx, targetID); err != nil {
+			return err
+		}
+
+		return u.audit(ctx, adminID, &targetID, constants.AdminActionAccountUnsuspended, nil, request)
+	})
+}
+
+func (u *adminUsecase) ForcePasswordReset(ctx context.Context, adminID, targetID int64, request *entities.Request) error {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "ForcePasswordReset")
+	defer span.End()
+
+	if err := checkSelfAction(span, adminID, targetID); err != nil {
+		return err
+	}
+
+	return u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		account, err := u.ar.GetAccount(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if err := checkTargetRole(span, account); err != nil {
+			return err
+		}
+		if !account.HasPassword {
+			// this is an oauth type account
+			err := fmt.Errorf("failed to force password reset: %w", errors.New("oauth account has no password"))
+			return ce.NewError(span, ce.CodeOAuthPasswordChange, "OAuth account has no password to reset", err)
+		}
+
+		if err := u.ar.RequirePasswordReset(ctx, targetID); err != nil {
+			return err
+		}
+		if err := u.su.RevokeAllSessions(ctx, targetID, ""); err != nil {
+			return err
+		}
+
+		// the owner gets the usual reset email, signing in is blocked until it is used
+		token := utils.NewUUID().String()
+		if err := u.ac.CreateResetToken(ctx, targetID, token, u.cfg.Auth.TokenDuration.Reset); err != nil {
+			return err
+		}
+		if err := u.aep.PublishPasswordResetRequested(ctx, targetID, account.Email, token); err != nil {
+			return err
+		}
+
+		return u.audit(ctx, adminID, &targetID, constants.AdminActionPasswordResetForced, nil, request)
+	})
+}
+
+func (u *adminUsecase) RevokeSessions(ctx context.Context, adminID, targetID int64, request *entities.Request) error {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "RevokeSessions")
+	defer span.End()
+
+	if err := checkSelfAction(span, adminID, targetID); err != nil {
+		return err
+	}
+
+	return u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		account, err := u.ar.GetAccount(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if err := checkTargetRole(span, account); err != nil {
+			return err
+		}
+		if err := u.su.RevokeAllSessions(ctx, targetID, ""); err != nil {
+			return err
+		}
+
+		return u.audit(ctx, adminID, &targetID, constants.AdminActionSessionsRevoked, nil, request)
+	})
+}
+
+func (u *adminUsecase) Impersonate(ctx context.Context, adminID, targetID int64, reason string, request *entities.Request) (*entities.AccessToken, error) {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "Impersonate")
+	defer span.End()
+
+	if err := checkSelfAction(span, adminID, targetID); err != nil {
+		return nil, err
+	}
+
+	var accessToken *entities.AccessToken
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		account, err := u.ar.GetAccount(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if account.RoleID == constants.RoleAdmin {
+			// acting as another admin would lend out its admin rights
+			err := fmt.Errorf("failed to impersonate: %w", errors.New("target is an admin"))
+			return ce.NewError(span, ce.CodeImpersonationForbidden, "Admin accounts cannot be impersonated", err)
+		}
+		if account.SuspendedAt != nil {
+			err := fmt.Errorf("failed to impersonate: %w", errors.New("account suspended"))
+			return ce.NewError(span, ce.CodeAccountSuspended, ce.MsgAccountSuspended, err)
+		}
+
+		// no session is created, so the token cannot be refreshed and simply runs out
+		auth := entities.Auth{
+			ID:         account.ID,
+			RoleID:     account.RoleID,
+			IsVerified: account.IsVerified,
+			IsApproved: account.IsApproved,
+		}
+		accessToken, err = u.jwt.CreateImpersonation(&auth, adminID, u.cfg.Auth.Admin.ImpersonationDuration)
+		if err != nil {
+			wErr := fmt.Errorf("failed to impersonate: %w", err)
+			return ce.NewError(span, ce.CodeJWTGenerationFailed, ce.MsgInternalServer, wErr)
+		}
+
+		// tracked with the owner's tokens, so a suspension or a revoke-all cuts it short as well
+		if err := u.su.TrackAccessToken(ctx, targetID, constants.ImpersonationSessionToken, "", accessToken); err != nil {
+			return err
+		}
+
+		details := map[string]any{
+			"reason":     reason,
+			"token_id":   accessToken.ID,
+			"expires_at": accessToken.ExpiresAt,
+		}
+		return u.audit(ctx, adminID, &targetID, constants.AdminActionImpersonated, details, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the owner sees it in their own event log as well
+	details := map[string]any{"admin_id": adminID, "token_id": accessToken.ID}
+	u.seu.Record(ctx, &targetID, constants.EventTypeImpersonated, details)
+
+	return accessToken, nil
+}
+
+func (u *adminUsecase) GetServiceAccounts(ctx context.Context, adminID int64, request *entities.Request) ([]entities.ServiceAccount, error) {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "GetServiceAccounts")
+	defer span.End()
+
+	serviceAccounts, err := u.sar.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.audit(ctx, adminID, nil, constants.AdminActionServiceAccountsViewed, nil, request); err != nil {
+		return nil, err
+	}
+
+	return serviceAccounts, nil
+}
+
+// the secret is only ever returned here and on rotation, just its hash is kept
+func (u *adminUsecase) CreateServiceAccount(ctx context.Context, adminID int64, data *entities.CreateServiceAccount, request *entities.Request) (*entities.ServiceAccount, string, error) {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "CreateServiceAccount")
+	defer span.End()
+
+	if err := checkScopes(span, data.Scopes, constants.Scopes); err != nil {
+		return nil, "", err
+	}
+
+	clientID := utils.NewUUID().String()
+	secret, secretHash := newClientSecret()
+
+	var serviceAccount *entities.ServiceAccount
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		serviceAccount, err = u.sar.Create(ctx, &adminID, clientID, secretHash, data)
+		if err != nil {
+			return err
+		}
+
+		details := map[string]any{
+			"service_account_id": serviceAccount.ID,
+			"client_id":          serviceAccount.ClientID,
+			"scopes":             serviceAccount.Scopes,
+		}
+		return u.audit(ctx, adminID, nil, constants.AdminActionServiceAccountCreated, details, request)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return serviceAccount, secret, nil
+}
+
+func (u *adminUsecase) RotateServiceAccountSecret(ctx context.Context, adminID, serviceAccountID int64, request *entities.Request) (*entities.ServiceAccount, string, error) {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "RotateServiceAccountSecret")
+	defer span.End()
+
+	secret, secretHash := newClientSecret()
+	previousExpiresAt := time.Now().UTC().Add(u.cfg.Auth.ServiceAccount.SecretGracePeriod)
+
+	var serviceAccount *entities.ServiceAccount
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		current, err := u.sar.GetByID(ctx, serviceAccountID)
+		if err != nil {
+			return err
+		}
+		if err := u.sar.RotateSecret(ctx, serviceAccountID, secretHash, previousExpiresAt); err != nil {
+			return err
+		}
+
+		// a secret still in its grace period is dropped by this rotation, so are its tokens
+		if current.PreviousSecretHash != nil {
+			if err := u.tdc.DenyClientSecret(ctx, current.ClientID, *current.PreviousSecretHash); err != nil {
+				return err
+			}
+		}
+
+		serviceAccount, err = u.sar.GetByID(ctx, serviceAccountID)
+		if err != nil {
+			return err
+		}
+
+		details := map[string]any{
+			"service_account_id":         serviceAccountID,
+			"previous_secret_expires_at": previousExpiresAt,
+		}
+		return u.audit(ctx, adminID, nil, constants.AdminActionServiceAccountSecretRotated, details, request)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return serviceAccount, secret, nil
+}
+
+func (u *adminUsecase) DisableServiceAccount(ctx context.Context, adminID, serviceAccountID int64, request *entities.Request) error {
+	ctx, span := otel.Tracer(adminErrorTracer).Start(ctx, "DisableServiceAccount")
+	defer span.End()
+
+	return u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		serviceAccount, err := u.sar.GetByID(ctx, serviceAccountID)
+		if err != nil {
+			return err
+		}
+		if err := u.sar.Disable(ctx, serviceAccountID); err != nil {
+			return err
+		}
+		if err := u.tdc.DenyClient(ctx, serviceAccount.ClientID); err != nil {
+			return err
+		}
+
+		details := map[string]any{"service_account_id": serviceAccountID}
+		return u.audit(ctx, adminID, nil, constants.AdminActionServiceAccountDisabled, details, request)
+	})
+}
+
+// failing to record an action fails the action itself
+func (u *adminUsecase) audit(ctx context.Context, adminID int64, targetID *int64, action string, details map[string]any, request *entities.Request) error {
+	data := entities.CreateAdminAudit{
+		TargetID:  targetID,
+		Action:    action,
+		Details:   details,
+		UserAgent: request.UserAgent,
+		IPAddress: request.IPAddress,
+	}
+	return u.aar.Create(ctx, &adminID, &data)
+}
+
+func checkSelfAction(span trace.Span, adminID, targetID int64) error {
+	if adminID == targetID {
+		err := fmt.Errorf("failed to check admin action: %w", errors.New("action targets own account"))
+		return ce.NewError(span, ce.CodeAdminSelfAction, "Cannot perform this action on your own account", err)
+	}
+	return nil
+}
+
+// one admin must not be able to lock another out
+func checkTargetRole(span trace.Span, account *entities.Account) error {
+	if account.RoleID == constants.RoleAdmin {
+		err := fmt.Errorf("failed to check admin action: %w", errors.New("action targets an admin account"))
+		return ce.NewError(span, ce.CodeAdminTargetForbidden, "Cannot perform this action on an admin account", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+)
+
+type guardAuthRepository struct {
+	repositories.AuthRepository
+	log     *callLog
+	account *entities.Account
+}
+
+func (r *guardAuthRepository) GetAccount(ctx context.Context, authID int64) (*entities.Account, error) {
+	return r.account, nil
+}
+
+func (r *guardAuthRepository) Suspend(ctx context.Context, authID int64, reason string) error {
+	r.log.add("auth.Suspend")
+	return nil
+}
+
+func (r *guardAuthRepository) RequirePasswordReset(ctx context.Context, authID int64) error {
+	r.log.add("auth.RequirePasswordReset")
+	return nil
+}
+
+type guardSessionUsecase struct {
+	SessionUsecase
+	log *callLog
+}
+
+func (u *guardSessionUsecase) RevokeAllSessions(ctx context.Context, authID int64, exceptToken string) error {
+	u.log.add("session.RevokeAllSessions")
+	return nil
+}
+
+func newGuardUsecase(t *testing.T, log *callLog, target *entities.Account) AdminUsecase {
+	t.Helper()
+
+	return NewAdminUsecase(
+		&guardAuthRepository{log: log, account: target},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		&guardSessionUsecase{log: log},
+		nil,
+		nil,
+		newTestTransactor(t),
+		nil,
+		&configs.Config{},
+	)
+}
+
+func TestAdminActionGuards(t *testing.T) {
+	const adminID int64 = 1
+
+	actions := map[string]func(u AdminUsecase, targetID int64) error{
+		"SuspendAccount": func(u AdminUsecase, targetID int64) error {
+			return u.SuspendAccount(context.Background(), adminID, targetID, "abuse", &entities.Request{})
+		},
+		"ForcePasswordReset": func(u AdminUsecase, targetID int64) error {
+			return u.ForcePasswordReset(context.Background(), adminID, targetID, &entities.Request{})
+		},
+		"RevokeSessions": func(u AdminUsecase, targetID int64) error {
+			return u.RevokeSessions(context.Background(), adminID, targetID, &entities.Request{})
+		},
+	}
+
+	tests := []struct {
+		name     string
+		target   *entities.Account
+		wantCode string
+	}{
+		{
+			name:     "admin target",
+			target:   &entities.Account{ID: 2, RoleID: constants.RoleAdmin, HasPassword: true},
+			wantCode: string(ce.CodeAdminTargetForbidden),
+		},
+		{
+			name:     "own account",
+			target:   &entities.Account{ID: adminID, RoleID: constants.RoleAdmin, HasPassword: true},
+			wantCode: string(ce.CodeAdminSelfAction),
+		},
+	}
+
+	for action, call := range actions {
+		for _, tt := range tests {
+			t.Run(action+"/"+tt.name, func(t *testing.T) {
+				log := &callLog{}
+				u := newGuardUsecase(t, log, tt.target)
+
+				err := call(u, tt.target.ID)
+
+				var cErr *ce.Error
+				if !errors.As(err, &cErr) || string(cErr.Code) != tt.wantCode {
+					t.Fatalf("got error %v, want code %s", err, tt.wantCode)
+				}
+				if len(log.calls) > 0 {
+					t.Errorf("nothing must change on a rejected action, calls: %v", log.calls)
+				}
+			})
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ritchieridanko/apotekly-api/auth/configs"
@@ -22,63 +24,117 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-// TODO
-// 1: Send verification token to notification service
-// 2: Send email change token to notification service
-// 3: Send reset token to notification service
-
 const authErrorTracer string = "usecase.auth"
 
 type AuthUsecase interface {
 	Register(ctx context.Context, data *entities.CreateAuth, request *entities.Request) (authToken *entities.AuthToken, createdAuth *entities.Auth, err error)
-	Login(ctx context.Context, data *entities.GetAuth, request *entities.Request) (authToken *entities.AuthToken, auth *entities.Auth, err error)
+	RegisterPharmacy(ctx context.Context, data *entities.CreateAuth, request *entities.Request) (authToken *entities.AuthToken, createdAuth *entities.Auth, err error)
+	Login(ctx context.Context, data *entities.GetAuth, request *entities.Request) (authToken *entities.AuthToken, auth *entities.Auth, mfaToken string, err error)
 	Logout(ctx context.Context, sessionToken string) (err error)
 	ChangeEmail(ctx context.Context, authID int64, email string) (recipientEmail string, err error)
-	ConfirmEmailChange(ctx context.Context, token, sessionToken string) (authToken *entities.AuthToken, updatedAuth *entities.Auth, err error)
-	ChangePassword(ctx context.Context, authID int64, data *entities.UpdatePassword) (err error)
+	ConfirmEmailChange(ctx context.Context, token, sessionToken string, request *entities.Request) (authToken *entities.AuthToken, updatedAuth *entities.Auth, err error)
+	ConfirmEmailChangeCode(ctx context.Context, authID int64, code, sessionToken string, request *entities.Request) (authToken *entities.AuthToken, updatedAuth *entities.Auth, err error)
+	ChangePassword(ctx context.Context, authID int64, sessionToken string, data *entities.UpdatePassword, request *entities.Request) (err error)
+	SetPassword(ctx context.Context, authID int64, password string) (err error)
 	ForgotPassword(ctx context.Context, email string) (recipientEmail string, err error)
-	ResetPassword(ctx context.Context, data *entities.ResetPassword) (err error)
+	ResetPassword(ctx context.Context, data *entities.ResetPassword, request *entities.Request) (err error)
+	RequestUnlock(ctx context.Context, email string) (recipientEmail string, err error)
+	UnlockAccount(ctx context.Context, token string) (err error)
 	ResendVerification(ctx context.Context, authID int64) (recipientEmail string, err error)
-	VerifyAccount(ctx context.Context, token, sessionToken string) (authToken *entities.AuthToken, updatedAuth *entities.Auth, err error)
-	RefreshSession(ctx context.Context, sessionToken string) (authToken *entities.AuthToken, err error)
+	VerifyAccount(ctx context.Context, token, sessionToken string, request *entities.Request) (authToken *entities.AuthToken, updatedAuth *entities.Auth, err error)
+	VerifyAccountCode(ctx context.Context, authID int64, code, sessionToken string, request *entities.Request) (authToken *entities.AuthToken, updatedAuth *entities.Auth, err error)
+	RefreshSession(ctx context.Context, sessionToken string, request *entities.Request) (authToken *entities.AuthToken, err error)
 	IsEmailRegistered(ctx context.Context, email string) (isRegistered bool, err error)
 	IsResetTokenValid(ctx context.Context, token string) (isValid bool, err error)
+	ConfirmIdentity(ctx context.Context, authID int64, data *entities.ConfirmIdentity, request *entities.Request) (auth *entities.Auth, err error)
+	RequestMagicLink(ctx context.Context, email, deviceID string, request *entities.Request) (recipientEmail string, err error)
+	ConfirmMagicLink(ctx context.Context, token, deviceID string, request *entities.Request) (authToken *entities.AuthToken, auth *entities.Auth, mfaToken string, err error)
+	RequestLoginCode(ctx context.Context, email string, request *entities.Request) (recipientEmail string, err error)
+	ConfirmLoginCode(ctx context.Context, email, code string, request *entities.Request) (authToken *entities.AuthToken, auth *entities.Auth, mfaToken string, err error)
 }
 
 type authUsecase struct {
 	ar         repositories.AuthRepository
+	par        repositories.PharmacyApplicationRepository
 	ac         caches.AuthCache
+	lc         caches.LockoutCache
 	su         SessionUsecase
+	seu        SecurityEventUsecase
+	sau        SignInAlertUsecase
+	mu         MFAUsecase
+	ppu        PasswordPolicyUsecase
 	aep        publishers.AuthEventPublisher
 	transactor *database.Transactor
-	bcrypt     *services.BCryptService
+	hasher     services.PasswordHasher
 	jwt        *services.JWTService
 	cfg        *configs.Config
 }
 
 func NewAuthUsecase(
 	ar repositories.AuthRepository,
+	par repositories.PharmacyApplicationRepository,
 	ac caches.AuthCache,
+	lc caches.LockoutCache,
 	su SessionUsecase,
+	seu SecurityEventUsecase,
+	sau SignInAlertUsecase,
+	mu MFAUsecase,
+	ppu PasswordPolicyUsecase,
 	aep publishers.AuthEventPublisher,
 	transactor *database.Transactor,
-	bcrypt *services.BCryptService,
+	hasher services.PasswordHasher,
 	jwt *services.JWTService,
 	cfg *configs.Config,
 ) AuthUsecase {
-	return &authUsecase{ar, ac, su, aep, transactor, bcrypt, jwt, cfg}
+	return &authUsecase{ar, par, ac, lc, su, seu, sau, mu, ppu, aep, transactor, hasher, jwt, cfg}
 }
 
 func (u *authUsecase) Register(ctx context.Context, data *entities.CreateAuth, request *entities.Request) (*entities.AuthToken, *entities.Auth, error) {
 	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "Register")
 	defer span.End()
 
+	data.RoleID = constants.RoleCustomer
+	data.IsApproved = true
+
+	return u.register(ctx, span, data, request)
+}
+
+func (u *authUsecase) RegisterPharmacy(ctx context.Context, data *entities.CreateAuth, request *entities.Request) (*entities.AuthToken, *entities.Auth, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "RegisterPharmacy")
+	defer span.End()
+
+	// an expired licence can never be approved, so it is not worth registering
+	if !data.Application.LicenseExpiry.After(time.Now().UTC()) {
+		err := fmt.Errorf("failed to register pharmacy: %w", errors.New("license expired"))
+		return nil, nil, ce.NewError(span, ce.CodePharmacyLicenseExpired, ce.MsgPharmacyLicenseExpired, err)
+	}
+
+	// the role stays inactive in the claims until an admin approves the account
+	data.RoleID = constants.RolePharmacy
+	data.IsApproved = !u.cfg.Auth.Pharmacy.RequireApproval
+	data.Application.LicenseNumber = strings.ToUpper(strings.TrimSpace(data.Application.LicenseNumber))
+
+	return u.register(ctx, span, data, request)
+}
+
+func (u *authUsecase) register(ctx context.Context, span trace.Span, data *entities.CreateAuth, request *entities.Request) (*entities.AuthToken, *entities.Auth, error) {
 	now := time.Now().UTC()
+	normalizedEmail := utils.Normalize(data.Email)
+
+	// checked before the transaction, the breached password lookup is a network call
+	if err := u.ppu.Check(ctx, &entities.Auth{Email: normalizedEmail}, *data.Password); err != nil {
+		return nil, nil, err
+	}
+
+	hashedPassword, err := u.hasher.Hash(*data.Password)
+	if err != nil {
+		wErr := fmt.Errorf("failed to register: %w", err)
+		return nil, nil, ce.NewError(span, ce.CodePasswordHashingFailed, ce.MsgInternalServer, wErr)
+	}
 
 	var auth *entities.Auth
 	var authToken entities.AuthToken
-	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
-		normalizedEmail := utils.Normalize(data.Email)
+	err = u.transactor.WithTx(ctx, func(ctx context.Context) error {
 		exists, err := u.ar.Exists(ctx, normalizedEmail)
 		if err != nil {
 			return err
@@ -97,25 +153,39 @@ func (u *authUsecase) Register(ctx context.Context, data *entities.CreateAuth, r
 			return ce.NewError(span, ce.CodeAuthEmailConflict, ce.MsgEmailAlreadyRegistered, err)
 		}
 
-		hashedPassword, err := u.bcrypt.Hash(*data.Password)
-		if err != nil {
-			wErr := fmt.Errorf("failed to register: %w", err)
-			return ce.NewError(span, ce.CodePasswordHashingFailed, ce.MsgInternalServer, wErr)
+		if data.Application != nil {
+			exists, err = u.par.IsLicenseRegistered(ctx, data.Application.LicenseNumber)
+			if err != nil {
+				return err
+			}
+			if exists {
+				err := fmt.Errorf("failed to register: %w", errors.New("license conflict"))
+				return ce.NewError(span, ce.CodePharmacyLicenseConflict, ce.MsgPharmacyLicenseConflict, err)
+			}
 		}
 
 		newAuthData := entities.CreateAuth{
-			Email:    normalizedEmail,
-			Password: &hashedPassword,
-			RoleID:   constants.RoleCustomer,
+			Email:      normalizedEmail,
+			Password:   &hashedPassword,
+			RoleID:     data.RoleID,
+			IsApproved: data.IsApproved,
 		}
 
 		auth, err = u.ar.Create(ctx, &newAuthData)
 		if err != nil {
 			return err
 		}
+		if err := u.ppu.Record(ctx, auth.ID, hashedPassword); err != nil {
+			return err
+		}
+		if data.Application != nil {
+			if err := u.par.Create(ctx, auth.ID, data.Application); err != nil {
+				return err
+			}
+		}
 
 		sessionToken := utils.NewUUID().String()
-		accessToken, err := u.jwt.Create(auth.ID, auth.RoleID, auth.IsVerified)
+		accessToken, err := u.jwt.Create(auth)
 		if err != nil {
 			wErr := fmt.Errorf("failed to register: %w", err)
 			return ce.NewError(span, ce.CodeJWTGenerationFailed, ce.MsgInternalServer, wErr)
@@ -130,86 +200,120 @@ func (u *authUsecase) Register(ctx context.Context, data *entities.CreateAuth, r
 		if err := u.su.CreateFirstSession(ctx, auth.ID, &newSessionData); err != nil {
 			return err
 		}
+		if err := u.su.TrackAccessToken(ctx, auth.ID, sessionToken, "", accessToken); err != nil {
+			log.Println("WARNING ->", err.Error())
+		}
 
 		authToken = entities.AuthToken{
-			AccessToken:  accessToken,
+			AccessToken:  accessToken.Token,
 			SessionToken: sessionToken,
 		}
 
-		return nil
+		verificationToken := utils.NewUUID().String()
+		err = u.ac.CreateVerificationToken(
+			ctx, auth.ID, verificationToken,
+			u.cfg.Auth.TokenDuration.Verification,
+		)
+		if err != nil {
+			// the account is still usable, verification can be resent later
+			log.Println("WARNING ->", err.Error())
+			return nil
+		}
+
+		// the code is optional, the link alone still verifies the account
+		verificationCode, err := issueOneTimeCode(
+			ctx, u.ac, constants.OTPPurposeVerification, strconv.FormatInt(auth.ID, 10),
+			auth.ID, "", u.cfg.Auth.TokenDuration.OTP,
+		)
+		if err != nil {
+			log.Println("WARNING ->", err.Error())
+		}
+
+		// committed together with the account, the outbox worker delivers it
+		return u.aep.PublishAuthRegistered(ctx, auth.ID, auth.Email, verificationToken, verificationCode)
 	})
 	if err != nil {
 		return nil, nil, err
 	}
 
-	verificationToken := utils.NewUUID().String()
-	err = u.ac.CreateVerificationToken(
-		ctx, auth.ID, verificationToken,
-		u.cfg.Auth.TokenDuration.Verification,
-	)
-	if err != nil {
-		log.Println("WARNING ->", err.Error())
-		return &authToken, auth, nil
-	}
-
-	if err := u.aep.PublishAuthRegistered(ctx, auth.ID, auth.Email, verificationToken); err != nil {
-		log.Println("WARNING ->", err.Error())
-	}
-
 	return &authToken, auth, nil
 }
 
-func (u *authUsecase) Login(ctx context.Context, data *entities.GetAuth, request *entities.Request) (*entities.AuthToken, *entities.Auth, error) {
+func (u *authUsecase) Login(ctx context.Context, data *entities.GetAuth, request *entities.Request) (*entities.AuthToken, *entities.Auth, string, error) {
 	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "Login")
 	defer span.End()
 
-	now := time.Now().UTC()
-
 	normalizedEmail := utils.Normalize(data.Email)
+	if err := u.checkLockout(ctx, span, 0, request.IPAddress); err != nil {
+		u.recordLoginFailure(ctx, nil, normalizedEmail, constants.LoginMethodPassword, constants.LoginFailureLocked)
+		return nil, nil, "", err
+	}
+
 	auth, err := u.ar.GetByEmail(ctx, normalizedEmail)
 	if err != nil {
-		return nil, nil, err
+		var cErr *ce.Error
+		if errors.As(err, &cErr) && cErr.Code == ce.CodeAuthNotFound {
+			u.recordFailure(ctx, 0, request.IPAddress)
+			u.recordLoginFailure(ctx, nil, normalizedEmail, constants.LoginMethodPassword, constants.LoginFailureUnknownEmail)
+		}
+		return nil, nil, "", err
+	}
+	if err := u.checkLockout(ctx, span, auth.ID, ""); err != nil {
+		u.recordLoginFailure(ctx, &auth.ID, "", constants.LoginMethodPassword, constants.LoginFailureLocked)
+		return nil, nil, "", err
 	}
 	if auth.Password == nil {
 		// this is an oauth type account
 		err := fmt.Errorf("failed to login: %w", errors.New("email registered as oauth"))
-		return nil, nil, ce.NewError(span, ce.CodeOAuthRegularLogin, ce.MsgInvalidCredentials, err)
+		return nil, nil, "", ce.NewError(span, ce.CodeOAuthRegularLogin, ce.MsgInvalidCredentials, err)
 	}
-	if err := u.bcrypt.Validate(*auth.Password, data.Password); err != nil {
+	if err := u.hasher.Validate(*auth.Password, data.Password); err != nil {
+		u.recordFailure(ctx, auth.ID, request.IPAddress)
+		u.recordLoginFailure(ctx, &auth.ID, "", constants.LoginMethodPassword, constants.LoginFailureWrongPassword)
 		wErr := fmt.Errorf("failed to login: %w", err)
-		return nil, nil, ce.NewError(span, ce.CodeAuthWrongPassword, ce.MsgInvalidCredentials, wErr)
+		return nil, nil, "", ce.NewError(span, ce.CodeAuthWrongPassword, ce.MsgInvalidCredentials, wErr)
 	}
+	u.resetLockout(ctx, auth.ID)
+	u.rehashPassword(ctx, auth, data.Password)
 
-	sessionToken := utils.NewUUID().String()
-	accessToken, err := u.jwt.Create(auth.ID, auth.RoleID, auth.IsVerified)
-	if err != nil {
-		wErr := fmt.Errorf("failed to login: %w", err)
-		return nil, nil, ce.NewError(span, ce.CodeJWTGenerationFailed, ce.MsgInternalServer, wErr)
+	// only revealed to whoever knows the password
+	if err := checkAccountState(span, auth); err != nil {
+		return nil, nil, "", err
 	}
 
-	newSessionData := entities.CreateSession{
-		Token:     sessionToken,
-		UserAgent: request.UserAgent,
-		IPAddress: request.IPAddress,
-		ExpiresAt: now.Add(u.cfg.Auth.TokenDuration.Session),
+	isMFAEnabled, err := u.mu.IsEnabled(ctx, auth.ID)
+	if err != nil {
+		return nil, nil, "", err
 	}
-	if err := u.su.CreateSession(ctx, auth.ID, &newSessionData); err != nil {
-		return nil, nil, err
+	if isMFAEnabled {
+		// session is created only after the second factor is verified
+		mfaToken, err := u.mu.CreatePendingToken(ctx, auth.ID)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		return nil, auth, mfaToken, nil
 	}
 
-	authToken := entities.AuthToken{
-		AccessToken:  accessToken,
-		SessionToken: sessionToken,
+	authToken, err := u.startSession(ctx, span, auth, request, constants.LoginMethodPassword)
+	if err != nil {
+		return nil, nil, "", err
 	}
 
-	return &authToken, auth, nil
+	return authToken, auth, "", nil
 }
 
 func (u *authUsecase) Logout(ctx context.Context, sessionToken string) error {
 	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "Logout")
 	defer span.End()
 
-	return u.su.RevokeSession(ctx, sessionToken)
+	if err := u.su.RevokeSession(ctx, sessionToken); err != nil {
+		return err
+	}
+
+	if authID, err := utils.CtxGetAuthID(ctx); err == nil {
+		u.seu.Record(ctx, &authID, constants.EventTypeLogout, nil)
+	}
+	return nil
 }
 
 func (u *authUsecase) ChangeEmail(ctx context.Context, authID int64, email string) (string, error) {
@@ -255,12 +359,21 @@ func (u *authUsecase) ChangeEmail(ctx context.Context, authID int64, email strin
 		return "", err
 	}
 
-	// TODO (2)
+	code, err := issueOneTimeCode(
+		ctx, u.ac, constants.OTPPurposeEmailChange, strconv.FormatInt(auth.ID, 10),
+		auth.ID, normalizedEmail, u.cfg.Auth.TokenDuration.OTP,
+	)
+	if err != nil {
+		return "", err
+	}
+	if err := u.aep.PublishEmailChangeRequested(ctx, auth.ID, auth.Email, normalizedEmail, token, code); err != nil {
+		return "", err
+	}
 
 	return normalizedEmail, nil
 }
 
-func (u *authUsecase) ConfirmEmailChange(ctx context.Context, token, sessionToken string) (*entities.AuthToken, *entities.Auth, error) {
+func (u *authUsecase) ConfirmEmailChange(ctx context.Context, token, sessionToken string, request *entities.Request) (*entities.AuthToken, *entities.Auth, error) {
 	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "ConfirmEmailChange")
 	defer span.End()
 
@@ -269,16 +382,47 @@ func (u *authUsecase) ConfirmEmailChange(ctx context.Context, token, sessionToke
 		return nil, nil, err
 	}
 
+	return u.changeEmail(ctx, span, authID, newEmail, sessionToken, request)
+}
+
+func (u *authUsecase) ConfirmEmailChangeCode(ctx context.Context, authID int64, code, sessionToken string, request *entities.Request) (*entities.AuthToken, *entities.Auth, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "ConfirmEmailChangeCode")
+	defer span.End()
+
+	subject := strconv.FormatInt(authID, 10)
+	_, newEmail, err := u.ac.UseOneTimeCode(
+		ctx, constants.OTPPurposeEmailChange, subject,
+		hashOneTimeCode(constants.OTPPurposeEmailChange, subject, code),
+		u.cfg.Auth.OTP.MaxAttempts,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return u.changeEmail(ctx, span, authID, newEmail, sessionToken, request)
+}
+
+func (u *authUsecase) changeEmail(ctx context.Context, span trace.Span, authID int64, newEmail, sessionToken string, request *entities.Request) (*entities.AuthToken, *entities.Auth, error) {
 	var auth *entities.Auth
 	var authToken *entities.AuthToken
-	err = u.transactor.WithTx(ctx, func(ctx context.Context) error {
+	var previousEmail string
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		current, err := u.ar.GetByID(ctx, authID)
+		if err != nil {
+			return err
+		}
+		previousEmail = current.Email
+
 		auth, err = u.ar.UpdateEmail(ctx, authID, newEmail)
 		if err != nil {
 			return err
 		}
+		if err := u.aep.PublishEmailChanged(ctx, auth.ID, current.Email, auth.Email); err != nil {
+			return err
+		}
 
 		if sessionToken != "" {
-			authToken, err = u.RefreshSession(ctx, sessionToken)
+			authToken, _, err = u.refreshSession(ctx, span, sessionToken, request)
 			if err != nil {
 				// non-fatal: trace the failure, but continue
 				span.AddEvent(
@@ -293,38 +437,109 @@ func (u *authUsecase) ConfirmEmailChange(ctx context.Context, token, sessionToke
 	if err := u.ac.UnreserveEmail(ctx, newEmail); err != nil {
 		return nil, nil, err
 	}
+	if err == nil {
+		details := map[string]any{"old_email": previousEmail, "new_email": auth.Email}
+		u.seu.Record(ctx, &authID, constants.EventTypeEmailChanged, details)
+	}
 
 	return authToken, auth, err
 }
 
-func (u *authUsecase) ChangePassword(ctx context.Context, authID int64, data *entities.UpdatePassword) error {
+func (u *authUsecase) ChangePassword(ctx context.Context, authID int64, sessionToken string, data *entities.UpdatePassword, request *entities.Request) error {
 	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "ChangePassword")
 	defer span.End()
 
-	return u.transactor.WithTx(ctx, func(ctx context.Context) error {
-		auth, err := u.ar.GetByID(ctx, authID)
-		if err != nil {
+	if err := u.checkLockout(ctx, span, authID, request.IPAddress); err != nil {
+		return err
+	}
+
+	// checked before the transaction, the breached password lookup is a network call
+	auth, err := u.ar.GetByID(ctx, authID)
+	if err != nil {
+		return err
+	}
+	if auth.Password == nil {
+		// this is an oauth type account
+		// password cannot be changed for oauth accounts
+		err := fmt.Errorf("failed to change password: %w", errors.New("password change with oauth account"))
+		return ce.NewError(span, ce.CodeOAuthPasswordChange, "OAuth account cannot change password", err)
+	}
+	if err := u.hasher.Validate(*auth.Password, data.OldPassword); err != nil {
+		u.recordFailure(ctx, authID, request.IPAddress)
+		wErr := fmt.Errorf("failed to change password: %w", err)
+		return ce.NewError(span, ce.CodeAuthWrongPassword, "Invalid old password", wErr)
+	}
+	if err := u.ppu.Check(ctx, auth, data.NewPassword); err != nil {
+		return err
+	}
+
+	hashedNewPassword, err := u.hasher.Hash(data.NewPassword)
+	if err != nil {
+		wErr := fmt.Errorf("failed to change password: %w", err)
+		return ce.NewError(span, ce.CodePasswordHashingFailed, ce.MsgInternalServer, wErr)
+	}
+
+	err = u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		if err := u.ar.UpdatePassword(ctx, auth.ID, hashedNewPassword); err != nil {
 			return err
 		}
-		if auth.Password == nil {
-			// this is an oauth type account
-			// password cannot be changed for oauth accounts
-			err := fmt.Errorf("failed to change password: %w", errors.New("password change with oauth account"))
-			return ce.NewError(span, ce.CodeOAuthPasswordChange, "OAuth account cannot change password", err)
+		if err := u.ppu.Record(ctx, auth.ID, hashedNewPassword); err != nil {
+			return err
 		}
-		if err := u.bcrypt.Validate(*auth.Password, data.OldPassword); err != nil {
-			wErr := fmt.Errorf("failed to change password: %w", err)
-			return ce.NewError(span, ce.CodeAuthWrongPassword, "Invalid old password", wErr)
+		if err := u.aep.PublishPasswordChanged(ctx, auth.ID, auth.Email); err != nil {
+			return err
 		}
 
-		hashedNewPassword, err := u.bcrypt.Hash(data.NewPassword)
-		if err != nil {
-			wErr := fmt.Errorf("failed to change password: %w", err)
-			return ce.NewError(span, ce.CodePasswordHashingFailed, ce.MsgInternalServer, wErr)
-		}
+		return u.su.RevokeAllSessions(ctx, auth.ID, sessionToken)
+	})
+	if err != nil {
+		return err
+	}
 
-		return u.ar.UpdatePassword(ctx, auth.ID, hashedNewPassword)
+	u.resetLockout(ctx, authID)
+	u.seu.Record(ctx, &authID, constants.EventTypePasswordChanged, map[string]any{"via": "change"})
+	return nil
+}
+
+func (u *authUsecase) SetPassword(ctx context.Context, authID int64, password string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "SetPassword")
+	defer span.End()
+
+	// checked before the transaction, the breached password lookup is a network call
+	auth, err := u.ar.GetByID(ctx, authID)
+	if err != nil {
+		return err
+	}
+	if auth.Password != nil {
+		// an existing password goes through the change flow, which asks for the old one
+		err := fmt.Errorf("failed to set password: %w", errors.New("password already set"))
+		return ce.NewError(span, ce.CodeOAuthPasswordSet, "Password is already set", err)
+	}
+	if err := u.ppu.Check(ctx, auth, password); err != nil {
+		return err
+	}
+
+	hashedPassword, err := u.hasher.Hash(password)
+	if err != nil {
+		wErr := fmt.Errorf("failed to set password: %w", err)
+		return ce.NewError(span, ce.CodePasswordHashingFailed, ce.MsgInternalServer, wErr)
+	}
+
+	err = u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		if err := u.ar.UpdatePassword(ctx, auth.ID, hashedPassword); err != nil {
+			return err
+		}
+		if err := u.ppu.Record(ctx, auth.ID, hashedPassword); err != nil {
+			return err
+		}
+		return u.aep.PublishPasswordChanged(ctx, auth.ID, auth.Email)
 	})
+	if err != nil {
+		return err
+	}
+
+	u.seu.Record(ctx, &authID, constants.EventTypePasswordChanged, map[string]any{"via": "set"})
+	return nil
 }
 
 func (u *authUsecase) ForgotPassword(ctx context.Context, email string) (string, error) {
@@ -347,28 +562,124 @@ func (u *authUsecase) ForgotPassword(ctx context.Context, email string) (string,
 	if err := u.ac.CreateResetToken(ctx, auth.ID, token, u.cfg.Auth.TokenDuration.Reset); err != nil {
 		return "", err
 	}
-
-	// TODO (3)
+	if err := u.aep.PublishPasswordResetRequested(ctx, auth.ID, auth.Email, token); err != nil {
+		return "", err
+	}
 
 	return normalizedEmail, nil
 }
 
-func (u *authUsecase) ResetPassword(ctx context.Context, data *entities.ResetPassword) error {
+func (u *authUsecase) ResetPassword(ctx context.Context, data *entities.ResetPassword, request *entities.Request) error {
 	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "ResetPassword")
 	defer span.End()
 
-	authID, err := u.ac.UseResetToken(ctx, data.Token)
+	if err := u.checkLockout(ctx, span, 0, request.IPAddress); err != nil {
+		return err
+	}
+
+	authID, err := u.ac.GetResetToken(ctx, data.Token)
+	if err != nil {
+		var cErr *ce.Error
+		if errors.As(err, &cErr) && cErr.Code == ce.CodeCacheValueNotFound {
+			u.recordFailure(ctx, 0, request.IPAddress)
+		}
+		return err
+	}
+
+	// checked before the token is used, so a rejected password leaves the link working
+	auth, err := u.ar.GetByID(ctx, authID)
 	if err != nil {
 		return err
 	}
+	if err := u.ppu.Check(ctx, auth, data.NewPassword); err != nil {
+		return err
+	}
 
-	hashedNewPassword, err := u.bcrypt.Hash(data.NewPassword)
+	authID, err = u.ac.UseResetToken(ctx, data.Token)
+	if err != nil {
+		return err
+	}
+
+	hashedNewPassword, err := u.hasher.Hash(data.NewPassword)
 	if err != nil {
 		wErr := fmt.Errorf("failed to reset password: %w", err)
 		return ce.NewError(span, ce.CodePasswordHashingFailed, ce.MsgInternalServer, wErr)
 	}
 
-	return u.ar.UpdatePassword(ctx, authID, hashedNewPassword)
+	err = u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		auth, err := u.ar.GetByID(ctx, authID)
+		if err != nil {
+			return err
+		}
+
+		if err := u.ar.UpdatePassword(ctx, auth.ID, hashedNewPassword); err != nil {
+			return err
+		}
+		if err := u.ppu.Record(ctx, auth.ID, hashedNewPassword); err != nil {
+			return err
+		}
+		if err := u.aep.PublishPasswordChanged(ctx, auth.ID, auth.Email); err != nil {
+			return err
+		}
+
+		// whoever knew the old password may still be signed in somewhere
+		return u.su.RevokeAllSessions(ctx, authID, "")
+	})
+	if err != nil {
+		return err
+	}
+
+	// proving ownership of the email lifts any lockout on the account
+	u.resetLockout(ctx, authID)
+	u.seu.Record(ctx, &authID, constants.EventTypePasswordChanged, map[string]any{"via": "reset"})
+	return nil
+}
+
+func (u *authUsecase) RequestUnlock(ctx context.Context, email string) (string, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "RequestUnlock")
+	defer span.End()
+
+	normalizedEmail := utils.Normalize(email)
+	auth, err := u.ar.GetByEmail(ctx, normalizedEmail)
+	if err != nil {
+		var cErr *ce.Error
+		if errors.As(err, &cErr) && cErr.Code == ce.CodeAuthNotFound {
+			// unknown emails get the same answer, so the endpoint cannot be used to probe for accounts
+			return normalizedEmail, nil
+		}
+		return "", err
+	}
+
+	// nothing to unlock, and the answer must not tell a locked account apart
+	lockout, err := u.lc.GetLockout(ctx, auth.ID, "")
+	if err != nil {
+		return "", err
+	}
+	if !lockout.IsLocked {
+		return normalizedEmail, nil
+	}
+
+	token := utils.NewUUID().String()
+	if err := u.lc.CreateUnlockToken(ctx, auth.ID, token, u.cfg.Auth.TokenDuration.Unlock); err != nil {
+		return "", err
+	}
+	if err := u.aep.PublishUnlockRequested(ctx, auth.ID, auth.Email, token); err != nil {
+		return "", err
+	}
+
+	return normalizedEmail, nil
+}
+
+func (u *authUsecase) UnlockAccount(ctx context.Context, token string) error {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "UnlockAccount")
+	defer span.End()
+
+	authID, err := u.lc.UseUnlockToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return u.lc.ResetAccount(ctx, authID)
 }
 
 func (u *authUsecase) ResendVerification(ctx context.Context, authID int64) (string, error) {
@@ -393,12 +704,21 @@ func (u *authUsecase) ResendVerification(ctx context.Context, authID int64) (str
 		return "", err
 	}
 
-	// TODO (1)
+	code, err := issueOneTimeCode(
+		ctx, u.ac, constants.OTPPurposeVerification, strconv.FormatInt(auth.ID, 10),
+		auth.ID, "", u.cfg.Auth.TokenDuration.OTP,
+	)
+	if err != nil {
+		return "", err
+	}
+	if err := u.aep.PublishVerificationRequested(ctx, auth.ID, auth.Email, token, code); err != nil {
+		return "", err
+	}
 
 	return auth.Email, nil
 }
 
-func (u *authUsecase) VerifyAccount(ctx context.Context, token, sessionToken string) (*entities.AuthToken, *entities.Auth, error) {
+func (u *authUsecase) VerifyAccount(ctx context.Context, token, sessionToken string, request *entities.Request) (*entities.AuthToken, *entities.Auth, error) {
 	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "VerifyAccount")
 	defer span.End()
 
@@ -407,16 +727,41 @@ func (u *authUsecase) VerifyAccount(ctx context.Context, token, sessionToken str
 		return nil, nil, err
 	}
 
+	return u.verify(ctx, span, authID, sessionToken, request)
+}
+
+func (u *authUsecase) VerifyAccountCode(ctx context.Context, authID int64, code, sessionToken string, request *entities.Request) (*entities.AuthToken, *entities.Auth, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "VerifyAccountCode")
+	defer span.End()
+
+	subject := strconv.FormatInt(authID, 10)
+	_, _, err := u.ac.UseOneTimeCode(
+		ctx, constants.OTPPurposeVerification, subject,
+		hashOneTimeCode(constants.OTPPurposeVerification, subject, code),
+		u.cfg.Auth.OTP.MaxAttempts,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return u.verify(ctx, span, authID, sessionToken, request)
+}
+
+func (u *authUsecase) verify(ctx context.Context, span trace.Span, authID int64, sessionToken string, request *entities.Request) (*entities.AuthToken, *entities.Auth, error) {
 	var auth *entities.Auth
 	var authToken *entities.AuthToken
-	err = u.transactor.WithTx(ctx, func(ctx context.Context) error {
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var err error
 		auth, err = u.ar.SetVerified(ctx, authID)
 		if err != nil {
 			return err
 		}
+		if err := u.aep.PublishAccountVerified(ctx, auth.ID, auth.Email); err != nil {
+			return err
+		}
 
 		if sessionToken != "" {
-			authToken, err = u.RefreshSession(ctx, sessionToken)
+			authToken, _, err = u.refreshSession(ctx, span, sessionToken, request)
 			if err != nil {
 				// non-fatal: trace the failure, but continue
 				span.AddEvent(
@@ -427,38 +772,97 @@ func (u *authUsecase) VerifyAccount(ctx context.Context, token, sessionToken str
 		}
 		return nil
 	})
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return authToken, auth, err
+	u.seu.Record(ctx, &authID, constants.EventTypeAccountVerified, nil)
+	return authToken, auth, nil
 }
 
-func (u *authUsecase) RefreshSession(ctx context.Context, sessionToken string) (*entities.AuthToken, error) {
+func (u *authUsecase) RefreshSession(ctx context.Context, sessionToken string, request *entities.Request) (*entities.AuthToken, error) {
 	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "RefreshSession")
 	defer span.End()
 
+	authToken, authID, err := u.refreshSession(ctx, span, sessionToken, request)
+	if err != nil {
+		return nil, err
+	}
+
+	u.seu.Record(ctx, &authID, constants.EventTypeSessionRefreshed, nil)
+	return authToken, nil
+}
+
+// also used by flows that refresh from inside their own transaction, which record their own event
+func (u *authUsecase) refreshSession(ctx context.Context, span trace.Span, sessionToken string, request *entities.Request) (*entities.AuthToken, int64, error) {
 	now := time.Now().UTC()
 
+	var authID int64
 	var authToken entities.AuthToken
+	var reusedSession *entities.Session
 	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
 		session, err := u.su.GetSession(ctx, sessionToken)
 		if err != nil {
 			return err
 		}
+		authID = session.AuthID
 		if !session.ExpiresAt.After(now) {
 			err := fmt.Errorf("failed to refresh session: %w", ce.ErrSessionExpired)
 			return ce.NewError(span, ce.CodeSessionExpired, ce.MsgUnauthenticated, err)
 		}
 		if session.RevokedAt != nil {
-			err := fmt.Errorf("failed to refresh session: %w", ce.ErrSessionRevoked)
-			return ce.NewError(span, ce.CodeSessionRevoked, ce.MsgUnauthenticated, err)
+			replacement, err := u.su.GetReplacement(ctx, session.ID)
+			if err != nil {
+				// revoked without being rotated (e.g. logged out)
+				err := fmt.Errorf("failed to refresh session: %w", ce.ErrSessionRevoked)
+				return ce.NewError(span, ce.CodeSessionRevoked, ce.MsgUnauthenticated, err)
+			}
+
+			// a rotated token presented again shortly after rotation is most likely
+			// a concurrent refresh from the same client, so it gets the replacement;
+			// from anywhere else it is treated as a leak
+			isActive := replacement.RevokedAt == nil && replacement.ExpiresAt.After(now)
+			isSameClient := request.UserAgent == session.UserAgent && request.IPAddress == session.IPAddress
+			if !isActive || !isSameClient || now.Sub(*session.RevokedAt) > u.cfg.Auth.Session.ReuseGrace {
+				reusedSession = session
+				return nil
+			}
+
+			auth, err := u.ar.GetByID(ctx, session.AuthID)
+			if err != nil {
+				return err
+			}
+			if err := checkAccountState(span, auth); err != nil {
+				return err
+			}
+
+			accessToken, err := u.jwt.Create(auth)
+			if err != nil {
+				wErr := fmt.Errorf("failed to refresh session: %w", err)
+				return ce.NewError(span, ce.CodeJWTGenerationFailed, ce.MsgInternalServer, wErr)
+			}
+			if err := u.su.TrackAccessToken(ctx, auth.ID, replacement.Token, "", accessToken); err != nil {
+				log.Println("WARNING ->", err.Error())
+			}
+
+			authToken = entities.AuthToken{
+				AccessToken:  accessToken.Token,
+				SessionToken: replacement.Token,
+			}
+
+			return nil
 		}
 
 		auth, err := u.ar.GetByID(ctx, session.AuthID)
 		if err != nil {
 			return err
 		}
+		if err := checkAccountState(span, auth); err != nil {
+			return err
+		}
 
 		newSessionToken := utils.NewUUID().String()
-		newAccessToken, err := u.jwt.Create(auth.ID, auth.RoleID, auth.IsVerified)
+		newAccessToken, err := u.jwt.Create(auth)
 		if err != nil {
 			wErr := fmt.Errorf("failed to refresh session: %w", err)
 			return ce.NewError(span, ce.CodeJWTGenerationFailed, ce.MsgInternalServer, wErr)
@@ -474,16 +878,25 @@ func (u *authUsecase) RefreshSession(ctx context.Context, sessionToken string) (
 		if err := u.su.RefreshSession(ctx, auth.ID, &newSessionData); err != nil {
 			return err
 		}
+		if err := u.su.TrackAccessToken(ctx, auth.ID, newSessionToken, sessionToken, newAccessToken); err != nil {
+			log.Println("WARNING ->", err.Error())
+		}
 
 		authToken = entities.AuthToken{
-			AccessToken:  newAccessToken,
+			AccessToken:  newAccessToken.Token,
 			SessionToken: newSessionToken,
 		}
 
 		return nil
 	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if reusedSession != nil {
+		return nil, 0, u.handleSessionReuse(ctx, span, reusedSession)
+	}
 
-	return &authToken, err
+	return &authToken, authID, nil
 }
 
 func (u *authUsecase) IsEmailRegistered(ctx context.Context, email string) (bool, error) {
@@ -508,3 +921,364 @@ func (u *authUsecase) IsResetTokenValid(ctx context.Context, token string) (bool
 
 	return u.ac.ResetTokenExists(ctx, token)
 }
+
+func (u *authUsecase) ConfirmIdentity(ctx context.Context, authID int64, data *entities.ConfirmIdentity, request *entities.Request) (*entities.Auth, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "ConfirmIdentity")
+	defer span.End()
+
+	if err := u.checkLockout(ctx, span, authID, request.IPAddress); err != nil {
+		return nil, err
+	}
+
+	auth, err := u.ar.GetByID(ctx, authID)
+	if err != nil {
+		return nil, err
+	}
+	isMFAEnabled, err := u.mu.IsEnabled(ctx, auth.ID)
+	if err != nil {
+		return nil, err
+	}
+	if auth.Password == nil && !isMFAEnabled {
+		// a valid access token alone is not enough for a sensitive action
+		err := fmt.Errorf("failed to confirm identity: %w", errors.New("no password or mfa to confirm with"))
+		return nil, ce.NewError(span, ce.CodeStepUpUnavailable, "Set a password or enable two-factor authentication first", err)
+	}
+
+	if auth.Password != nil {
+		if err := u.hasher.Validate(*auth.Password, data.Password); err != nil {
+			u.recordFailure(ctx, auth.ID, request.IPAddress)
+			wErr := fmt.Errorf("failed to confirm identity: %w", err)
+			return nil, ce.NewError(span, ce.CodeAuthWrongPassword, "Invalid password", wErr)
+		}
+	}
+	if isMFAEnabled {
+		if err := u.mu.VerifyCode(ctx, auth.ID, data.Code); err != nil {
+			// codes are short, guessing them counts towards the lockout just like passwords
+			var cErr *ce.Error
+			if errors.As(err, &cErr) && cErr.Code == ce.CodeMFAInvalidCode {
+				u.recordFailure(ctx, auth.ID, request.IPAddress)
+			}
+			return nil, err
+		}
+	}
+	u.resetLockout(ctx, auth.ID)
+
+	return auth, nil
+}
+
+func (u *authUsecase) RequestMagicLink(ctx context.Context, email, deviceID string, request *entities.Request) (string, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "RequestMagicLink")
+	defer span.End()
+
+	normalizedEmail := utils.Normalize(email)
+	auth, err := u.ar.GetByEmail(ctx, normalizedEmail)
+	if err != nil {
+		var cErr *ce.Error
+		if errors.As(err, &cErr) && cErr.Code == ce.CodeAuthNotFound {
+			// unknown emails get the same answer, so the endpoint cannot be used to probe for accounts
+			return normalizedEmail, nil
+		}
+		return "", err
+	}
+
+	token := utils.NewRandomToken()
+	err = u.ac.CreateMagicLinkToken(
+		ctx, auth.ID, token, utils.HashSHA256(deviceID),
+		u.cfg.Auth.TokenDuration.MagicLink,
+	)
+	if err != nil {
+		return "", err
+	}
+	if err := u.aep.PublishMagicLinkRequested(ctx, auth.ID, auth.Email, token, request); err != nil {
+		return "", err
+	}
+
+	return normalizedEmail, nil
+}
+
+func (u *authUsecase) ConfirmMagicLink(ctx context.Context, token, deviceID string, request *entities.Request) (*entities.AuthToken, *entities.Auth, string, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "ConfirmMagicLink")
+	defer span.End()
+
+	authID, err := u.ac.UseMagicLinkToken(ctx, token, utils.HashSHA256(deviceID))
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	auth, err := u.ar.GetByID(ctx, authID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return u.completeEmailLogin(ctx, span, auth, request, constants.LoginMethodMagicLink)
+}
+
+func (u *authUsecase) RequestLoginCode(ctx context.Context, email string, request *entities.Request) (string, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "RequestLoginCode")
+	defer span.End()
+
+	normalizedEmail := utils.Normalize(email)
+	auth, err := u.ar.GetByEmail(ctx, normalizedEmail)
+	if err != nil {
+		var cErr *ce.Error
+		if errors.As(err, &cErr) && cErr.Code == ce.CodeAuthNotFound {
+			// unknown emails get the same answer, so the endpoint cannot be used to probe for accounts
+			return normalizedEmail, nil
+		}
+		return "", err
+	}
+
+	code, err := issueOneTimeCode(
+		ctx, u.ac, constants.OTPPurposeLogin, normalizedEmail,
+		auth.ID, "", u.cfg.Auth.TokenDuration.OTP,
+	)
+	if err != nil {
+		return "", err
+	}
+	if err := u.aep.PublishLoginCodeRequested(ctx, auth.ID, auth.Email, code, request); err != nil {
+		return "", err
+	}
+
+	return normalizedEmail, nil
+}
+
+func (u *authUsecase) ConfirmLoginCode(ctx context.Context, email, code string, request *entities.Request) (*entities.AuthToken, *entities.Auth, string, error) {
+	ctx, span := otel.Tracer(authErrorTracer).Start(ctx, "ConfirmLoginCode")
+	defer span.End()
+
+	normalizedEmail := utils.Normalize(email)
+	authID, _, err := u.ac.UseOneTimeCode(
+		ctx, constants.OTPPurposeLogin, normalizedEmail,
+		hashOneTimeCode(constants.OTPPurposeLogin, normalizedEmail, code),
+		u.cfg.Auth.OTP.MaxAttempts,
+	)
+	if err != nil {
+		var cErr *ce.Error
+		if errors.As(err, &cErr) && (cErr.Code == ce.CodeOTPInvalid || cErr.Code == ce.CodeOTPAttemptsExceeded) {
+			u.recordLoginFailure(ctx, nil, normalizedEmail, constants.LoginMethodEmailCode, constants.LoginFailureWrongCode)
+		}
+		return nil, nil, "", err
+	}
+
+	auth, err := u.ar.GetByID(ctx, authID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return u.completeEmailLogin(ctx, span, auth, request, constants.LoginMethodEmailCode)
+}
+
+func (u *authUsecase) checkLockout(ctx context.Context, span trace.Span, authID int64, ipAddress string) error {
+	lockout, err := u.lc.GetLockout(ctx, authID, ipAddress)
+	if err != nil {
+		return err
+	}
+	if lockout.IsLocked {
+		err := fmt.Errorf("failed to check lockout: %w", errors.New("locked due to failed attempts"))
+		return ce.NewError(span, ce.CodeAuthLocked, ce.MsgAuthLocked, err).WithRetryAfter(lockout.RetryAfter)
+	}
+	if lockout.RetryAfter > 0 {
+		err := fmt.Errorf("failed to check lockout: %w", errors.New("throttled due to failed attempts"))
+		return ce.NewError(span, ce.CodeAuthThrottled, ce.MsgAuthThrottled, err).WithRetryAfter(lockout.RetryAfter)
+	}
+	return nil
+}
+
+// suspended accounts and those pending a forced reset cannot be signed into
+func checkAccountState(span trace.Span, auth *entities.Auth) error {
+	if auth.SuspendedAt != nil {
+		err := fmt.Errorf("failed to check account state: %w", errors.New("account suspended"))
+		return ce.NewError(span, ce.CodeAccountSuspended, ce.MsgAccountSuspended, err)
+	}
+	if auth.PasswordResetRequired {
+		err := fmt.Errorf("failed to check account state: %w", errors.New("password reset required"))
+		return ce.NewError(span, ce.CodePasswordResetRequired, ce.MsgPasswordResetRequired, err)
+	}
+	return nil
+}
+
+// the purpose and subject are mixed in so equal codes never share a hash
+func hashOneTimeCode(purpose, subject, code string) string {
+	return utils.HashSHA256(purpose + ":" + subject + ":" + code)
+}
+
+func issueOneTimeCode(ctx context.Context, ac caches.AuthCache, purpose, subject string, authID int64, payload string, duration time.Duration) (string, error) {
+	code := utils.NewOneTimeCode()
+	err := ac.CreateOneTimeCode(
+		ctx, purpose, subject, authID, payload,
+		hashOneTimeCode(purpose, subject, code), duration,
+	)
+	if err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+func (u *authUsecase) recordFailure(ctx context.Context, authID int64, ipAddress string) {
+	// non-fatal: failing to record must not mask the original error
+	if ipAddress != "" {
+		// shared networks are only ever locked, never delayed
+		policy := entities.LockoutPolicy{
+			MaxAttempts: u.cfg.Auth.Lockout.IPMaxAttempts,
+			Window:      u.cfg.Auth.Lockout.Window,
+			Duration:    u.cfg.Auth.Lockout.Duration,
+		}
+		if _, err := u.lc.RecordIPFailure(ctx, ipAddress, &policy); err != nil {
+			log.Println("WARNING ->", err.Error())
+		}
+	}
+	if authID == 0 {
+		return
+	}
+
+	policy := entities.LockoutPolicy{
+		MaxAttempts: u.cfg.Auth.Lockout.MaxAttempts,
+		Window:      u.cfg.Auth.Lockout.Window,
+		Duration:    u.cfg.Auth.Lockout.Duration,
+		BaseDelay:   u.cfg.Auth.Lockout.BaseDelay,
+		MaxDelay:    u.cfg.Auth.Lockout.MaxDelay,
+	}
+	isLocked, err := u.lc.RecordAccountFailure(ctx, authID, &policy)
+	if err != nil {
+		log.Println("WARNING ->", err.Error())
+		return
+	}
+	if !isLocked {
+		return
+	}
+	u.seu.Record(ctx, &authID, constants.EventTypeAccountLocked, nil)
+
+	token := utils.NewUUID().String()
+	if err := u.lc.CreateUnlockToken(ctx, authID, token, u.cfg.Auth.TokenDuration.Unlock); err != nil {
+		log.Println("WARNING ->", err.Error())
+		return
+	}
+
+	auth, err := u.ar.GetByID(ctx, authID)
+	if err != nil {
+		log.Println("WARNING ->", err.Error())
+		return
+	}
+	if err := u.aep.PublishUnlockRequested(ctx, auth.ID, auth.Email, token); err != nil {
+		log.Println("WARNING ->", err.Error())
+	}
+}
+
+func (u *authUsecase) recordLoginFailure(ctx context.Context, authID *int64, email, method, reason string) {
+	details := map[string]any{"method": method, "reason": reason}
+	if authID == nil {
+		// the only clue to which account was targeted, hashed so the log holds no address
+		details["email_hash"] = utils.HashSHA256(utils.Normalize(email))
+	}
+	u.seu.Record(ctx, authID, constants.EventTypeLoginFailed, details)
+}
+
+func (u *authUsecase) resetLockout(ctx context.Context, authID int64) {
+	if err := u.lc.ResetAccount(ctx, authID); err != nil {
+		log.Println("WARNING ->", err.Error())
+	}
+}
+
+// non-fatal: upgrades an outdated hash while the plain password is at hand
+func (u *authUsecase) rehashPassword(ctx context.Context, auth *entities.Auth, password string) {
+	if !u.hasher.NeedsRehash(*auth.Password) {
+		return
+	}
+
+	hashedPassword, err := u.hasher.Hash(password)
+	if err != nil {
+		log.Println("WARNING ->", fmt.Errorf("failed to rehash password: %w", err).Error())
+		return
+	}
+	if err := u.ar.UpdatePasswordHash(ctx, auth.ID, *auth.Password, hashedPassword); err != nil {
+		log.Println("WARNING ->", err.Error())
+		return
+	}
+	auth.Password = &hashedPassword
+}
+
+// finishes a sign-in proven by a link or code sent to the account's email
+func (u *authUsecase) completeEmailLogin(ctx context.Context, span trace.Span, auth *entities.Auth, request *entities.Request, method string) (*entities.AuthToken, *entities.Auth, string, error) {
+	// control of the inbox is proven, the same as an unlock link would
+	u.resetLockout(ctx, auth.ID)
+
+	if err := checkAccountState(span, auth); err != nil {
+		return nil, nil, "", err
+	}
+
+	// the email stands in for the password only, the second factor is still required
+	isMFAEnabled, err := u.mu.IsEnabled(ctx, auth.ID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if isMFAEnabled {
+		mfaToken, err := u.mu.CreatePendingToken(ctx, auth.ID)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		return nil, auth, mfaToken, nil
+	}
+
+	authToken, err := u.startSession(ctx, span, auth, request, method)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return authToken, auth, "", nil
+}
+
+func (u *authUsecase) startSession(ctx context.Context, span trace.Span, auth *entities.Auth, request *entities.Request, method string) (*entities.AuthToken, error) {
+	sessionToken := utils.NewUUID().String()
+	accessToken, err := u.jwt.Create(auth)
+	if err != nil {
+		wErr := fmt.Errorf("failed to start session: %w", err)
+		return nil, ce.NewError(span, ce.CodeJWTGenerationFailed, ce.MsgInternalServer, wErr)
+	}
+
+	newSessionData := entities.CreateSession{
+		Token:     sessionToken,
+		UserAgent: request.UserAgent,
+		IPAddress: request.IPAddress,
+		ExpiresAt: time.Now().UTC().Add(u.cfg.Auth.TokenDuration.Session),
+	}
+	if err := u.su.CreateSession(ctx, auth.ID, &newSessionData); err != nil {
+		return nil, err
+	}
+	if err := u.su.TrackAccessToken(ctx, auth.ID, sessionToken, "", accessToken); err != nil {
+		log.Println("WARNING ->", err.Error())
+	}
+	u.seu.Record(ctx, &auth.ID, constants.EventTypeLoginSucceeded, map[string]any{"method": method})
+	u.sau.Check(ctx, auth.ID, sessionToken)
+
+	return &entities.AuthToken{
+		AccessToken:  accessToken.Token,
+		SessionToken: sessionToken,
+	}, nil
+}
+
+func (u *authUsecase) handleSessionReuse(ctx context.Context, span trace.Span, session *entities.Session) error {
+	// reuse of a rotated token means it has leaked, so the whole family is revoked
+	if err := u.su.RevokeFamily(ctx, session.AuthID, session.ID); err != nil {
+		return err
+	}
+
+	// the request is the one presenting the leaked token, the session is where it was issued
+	details := map[string]any{
+		"session_id":         session.ID,
+		"session_user_agent": session.UserAgent,
+		"session_ip_address": session.IPAddress,
+	}
+	u.seu.Record(ctx, &session.AuthID, constants.EventTypeSessionReuseDetected, details)
+
+	// non-fatal: the family is already revoked at this point
+	auth, err := u.ar.GetByID(ctx, session.AuthID)
+	if err == nil {
+		err = u.aep.PublishSessionReuseDetected(ctx, auth.ID, auth.Email, session)
+	}
+	if err != nil {
+		log.Println("WARNING ->", err.Error())
+	}
+
+	err = fmt.Errorf("failed to refresh session: %w", ce.ErrSessionReused)
+	return ce.NewError(span, ce.CodeSessionReused, ce.MsgUnauthenticated, err)
+}
@@ -0,0 +1,67 @@
+package usecases
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+)
+
+// a driver that only knows transactions, the repositories under test are fakes
+type txDriver struct{}
+
+type txConn struct{}
+
+type txTx struct{}
+
+func (txDriver) Open(name string) (driver.Conn, error) { return txConn{}, nil }
+
+func (txConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("statements are not supported")
+}
+func (txConn) Close() error              { return nil }
+func (txConn) Begin() (driver.Tx, error) { return txTx{}, nil }
+
+func (txTx) Commit() error   { return nil }
+func (txTx) Rollback() error { return nil }
+
+var registerTxDriver sync.Once
+
+func newTestTransactor(t *testing.T) *database.Transactor {
+	t.Helper()
+
+	registerTxDriver.Do(func() { sql.Register("usecases-tx", txDriver{}) })
+
+	db, err := sql.Open("usecases-tx", "")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return database.NewTransactor(db)
+}
+
+// records the calls made on the fakes, in order
+type callLog struct {
+	calls []string
+}
+
+func (l *callLog) add(call string) {
+	l.calls = append(l.calls, call)
+}
+
+func (l *callLog) index(call string) int {
+	for i, c := range l.calls {
+		if c == call {
+			return i
+		}
+	}
+	return -1
+}
+
+func (l *callLog) has(call string) bool {
+	return l.index(call) >= 0
+}
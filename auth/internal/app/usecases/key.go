@@ -0,0 +1,117 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const keyErrorTracer string = "usecase.key"
+
+type KeyUsecase interface {
+	LoadKeys(ctx context.Context) (err error)
+}
+
+type keyUsecase struct {
+	skr    repositories.SigningKeyRepository
+	jwt    *services.JWTService
+	cipher *services.CipherService
+	cfg    *configs.Config
+}
+
+func NewKeyUsecase(
+	skr repositories.SigningKeyRepository,
+	jwt *services.JWTService,
+	cipher *services.CipherService,
+	cfg *configs.Config,
+) KeyUsecase {
+	return &keyUsecase{skr, jwt, cipher, cfg}
+}
+
+func (u *keyUsecase) LoadKeys(ctx context.Context) error {
+	ctx, span := otel.Tracer(keyErrorTracer).Start(ctx, "LoadKeys")
+	defer span.End()
+
+	keys, err := u.skr.GetAllActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	// rotate once the newest key has been around for a full rotation period,
+	// older keys stay published until they expire so issued tokens remain verifiable
+	now := time.Now().UTC()
+	if len(keys) == 0 || now.Sub(keys[0].CreatedAt) >= u.cfg.Auth.JWT.Rotation {
+		if err := u.rotate(ctx, span, now); err != nil {
+			return err
+		}
+
+		keys, err = u.skr.GetAllActive(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range keys {
+		privateKey, err := u.cipher.Decrypt(keys[i].PrivateKey)
+		if err != nil {
+			wErr := fmt.Errorf("failed to load keys: %w", err)
+			return ce.NewError(span, ce.CodeDecryptionFailed, ce.MsgInternalServer, wErr)
+		}
+		keys[i].PrivateKey = privateKey
+	}
+
+	// a new key only starts signing once every instance had the chance to load it,
+	// unless there is no older key to fall back on
+	current := keys[0]
+	for _, key := range keys {
+		if now.Sub(key.CreatedAt) >= u.cfg.Auth.JWT.Refresh {
+			current = key
+			break
+		}
+	}
+
+	if err := u.jwt.SetKeys(keys, current.KID); err != nil {
+		wErr := fmt.Errorf("failed to load keys: %w", err)
+		return ce.NewError(span, ce.CodeSigningKeyInvalid, ce.MsgInternalServer, wErr)
+	}
+
+	// non-fatal: expired keys are already excluded from the key set
+	if err := u.skr.DeleteExpired(ctx); err != nil {
+		log.Println("WARNING ->", err.Error())
+	}
+
+	return nil
+}
+
+func (u *keyUsecase) rotate(ctx context.Context, span trace.Span, now time.Time) error {
+	privateKey, err := u.jwt.GenerateKey(u.cfg.Auth.JWT.Algorithm)
+	if err != nil {
+		wErr := fmt.Errorf("failed to rotate key: %w", err)
+		return ce.NewError(span, ce.CodeSigningKeyGeneration, ce.MsgInternalServer, wErr)
+	}
+
+	encryptedKey, err := u.cipher.Encrypt(privateKey)
+	if err != nil {
+		wErr := fmt.Errorf("failed to rotate key: %w", err)
+		return ce.NewError(span, ce.CodeEncryptionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	data := entities.CreateSigningKey{
+		KID:        utils.NewUUID().String(),
+		Algorithm:  u.cfg.Auth.JWT.Algorithm,
+		PrivateKey: encryptedKey,
+		ExpiresAt:  now.Add(u.cfg.Auth.JWT.Rotation + u.cfg.Auth.JWT.Overlap),
+	}
+
+	return u.skr.Create(ctx, &data)
+}
@@ -0,0 +1,352 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/caches"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const mfaErrorTracer string = "usecase.mfa"
+
+type MFAUsecase interface {
+	Enroll(ctx context.Context, authID int64) (enrollment *entities.MFAEnrollment, err error)
+	ConfirmEnrollment(ctx context.Context, authID int64, code string) (recoveryCodes []string, err error)
+	Disable(ctx context.Context, authID int64, code string) (err error)
+	RegenerateRecoveryCodes(ctx context.Context, authID int64, code string) (recoveryCodes []string, err error)
+	CreatePendingToken(ctx context.Context, authID int64) (mfaToken string, err error)
+	VerifyLogin(ctx context.Context, data *entities.VerifyMFA, request *entities.Request) (authToken *entities.AuthToken, auth *entities.Auth, err error)
+	IsEnabled(ctx context.Context, authID int64) (isEnabled bool, err error)
+	VerifyCode(ctx context.Context, authID int64, code string) (err error)
+}
+
+type mfaUsecase struct {
+	mr         repositories.MFARepository
+	ar         repositories.AuthRepository
+	mc         caches.MFACache
+	su         SessionUsecase
+	seu        SecurityEventUsecase
+	sau        SignInAlertUsecase
+	transactor *database.Transactor
+	totp       *services.TOTPService
+	cipher     *services.CipherService
+	jwt        *services.JWTService
+	cfg        *configs.Config
+}
+
+func NewMFAUsecase(
+	mr repositories.MFARepository,
+	ar repositories.AuthRepository,
+	mc caches.MFACache,
+	su SessionUsecase,
+	seu SecurityEventUsecase,
+	sau SignInAlertUsecase,
+	transactor *database.Transactor,
+	totp *services.TOTPService,
+	cipher *services.CipherService,
+	jwt *services.JWTService,
+	cfg *configs.Config,
+) MFAUsecase {
+	return &mfaUsecase{mr, ar, mc, su, seu, sau, transactor, totp, cipher, jwt, cfg}
+}
+
+func (u *mfaUsecase) Enroll(ctx context.Context, authID int64) (*entities.MFAEnrollment, error) {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "Enroll")
+	defer span.End()
+
+	auth, err := u.ar.GetByID(ctx, authID)
+	if err != nil {
+		return nil, err
+	}
+	if auth.Password == nil {
+		// this is an oauth type account
+		// second factor is handled by the oauth provider
+		err := fmt.Errorf("failed to enroll mfa: %w", errors.New("mfa enrollment with oauth account"))
+		return nil, ce.NewError(span, ce.CodeOAuthMFAEnrollment, "OAuth account cannot enroll two-factor authentication", err)
+	}
+
+	secret, uri, err := u.totp.Generate(auth.Email)
+	if err != nil {
+		wErr := fmt.Errorf("failed to enroll mfa: %w", err)
+		return nil, ce.NewError(span, ce.CodeMFAGenerationFailed, ce.MsgInternalServer, wErr)
+	}
+
+	encryptedSecret, err := u.cipher.Encrypt(secret)
+	if err != nil {
+		wErr := fmt.Errorf("failed to enroll mfa: %w", err)
+		return nil, ce.NewError(span, ce.CodeEncryptionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	if err := u.mr.Upsert(ctx, auth.ID, encryptedSecret); err != nil {
+		return nil, err
+	}
+
+	enrollment := entities.MFAEnrollment{
+		Secret: secret,
+		URI:    uri,
+	}
+
+	return &enrollment, nil
+}
+
+func (u *mfaUsecase) ConfirmEnrollment(ctx context.Context, authID int64, code string) ([]string, error) {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "ConfirmEnrollment")
+	defer span.End()
+
+	var recoveryCodes []string
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		mfa, err := u.mr.GetByAuthID(ctx, authID)
+		if err != nil {
+			return err
+		}
+		if mfa.IsEnabled {
+			err := fmt.Errorf("failed to confirm mfa enrollment: %w", errors.New("mfa already enabled"))
+			return ce.NewError(span, ce.CodeMFAEnabled, "Two-factor authentication is already enabled", err)
+		}
+		if err := u.validateTOTP(ctx, span, mfa, code); err != nil {
+			return err
+		}
+
+		if err := u.mr.Enable(ctx, authID); err != nil {
+			return err
+		}
+
+		recoveryCodes, err = u.createRecoveryCodes(ctx, authID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+func (u *mfaUsecase) Disable(ctx context.Context, authID int64, code string) error {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "Disable")
+	defer span.End()
+
+	return u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		mfa, err := u.getEnabled(ctx, span, authID)
+		if err != nil {
+			return err
+		}
+		if err := u.validateCode(ctx, span, mfa, code); err != nil {
+			return err
+		}
+
+		// the recovery codes go too, they are useless without the secret
+		return u.mr.DeleteAllByAuthID(ctx, authID)
+	})
+}
+
+func (u *mfaUsecase) RegenerateRecoveryCodes(ctx context.Context, authID int64, code string) ([]string, error) {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "RegenerateRecoveryCodes")
+	defer span.End()
+
+	var recoveryCodes []string
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		mfa, err := u.getEnabled(ctx, span, authID)
+		if err != nil {
+			return err
+		}
+		if err := u.validateTOTP(ctx, span, mfa, code); err != nil {
+			return err
+		}
+
+		recoveryCodes, err = u.createRecoveryCodes(ctx, authID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+func (u *mfaUsecase) CreatePendingToken(ctx context.Context, authID int64) (string, error) {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "CreatePendingToken")
+	defer span.End()
+
+	token := utils.NewUUID().String()
+	if err := u.mc.CreatePendingToken(ctx, authID, token, u.cfg.Auth.TokenDuration.MFAPending); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (u *mfaUsecase) VerifyLogin(ctx context.Context, data *entities.VerifyMFA, request *entities.Request) (*entities.AuthToken, *entities.Auth, error) {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "VerifyLogin")
+	defer span.End()
+
+	now := time.Now().UTC()
+
+	authID, err := u.mc.GetPendingToken(ctx, data.Token, u.cfg.Auth.MFA.MaxAttempts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	isWrongCode := false
+	var auth *entities.Auth
+	var authToken entities.AuthToken
+	err = u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		mfa, err := u.getEnabled(ctx, span, authID)
+		if err != nil {
+			return err
+		}
+		if err := u.validateCode(ctx, span, mfa, data.Code); err != nil {
+			isWrongCode = true
+			return err
+		}
+
+		auth, err = u.ar.GetByID(ctx, authID)
+		if err != nil {
+			return err
+		}
+		if err := checkAccountState(span, auth); err != nil {
+			return err
+		}
+
+		sessionToken := utils.NewUUID().String()
+		accessToken, err := u.jwt.Create(auth)
+		if err != nil {
+			wErr := fmt.Errorf("failed to verify mfa login: %w", err)
+			return ce.NewError(span, ce.CodeJWTGenerationFailed, ce.MsgInternalServer, wErr)
+		}
+
+		newSessionData := entities.CreateSession{
+			Token:     sessionToken,
+			UserAgent: request.UserAgent,
+			IPAddress: request.IPAddress,
+			ExpiresAt: now.Add(u.cfg.Auth.TokenDuration.Session),
+		}
+		if err := u.su.CreateSession(ctx, auth.ID, &newSessionData); err != nil {
+			return err
+		}
+		if err := u.su.TrackAccessToken(ctx, auth.ID, sessionToken, "", accessToken); err != nil {
+			log.Println("WARNING ->", err.Error())
+		}
+
+		authToken = entities.AuthToken{
+			AccessToken:  accessToken.Token,
+			SessionToken: sessionToken,
+		}
+
+		return nil
+	})
+	if isWrongCode {
+		details := map[string]any{"method": constants.LoginMethodMFA, "reason": constants.LoginFailureWrongCode}
+		u.seu.Record(ctx, &authID, constants.EventTypeLoginFailed, details)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := u.mc.DeletePendingToken(ctx, data.Token); err != nil {
+		return nil, nil, err
+	}
+
+	u.seu.Record(ctx, &auth.ID, constants.EventTypeLoginSucceeded, map[string]any{"method": constants.LoginMethodMFA})
+	u.sau.Check(ctx, auth.ID, authToken.SessionToken)
+	return &authToken, auth, nil
+}
+
+func (u *mfaUsecase) IsEnabled(ctx context.Context, authID int64) (bool, error) {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "IsEnabled")
+	defer span.End()
+
+	return u.mr.IsEnabled(ctx, authID)
+}
+
+func (u *mfaUsecase) VerifyCode(ctx context.Context, authID int64, code string) error {
+	ctx, span := otel.Tracer(mfaErrorTracer).Start(ctx, "VerifyCode")
+	defer span.End()
+
+	if code == "" {
+		err := fmt.Errorf("failed to verify mfa code: %w", errors.New("mfa code not provided"))
+		return ce.NewError(span, ce.CodeMFAInvalidCode, "Authentication code is required", err)
+	}
+
+	return u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		mfa, err := u.getEnabled(ctx, span, authID)
+		if err != nil {
+			return err
+		}
+		return u.validateCode(ctx, span, mfa, code)
+	})
+}
+
+func (u *mfaUsecase) getEnabled(ctx context.Context, span trace.Span, authID int64) (*entities.MFA, error) {
+	mfa, err := u.mr.GetByAuthID(ctx, authID)
+	if err != nil {
+		return nil, err
+	}
+	if !mfa.IsEnabled {
+		err := fmt.Errorf("failed to fetch mfa: %w", errors.New("mfa not enabled"))
+		return nil, ce.NewError(span, ce.CodeMFANotEnabled, "Two-factor authentication is not enabled", err)
+	}
+	return mfa, nil
+}
+
+func (u *mfaUsecase) validateCode(ctx context.Context, span trace.Span, mfa *entities.MFA, code string) error {
+	if utils.IsTOTPCode(code) {
+		return u.validateTOTP(ctx, span, mfa, code)
+	}
+
+	// anything that is not a totp code is treated as a recovery code
+	codeHash := utils.HashSHA256(utils.NormalizeRecoveryCode(code))
+	return u.mr.UseRecoveryCode(ctx, mfa.AuthID, codeHash)
+}
+
+func (u *mfaUsecase) validateTOTP(ctx context.Context, span trace.Span, mfa *entities.MFA, code string) error {
+	secret, err := u.cipher.Decrypt(mfa.Secret)
+	if err != nil {
+		wErr := fmt.Errorf("failed to validate mfa code: %w", err)
+		return ce.NewError(span, ce.CodeDecryptionFailed, ce.MsgInternalServer, wErr)
+	}
+	if !u.totp.Validate(code, secret) {
+		err := fmt.Errorf("failed to validate mfa code: %w", errors.New("invalid totp code"))
+		return ce.NewError(span, ce.CodeMFAInvalidCode, ce.MsgInvalidMFACode, err)
+	}
+
+	// a code cannot be replayed within its validity window
+	isFirstUse, err := u.mc.MarkCodeUsed(ctx, mfa.AuthID, code, u.totp.ValidityWindow())
+	if err != nil {
+		return err
+	}
+	if !isFirstUse {
+		err := fmt.Errorf("failed to validate mfa code: %w", errors.New("totp code already used"))
+		return ce.NewError(span, ce.CodeMFAInvalidCode, ce.MsgInvalidMFACode, err)
+	}
+
+	return nil
+}
+
+func (u *mfaUsecase) createRecoveryCodes(ctx context.Context, authID int64) ([]string, error) {
+	recoveryCodes := make([]string, 0, u.cfg.Auth.MFA.RecoveryCodes)
+	codeHashes := make([]string, 0, u.cfg.Auth.MFA.RecoveryCodes)
+	for range u.cfg.Auth.MFA.RecoveryCodes {
+		code := utils.NewRecoveryCode()
+		recoveryCodes = append(recoveryCodes, code)
+		codeHashes = append(codeHashes, utils.HashSHA256(utils.NormalizeRecoveryCode(code)))
+	}
+
+	if err := u.mr.CreateRecoveryCodes(ctx, authID, codeHashes); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/ritchieridanko/apotekly-api/auth/configs"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/app/caches"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/publishers"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/services"
@@ -17,16 +20,19 @@ import (
 	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
 	"go.opentelemetry.io/otel"
+	"golang.org/x/oauth2"
 )
 
-// TODO
-// 1: Send verification token to notification service
-
 const oAuthErrorTracer string = "usecase.oauth"
 
 type OAuthUsecase interface {
-	Authenticate(ctx context.Context, data *entities.OAuth, request *entities.Request) (sessionToken string, exchangeCode string, err error)
-	ExchangeCode(ctx context.Context, code string) (auth *entities.Auth, accessToken string, err error)
+	Authenticate(ctx context.Context, data *entities.OAuth, request *entities.Request) (sessionToken, exchangeCode, mfaToken string, err error)
+	ExchangeCode(ctx context.Context, code, sessionToken string) (auth *entities.Auth, accessToken string, err error)
+	CreateState(ctx context.Context, provider int16, authID int64, redirectTo string) (state *entities.OAuthState, err error)
+	UseState(ctx context.Context, provider int16, state string) (oauthState *entities.OAuthState, err error)
+	GetIdentities(ctx context.Context, authID int64) (identities []entities.OAuthIdentity, err error)
+	LinkIdentity(ctx context.Context, authID int64, data *entities.OAuth) (err error)
+	UnlinkIdentity(ctx context.Context, authID int64, provider int16) (err error)
 }
 
 type oAuthUsecase struct {
@@ -35,6 +41,10 @@ type oAuthUsecase struct {
 	oac        caches.OAuthCache
 	ac         caches.AuthCache
 	su         SessionUsecase
+	seu        SecurityEventUsecase
+	sau        SignInAlertUsecase
+	mu         MFAUsecase
+	aep        publishers.AuthEventPublisher
 	transactor *database.Transactor
 	jwt        *services.JWTService
 	cfg        *configs.Config
@@ -46,44 +56,90 @@ func NewOAuthUsecase(
 	oac caches.OAuthCache,
 	ac caches.AuthCache,
 	su SessionUsecase,
+	seu SecurityEventUsecase,
+	sau SignInAlertUsecase,
+	mu MFAUsecase,
+	aep publishers.AuthEventPublisher,
 	transactor *database.Transactor,
 	jwt *services.JWTService,
 	cfg *configs.Config,
 ) OAuthUsecase {
-	return &oAuthUsecase{oar, ar, oac, ac, su, transactor, jwt, cfg}
+	return &oAuthUsecase{oar, ar, oac, ac, su, seu, sau, mu, aep, transactor, jwt, cfg}
 }
 
-func (u *oAuthUsecase) Authenticate(ctx context.Context, data *entities.OAuth, request *entities.Request) (string, string, error) {
+func (u *oAuthUsecase) Authenticate(ctx context.Context, data *entities.OAuth, request *entities.Request) (string, string, string, error) {
 	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "Authenticate")
 	defer span.End()
 
 	if !data.IsVerified {
 		err := fmt.Errorf("failed to authenticate: %w", errors.New("user email not verified"))
-		return "", "", ce.NewError(span, ce.CodeOAuthNotVerified, "Cannot authenticate with unverified email", err)
+		return "", "", "", ce.NewError(span, ce.CodeOAuthNotVerified, "Cannot authenticate with unverified email", err)
 	}
 
 	now := time.Now().UTC()
 	newAccount := false
+	isNewLink := false
+	isMFAEnabled := false
 
 	var rAuth *entities.Auth
 	var sessionToken string
 	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
 		normalizedEmail := utils.Normalize(data.Email)
-		exists, auth, err := u.ar.GetForOAuth(ctx, normalizedEmail)
+
+		// a linked identity wins over the email, which may differ on either side
+		isLinked, linkedAuthID, err := u.oar.GetAuthIDByProviderUID(ctx, data.Provider, data.UID)
 		if err != nil {
 			return err
 		}
-		if exists && auth.Password != nil {
-			// this is a regular type account
-			err := fmt.Errorf("failed to authenticate: %w", errors.New("email registered as regular auth"))
-			return ce.NewError(span, ce.CodeOAuthRegularExists, ce.MsgInvalidCredentials, err)
+
+		var exists bool
+		var auth *entities.Auth
+		if isLinked {
+			exists = true
+			auth, err = u.ar.GetByID(ctx, linkedAuthID)
+			if err != nil {
+				return err
+			}
+		} else {
+			exists, auth, err = u.ar.GetForOAuth(ctx, normalizedEmail)
+			if err != nil {
+				return err
+			}
+		}
+		if exists && !isLinked {
+			if auth.Password != nil {
+				// this is a regular type account, the identity has to be linked from it first
+				err := fmt.Errorf("failed to authenticate: %w", errors.New("email registered as regular auth"))
+				return ce.NewError(span, ce.CodeOAuthRegularExists, ce.MsgInvalidCredentials, err)
+			}
+			// an oauth account signing in through another provider with the same email
+			isNewLink, err = u.linkIfAbsent(ctx, auth, data)
+			if err != nil {
+				return err
+			}
+		}
+		if exists {
+			if err := checkAccountState(span, auth); err != nil {
+				return err
+			}
+
+			// the provider stands in for the password only, the second factor is still required
+			isMFAEnabled, err = u.mu.IsEnabled(ctx, auth.ID)
+			if err != nil {
+				return err
+			}
+			if isMFAEnabled {
+				rAuth = auth
+				return nil
+			}
 		}
 		if !exists {
 			// register if not exists
 			newAccount = true
 			newAuthData := entities.CreateAuth{
-				Email:  normalizedEmail,
-				RoleID: constants.RoleCustomer,
+				Email:      normalizedEmail,
+				RoleID:     constants.RoleCustomer,
+				IsApproved: true,
 			}
 
 			auth, err = u.ar.Create(ctx, &newAuthData)
@@ -113,12 +169,28 @@ func (u *oAuthUsecase) Authenticate(ctx context.Context, data *entities.OAuth, r
 		return err
 	})
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
 
+	provider := u.providerName(data.Provider)
+	if isNewLink {
+		u.seu.Record(ctx, &rAuth.ID, constants.EventTypeOAuthLinked, map[string]any{"provider": provider})
+	}
+	if isMFAEnabled {
+		// session is created only after the second factor is verified
+		mfaToken, err := u.mu.CreatePendingToken(ctx, rAuth.ID)
+		if err != nil {
+			return "", "", "", err
+		}
+		return "", "", mfaToken, nil
+	}
+	details := map[string]any{"method": constants.LoginMethodOAuth, "provider": provider}
+	u.seu.Record(ctx, &rAuth.ID, constants.EventTypeLoginSucceeded, details)
+	u.sau.Check(ctx, rAuth.ID, sessionToken)
+
 	exchangeCode := utils.NewUUID().String()
 	if err := u.oac.StoreAuth(ctx, exchangeCode, rAuth, u.cfg.OAuth.Duration.CodeExchange); err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
 
 	if newAccount {
@@ -129,16 +201,26 @@ func (u *oAuthUsecase) Authenticate(ctx context.Context, data *entities.OAuth, r
 		)
 		if err != nil {
 			log.Println("WARNING ->", err.Error())
-			return sessionToken, exchangeCode, nil
+			return sessionToken, exchangeCode, "", nil
+		}
+		verificationCode, err := issueOneTimeCode(
+			ctx, u.ac, constants.OTPPurposeVerification, strconv.FormatInt(rAuth.ID, 10),
+			rAuth.ID, "", u.cfg.Auth.TokenDuration.OTP,
+		)
+		if err != nil {
+			// the link alone still verifies the account
+			log.Println("WARNING ->", err.Error())
+		}
+		if err := u.aep.PublishVerificationRequested(ctx, rAuth.ID, rAuth.Email, verificationToken, verificationCode); err != nil {
+			// the account is already signed in, verification can be resent later
+			log.Println("WARNING ->", err.Error())
 		}
-
-		// TODO (1)
 	}
 
-	return sessionToken, exchangeCode, nil
+	return sessionToken, exchangeCode, "", nil
 }
 
-func (u *oAuthUsecase) ExchangeCode(ctx context.Context, code string) (*entities.Auth, string, error) {
+func (u *oAuthUsecase) ExchangeCode(ctx context.Context, code, sessionToken string) (*entities.Auth, string, error) {
 	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "ExchangeCode")
 	defer span.End()
 
@@ -147,11 +229,184 @@ func (u *oAuthUsecase) ExchangeCode(ctx context.Context, code string) (*entities
 		return nil, "", err
 	}
 
-	accessToken, err := u.jwt.Create(auth.ID, auth.RoleID, auth.IsVerified)
+	accessToken, err := u.jwt.Create(auth)
 	if err != nil {
 		wErr := fmt.Errorf("failed to exchange code: %w", err)
 		return nil, "", ce.NewError(span, ce.CodeJWTGenerationFailed, ce.MsgInternalServer, wErr)
 	}
+	if err := u.su.TrackAccessToken(ctx, auth.ID, sessionToken, "", accessToken); err != nil {
+		log.Println("WARNING ->", err.Error())
+	}
+
+	return auth, accessToken.Token, nil
+}
+
+func (u *oAuthUsecase) CreateState(ctx context.Context, provider int16, authID int64, redirectTo string) (*entities.OAuthState, error) {
+	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "CreateState")
+	defer span.End()
+
+	// only exact matches are accepted, anything looser turns the flow into an open redirect
+	if redirectTo != "" && !slices.Contains(u.cfg.OAuth.RedirectAllowlist, redirectTo) {
+		err := fmt.Errorf("failed to create oauth state: %w", fmt.Errorf("redirect '%s' not allowed", redirectTo))
+		return nil, ce.NewError(span, ce.CodeOAuthRedirectNotAllowed, "Redirect URL is not allowed", err)
+	}
+
+	state := entities.OAuthState{
+		State:      utils.NewRandomToken(),
+		Provider:   provider,
+		Verifier:   oauth2.GenerateVerifier(),
+		Nonce:      utils.NewRandomToken(),
+		RedirectTo: redirectTo,
+		AuthID:     authID,
+	}
+
+	if err := u.oac.StoreState(ctx, &state, u.cfg.OAuth.Duration.State); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+func (u *oAuthUsecase) UseState(ctx context.Context, provider int16, state string) (*entities.OAuthState, error) {
+	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "UseState")
+	defer span.End()
+
+	oauthState, err := u.oac.UseState(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	if oauthState.Provider != provider {
+		err := fmt.Errorf("failed to use oauth state: %w", ce.ErrOAuthStateMismatch)
+		return nil, ce.NewError(span, ce.CodeOAuthStateInvalid, ce.MsgInvalidOAuthState, err)
+	}
+
+	return oauthState, nil
+}
+
+func (u *oAuthUsecase) GetIdentities(ctx context.Context, authID int64) ([]entities.OAuthIdentity, error) {
+	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "GetIdentities")
+	defer span.End()
+
+	return u.oar.GetAllByAuthID(ctx, authID)
+}
+
+func (u *oAuthUsecase) LinkIdentity(ctx context.Context, authID int64, data *entities.OAuth) error {
+	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "LinkIdentity")
+	defer span.End()
 
-	return auth, accessToken, nil
+	isNewLink := false
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		// locks the account against concurrent link/unlink
+		auth, err := u.ar.GetByID(ctx, authID)
+		if err != nil {
+			return err
+		}
+
+		isLinked, linkedAuthID, err := u.oar.GetAuthIDByProviderUID(ctx, data.Provider, data.UID)
+		if err != nil {
+			return err
+		}
+		if isLinked {
+			if linkedAuthID == authID {
+				// linking the same identity twice is a no-op
+				return nil
+			}
+			err := fmt.Errorf("failed to link identity: %w", ce.ErrOAuthLinkConflict)
+			return ce.NewError(span, ce.CodeOAuthIdentityConflict, "Identity is already linked to another account", err)
+		}
+
+		identities, err := u.oar.GetAllByAuthID(ctx, authID)
+		if err != nil {
+			return err
+		}
+		for _, identity := range identities {
+			if identity.Provider == data.Provider {
+				err := fmt.Errorf("failed to link identity: %w", ce.ErrOAuthLinkConflict)
+				return ce.NewError(span, ce.CodeOAuthIdentityConflict, "Another identity of this provider is already linked", err)
+			}
+		}
+
+		if err := u.oar.Create(ctx, authID, data); err != nil {
+			return err
+		}
+
+		isNewLink = true
+		return u.aep.PublishOAuthLinked(ctx, auth.ID, auth.Email, u.providerName(data.Provider))
+	})
+	if err != nil {
+		return err
+	}
+
+	if isNewLink {
+		u.seu.Record(ctx, &authID, constants.EventTypeOAuthLinked, map[string]any{"provider": u.providerName(data.Provider)})
+	}
+	return nil
+}
+
+func (u *oAuthUsecase) UnlinkIdentity(ctx context.Context, authID int64, provider int16) error {
+	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "UnlinkIdentity")
+	defer span.End()
+
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		auth, err := u.ar.GetByID(ctx, authID)
+		if err != nil {
+			return err
+		}
+
+		identities, err := u.oar.GetAllByAuthID(ctx, authID)
+		if err != nil {
+			return err
+		}
+
+		isFound := false
+		for _, identity := range identities {
+			if identity.Provider == provider {
+				isFound = true
+				break
+			}
+		}
+		if !isFound {
+			err := fmt.Errorf("failed to unlink identity: %w", errors.New("identity not linked"))
+			return ce.NewError(span, ce.CodeOAuthIdentityNotFound, "Identity not found", err)
+		}
+
+		// passkeys are only a shortcut into an account, a password or another identity has to remain
+		if auth.Password == nil && len(identities) == 1 {
+			err := fmt.Errorf("failed to unlink identity: %w", errors.New("last login method"))
+			return ce.NewError(span, ce.CodeOAuthLastLoginMethod, "Set a password or link another identity before unlinking this one", err)
+		}
+
+		return u.oar.Delete(ctx, authID, provider)
+	})
+	if err != nil {
+		return err
+	}
+
+	u.seu.Record(ctx, &authID, constants.EventTypeOAuthUnlinked, map[string]any{"provider": u.providerName(provider)})
+	return nil
+}
+
+func (u *oAuthUsecase) linkIfAbsent(ctx context.Context, auth *entities.Auth, data *entities.OAuth) (bool, error) {
+	identities, err := u.oar.GetAllByAuthID(ctx, auth.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, identity := range identities {
+		if identity.Provider == data.Provider {
+			return false, nil
+		}
+	}
+	if err := u.oar.Create(ctx, auth.ID, data); err != nil {
+		return false, err
+	}
+	return true, u.aep.PublishOAuthLinked(ctx, auth.ID, auth.Email, u.providerName(data.Provider))
+}
+
+func (u *oAuthUsecase) providerName(id int16) string {
+	for name, provider := range u.cfg.OAuth.Providers {
+		if provider.ID == id {
+			return name
+		}
+	}
+	return strconv.Itoa(int(id))
 }
@@ -0,0 +1,116 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/broker"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"go.opentelemetry.io/otel"
+)
+
+const outboxErrorTracer string = "usecase.outbox"
+
+type OutboxUsecase interface {
+	Relay(ctx context.Context) (err error)
+	Cleanup(ctx context.Context) (err error)
+}
+
+type outboxUsecase struct {
+	or         repositories.OutboxRepository
+	producer   *broker.Producer
+	transactor *database.Transactor
+	cfg        *configs.Config
+}
+
+func NewOutboxUsecase(
+	or repositories.OutboxRepository,
+	producer *broker.Producer,
+	transactor *database.Transactor,
+	cfg *configs.Config,
+) OutboxUsecase {
+	return &outboxUsecase{or, producer, transactor, cfg}
+}
+
+func (u *outboxUsecase) Relay(ctx context.Context) error {
+	ctx, span := otel.Tracer(outboxErrorTracer).Start(ctx, "Relay")
+	defer span.End()
+
+	// the lock is only held while claiming, publishing happens outside the transaction
+	var messages []entities.OutboxMessage
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		isLocked, err := u.or.TryLockRelay(ctx)
+		if err != nil {
+			return err
+		}
+		if !isLocked {
+			// another instance is claiming
+			return nil
+		}
+
+		claimedUntil := time.Now().UTC().Add(u.cfg.Broker.Outbox.ClaimDuration)
+		messages, err = u.or.ClaimPending(ctx, u.cfg.Broker.Outbox.BatchSize, claimedUntil)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// once a message fails, later messages with the same key wait for it
+	blocked := make(map[string]bool)
+	for _, message := range messages {
+		if blocked[message.Key] {
+			continue
+		}
+
+		err := u.producer.PublishEncoded(
+			ctx, message.Topic, message.Key, message.Payload,
+			message.TraceID, message.CorrelationID,
+		)
+		if err != nil {
+			blocked[message.Key] = true
+			log.Println("WARNING ->", fmt.Errorf("failed to relay event %s (%s): %w", message.EventType, message.EventID, err).Error())
+
+			if message.Attempts+1 >= u.cfg.Broker.Outbox.MaxAttempts {
+				// given up on, so the messages queued behind it are not held up forever
+				log.Printf("WARNING -> parking event %s (%s) after %d attempts", message.EventType, message.EventID, message.Attempts+1)
+				if err := u.or.Park(ctx, message.ID, err.Error()); err != nil {
+					return err
+				}
+				continue
+			}
+
+			retryAt := time.Now().UTC().Add(u.retryDelay(message.Attempts))
+			if err := u.or.MarkFailed(ctx, message.ID, err.Error(), retryAt); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := u.or.MarkSent(ctx, message.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *outboxUsecase) Cleanup(ctx context.Context) error {
+	ctx, span := otel.Tracer(outboxErrorTracer).Start(ctx, "Cleanup")
+	defer span.End()
+
+	return u.or.DeleteSent(ctx, time.Now().UTC().Add(-u.cfg.Broker.Outbox.Retention))
+}
+
+func (u *outboxUsecase) retryDelay(attempts int) time.Duration {
+	delay := u.cfg.Broker.Outbox.BaseDelay
+	for i := 0; i < attempts && delay < u.cfg.Broker.Outbox.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, u.cfg.Broker.Outbox.MaxDelay)
+}
@@ -0,0 +1,317 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/caches"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const passkeyErrorTracer string = "usecase.passkey"
+
+type PasskeyUsecase interface {
+	BeginRegistration(ctx context.Context, authID int64) (options *protocol.CredentialCreation, err error)
+	FinishRegistration(ctx context.Context, authID int64, data *entities.RegisterPasskey) (passkey *entities.Passkey, err error)
+	BeginLogin(ctx context.Context) (challengeID string, options *protocol.CredentialAssertion, err error)
+	FinishLogin(ctx context.Context, data *entities.LoginPasskey, request *entities.Request) (authToken *entities.AuthToken, auth *entities.Auth, err error)
+	GetAll(ctx context.Context, authID int64) (passkeys []entities.Passkey, err error)
+	Rename(ctx context.Context, authID, passkeyID int64, name string) (passkey *entities.Passkey, err error)
+	Delete(ctx context.Context, authID, passkeyID int64) (err error)
+}
+
+type passkeyUsecase struct {
+	pr         repositories.PasskeyRepository
+	ar         repositories.AuthRepository
+	pc         caches.PasskeyCache
+	su         SessionUsecase
+	seu        SecurityEventUsecase
+	sau        SignInAlertUsecase
+	transactor *database.Transactor
+	webauthn   *services.WebAuthnService
+	jwt        *services.JWTService
+	cfg        *configs.Config
+}
+
+func NewPasskeyUsecase(
+	pr repositories.PasskeyRepository,
+	ar repositories.AuthRepository,
+	pc caches.PasskeyCache,
+	su SessionUsecase,
+	seu SecurityEventUsecase,
+	sau SignInAlertUsecase,
+	transactor *database.Transactor,
+	webauthn *services.WebAuthnService,
+	jwt *services.JWTService,
+	cfg *configs.Config,
+) PasskeyUsecase {
+	return &passkeyUsecase{pr, ar, pc, su, seu, sau, transactor, webauthn, jwt, cfg}
+}
+
+func (u *passkeyUsecase) BeginRegistration(ctx context.Context, authID int64) (*protocol.CredentialCreation, error) {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "BeginRegistration")
+	defer span.End()
+
+	user, err := u.getUser(ctx, span, authID)
+	if err != nil {
+		return nil, err
+	}
+
+	options, session, err := u.webauthn.BeginRegistration(user)
+	if err != nil {
+		wErr := fmt.Errorf("failed to begin passkey registration: %w", err)
+		return nil, ce.NewError(span, ce.CodePasskeyCeremonyFailed, ce.MsgInternalServer, wErr)
+	}
+
+	sessionData, err := json.Marshal(session)
+	if err != nil {
+		wErr := fmt.Errorf("failed to begin passkey registration: %w", err)
+		return nil, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	err = u.pc.CreateRegistrationChallenge(ctx, authID, sessionData, u.cfg.Auth.TokenDuration.WebAuthn)
+	if err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+func (u *passkeyUsecase) FinishRegistration(ctx context.Context, authID int64, data *entities.RegisterPasskey) (*entities.Passkey, error) {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "FinishRegistration")
+	defer span.End()
+
+	sessionData, err := u.pc.UseRegistrationChallenge(ctx, authID)
+	if err != nil {
+		return nil, err
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal(sessionData, &session); err != nil {
+		wErr := fmt.Errorf("failed to finish passkey registration: %w", err)
+		return nil, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	user, err := u.getUser(ctx, span, authID)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := u.webauthn.FinishRegistration(user, &session, data.Response)
+	if err != nil {
+		wErr := fmt.Errorf("failed to finish passkey registration: %w", err)
+		return nil, ce.NewError(span, ce.CodePasskeyRegistration, "Invalid passkey registration", wErr)
+	}
+
+	encodedCredential, err := json.Marshal(credential)
+	if err != nil {
+		wErr := fmt.Errorf("failed to finish passkey registration: %w", err)
+		return nil, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	newPasskeyData := entities.CreatePasskey{
+		CredentialID: credential.ID,
+		Credential:   encodedCredential,
+		Name:         data.Name,
+	}
+
+	return u.pr.Create(ctx, authID, &newPasskeyData)
+}
+
+func (u *passkeyUsecase) BeginLogin(ctx context.Context) (string, *protocol.CredentialAssertion, error) {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "BeginLogin")
+	defer span.End()
+
+	options, session, err := u.webauthn.BeginLogin()
+	if err != nil {
+		wErr := fmt.Errorf("failed to begin passkey login: %w", err)
+		return "", nil, ce.NewError(span, ce.CodePasskeyCeremonyFailed, ce.MsgInternalServer, wErr)
+	}
+
+	sessionData, err := json.Marshal(session)
+	if err != nil {
+		wErr := fmt.Errorf("failed to begin passkey login: %w", err)
+		return "", nil, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	challengeID := utils.NewUUID().String()
+	err = u.pc.CreateLoginChallenge(ctx, challengeID, sessionData, u.cfg.Auth.TokenDuration.WebAuthn)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return challengeID, options, nil
+}
+
+func (u *passkeyUsecase) FinishLogin(ctx context.Context, data *entities.LoginPasskey, request *entities.Request) (*entities.AuthToken, *entities.Auth, error) {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "FinishLogin")
+	defer span.End()
+
+	now := time.Now().UTC()
+
+	sessionData, err := u.pc.UseLoginChallenge(ctx, data.ChallengeID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal(sessionData, &session); err != nil {
+		wErr := fmt.Errorf("failed to finish passkey login: %w", err)
+		return nil, nil, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+	}
+
+	var failedAuthID *int64
+	var auth *entities.Auth
+	var authToken entities.AuthToken
+	err = u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var passkey *entities.Passkey
+		handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+			found, err := u.pr.GetByCredentialID(ctx, rawID)
+			if err != nil {
+				return nil, err
+			}
+			passkey = found
+
+			user, err := u.getUser(ctx, span, passkey.AuthID)
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(user.WebAuthnID(), userHandle) {
+				return nil, errors.New("user handle does not match credential owner")
+			}
+
+			return user, nil
+		}
+
+		user, credential, err := u.webauthn.FinishLogin(handler, &session, data.Response)
+		if err != nil {
+			if passkey != nil {
+				failedAuthID = &passkey.AuthID
+			}
+			wErr := fmt.Errorf("failed to finish passkey login: %w", err)
+			return ce.NewError(span, ce.CodePasskeyLoginFailed, ce.MsgInvalidCredentials, wErr)
+		}
+		if credential.Authenticator.CloneWarning {
+			failedAuthID = &passkey.AuthID
+			err := fmt.Errorf("failed to finish passkey login: %w", errors.New("possible cloned authenticator"))
+			return ce.NewError(span, ce.CodePasskeyLoginFailed, ce.MsgInvalidCredentials, err)
+		}
+
+		// persist the updated sign count
+		encodedCredential, err := json.Marshal(credential)
+		if err != nil {
+			wErr := fmt.Errorf("failed to finish passkey login: %w", err)
+			return ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+		}
+		if err := u.pr.UpdateCredential(ctx, passkey.ID, encodedCredential); err != nil {
+			return err
+		}
+
+		auth, err = u.ar.GetByID(ctx, user.(*services.WebAuthnUser).AuthID())
+		if err != nil {
+			return err
+		}
+		if err := checkAccountState(span, auth); err != nil {
+			return err
+		}
+
+		sessionToken := utils.NewUUID().String()
+		accessToken, err := u.jwt.Create(auth)
+		if err != nil {
+			wErr := fmt.Errorf("failed to finish passkey login: %w", err)
+			return ce.NewError(span, ce.CodeJWTGenerationFailed, ce.MsgInternalServer, wErr)
+		}
+
+		newSessionData := entities.CreateSession{
+			Token:     sessionToken,
+			UserAgent: request.UserAgent,
+			IPAddress: request.IPAddress,
+			ExpiresAt: now.Add(u.cfg.Auth.TokenDuration.Session),
+		}
+		if err := u.su.CreateSession(ctx, auth.ID, &newSessionData); err != nil {
+			return err
+		}
+		if err := u.su.TrackAccessToken(ctx, auth.ID, sessionToken, "", accessToken); err != nil {
+			log.Println("WARNING ->", err.Error())
+		}
+
+		authToken = entities.AuthToken{
+			AccessToken:  accessToken.Token,
+			SessionToken: sessionToken,
+		}
+
+		return nil
+	})
+	if failedAuthID != nil {
+		details := map[string]any{"method": constants.LoginMethodPasskey, "reason": constants.LoginFailureBadPasskey}
+		u.seu.Record(ctx, failedAuthID, constants.EventTypeLoginFailed, details)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	u.seu.Record(ctx, &auth.ID, constants.EventTypeLoginSucceeded, map[string]any{"method": constants.LoginMethodPasskey})
+	u.sau.Check(ctx, auth.ID, authToken.SessionToken)
+	return &authToken, auth, nil
+}
+
+func (u *passkeyUsecase) GetAll(ctx context.Context, authID int64) ([]entities.Passkey, error) {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "GetAll")
+	defer span.End()
+
+	return u.pr.GetAllByAuthID(ctx, authID)
+}
+
+func (u *passkeyUsecase) Rename(ctx context.Context, authID, passkeyID int64, name string) (*entities.Passkey, error) {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "Rename")
+	defer span.End()
+
+	return u.pr.UpdateName(ctx, authID, passkeyID, name)
+}
+
+func (u *passkeyUsecase) Delete(ctx context.Context, authID, passkeyID int64) error {
+	ctx, span := otel.Tracer(passkeyErrorTracer).Start(ctx, "Delete")
+	defer span.End()
+
+	return u.pr.Delete(ctx, authID, passkeyID)
+}
+
+func (u *passkeyUsecase) getUser(ctx context.Context, span trace.Span, authID int64) (*services.WebAuthnUser, error) {
+	auth, err := u.ar.GetByID(ctx, authID)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys, err := u.pr.GetAllByAuthID(ctx, authID)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := make([]webauthn.Credential, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		var credential webauthn.Credential
+		if err := json.Unmarshal(passkey.Credential, &credential); err != nil {
+			wErr := fmt.Errorf("failed to fetch passkey user: %w", err)
+			return nil, ce.NewError(span, ce.CodeTypeConversionFailed, ce.MsgInternalServer, wErr)
+		}
+		credentials = append(credentials, credential)
+	}
+
+	return services.NewWebAuthnUser(auth.ID, auth.Email, credentials), nil
+}
@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/breach"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+)
+
+const passwordPolicyErrorTracer string = "usecase.password_policy"
+
+// shorter local parts match too many passwords by accident
+const emailLocalPartMinLength int = 3
+
+type PasswordPolicyUsecase interface {
+	Check(ctx context.Context, auth *entities.Auth, password string) (err error)
+	Record(ctx context.Context, authID int64, hashedPassword string) (err error)
+}
+
+type passwordPolicyUsecase struct {
+	phr     repositories.PasswordHistoryRepository
+	checker *breach.Checker
+	hasher  services.PasswordHasher
+	cfg     *configs.Config
+}
+
+// a nil checker turns the breached password check off
+func NewPasswordPolicyUsecase(
+	phr repositories.PasswordHistoryRepository,
+	checker *breach.Checker,
+	hasher services.PasswordHasher,
+	cfg *configs.Config,
+) PasswordPolicyUsecase {
+	return &passwordPolicyUsecase{phr, checker, hasher, cfg}
+}
+
+func (u *passwordPolicyUsecase) Check(ctx context.Context, auth *entities.Auth, password string) error {
+	ctx, span := otel.Tracer(passwordPolicyErrorTracer).Start(ctx, "Check")
+	defer span.End()
+
+	localPart, _, _ := strings.Cut(utils.Normalize(auth.Email), "@")
+	if len(localPart) >= emailLocalPartMinLength && strings.Contains(strings.ToLower(password), localPart) {
+		err := fmt.Errorf("failed to check password: %w", errors.New("password contains email"))
+		return ce.NewError(span, ce.CodePasswordContainsEmail, "Password cannot contain your email address", err)
+	}
+
+	if u.checker != nil {
+		isBreached, err := u.checker.IsBreached(ctx, password)
+		if err != nil {
+			// non-fatal: an unreachable corpus must not block every password change
+			log.Println("WARNING ->", fmt.Errorf("failed to check breached password: %w", err).Error())
+		}
+		if isBreached {
+			err := fmt.Errorf("failed to check password: %w", errors.New("password found in breach corpus"))
+			return ce.NewError(span, ce.CodePasswordBreached, "Password has appeared in a data breach, please choose another", err)
+		}
+	}
+
+	if auth.ID == 0 || u.cfg.Auth.PasswordPolicy.HistorySize <= 0 {
+		return nil
+	}
+
+	previous, err := u.phr.GetRecent(ctx, auth.ID, u.cfg.Auth.PasswordPolicy.HistorySize)
+	if err != nil {
+		return err
+	}
+	if auth.Password != nil && !slices.Contains(previous, *auth.Password) {
+		// accounts from before the history was kept only have their current password
+		previous = append(previous, *auth.Password)
+	}
+	for _, hashedPassword := range previous {
+		if err := u.hasher.Validate(hashedPassword, password); err == nil {
+			err := fmt.Errorf("failed to check password: %w", errors.New("password used recently"))
+			return ce.NewError(span, ce.CodePasswordReused, "Password was used recently, please choose another", err)
+		}
+	}
+
+	return nil
+}
+
+func (u *passwordPolicyUsecase) Record(ctx context.Context, authID int64, hashedPassword string) error {
+	ctx, span := otel.Tracer(passwordPolicyErrorTracer).Start(ctx, "Record")
+	defer span.End()
+
+	historySize := u.cfg.Auth.PasswordPolicy.HistorySize
+	if historySize <= 0 {
+		return nil
+	}
+
+	if err := u.phr.Create(ctx, authID, hashedPassword); err != nil {
+		return err
+	}
+	return u.phr.Prune(ctx, authID, historySize)
+}
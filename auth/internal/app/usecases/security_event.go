@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const securityEventErrorTracer string = "usecase.security_event"
+
+type SecurityEventUsecase interface {
+	Record(ctx context.Context, authID *int64, eventType string, details map[string]any)
+	GetEvents(ctx context.Context, authID int64, cursor string, limit int) (events []entities.SecurityEvent, nextCursor string, err error)
+	SearchEvents(ctx context.Context, filter *entities.SecurityEventFilter, cursor string) (events []entities.SecurityEvent, nextCursor string, err error)
+}
+
+type securityEventUsecase struct {
+	ser repositories.SecurityEventRepository
+	cfg *configs.Config
+}
+
+func NewSecurityEventUsecase(ser repositories.SecurityEventRepository, cfg *configs.Config) SecurityEventUsecase {
+	return &securityEventUsecase{ser, cfg}
+}
+
+// non-fatal, so it must be called outside of transactions: a failed insert would abort them
+func (u *securityEventUsecase) Record(ctx context.Context, authID *int64, eventType string, details map[string]any) {
+	ctx, span := otel.Tracer(securityEventErrorTracer).Start(ctx, "Record")
+	defer span.End()
+
+	data := newSecurityEvent(ctx, eventType, details)
+	if err := u.ser.Create(ctx, authID, &data); err != nil {
+		log.Println("WARNING ->", err.Error())
+	}
+}
+
+func (u *securityEventUsecase) GetEvents(ctx context.Context, authID int64, cursor string, limit int) ([]entities.SecurityEvent, string, error) {
+	ctx, span := otel.Tracer(securityEventErrorTracer).Start(ctx, "GetEvents")
+	defer span.End()
+
+	filter := entities.SecurityEventFilter{AuthID: &authID, Limit: limit}
+	return u.search(ctx, span, &filter, cursor)
+}
+
+func (u *securityEventUsecase) SearchEvents(ctx context.Context, filter *entities.SecurityEventFilter, cursor string) ([]entities.SecurityEvent, string, error) {
+	ctx, span := otel.Tracer(securityEventErrorTracer).Start(ctx, "SearchEvents")
+	defer span.End()
+
+	return u.search(ctx, span, filter, cursor)
+}
+
+func (u *securityEventUsecase) search(ctx context.Context, span trace.Span, filter *entities.SecurityEventFilter, cursor string) ([]entities.SecurityEvent, string, error) {
+	if cursor != "" {
+		beforeID, err := decodeEventCursor(cursor)
+		if err != nil {
+			wErr := fmt.Errorf("failed to search security events: %w", err)
+			return nil, "", ce.NewError(span, ce.CodeInvalidParams, "Invalid cursor", wErr)
+		}
+		filter.BeforeID = beforeID
+	}
+	if filter.Limit < 1 {
+		filter.Limit = u.cfg.Auth.SecurityEvents.PageSize
+	}
+	if filter.Limit > u.cfg.Auth.SecurityEvents.MaxPageSize {
+		filter.Limit = u.cfg.Auth.SecurityEvents.MaxPageSize
+	}
+
+	// one extra row tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	events, err := u.ser.Search(ctx, filter)
+	filter.Limit = limit
+	if err != nil {
+		return nil, "", err
+	}
+	if len(events) <= limit {
+		return events, "", nil
+	}
+
+	events = events[:limit]
+	return events, encodeEventCursor(events[limit-1].ID), nil
+}
+
+// request metadata is taken from the context, so events can be recorded from anywhere in a flow
+func newSecurityEvent(ctx context.Context, eventType string, details map[string]any) entities.CreateSecurityEvent {
+	data := entities.CreateSecurityEvent{
+		EventType: eventType,
+		Details:   details,
+		UserAgent: utils.CtxGetUserAgent(ctx),
+		IPAddress: utils.CtxGetIPAddress(ctx),
+		RequestID: utils.CtxGetRequestID(ctx),
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.HasTraceID() {
+		data.TraceID = spanCtx.TraceID().String()
+	}
+	return data
+}
+
+func encodeEventCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeEventCursor(cursor string) (int64, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := strconv.ParseInt(string(decoded), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if id < 1 {
+		return 0, errors.New("cursor out of range")
+	}
+	return id, nil
+}
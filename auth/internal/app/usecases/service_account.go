@@ -0,0 +1,158 @@
+package usecases
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/caches"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const serviceAccountErrorTracer string = "usecase.service_account"
+
+type ServiceAccountUsecase interface {
+	IssueToken(ctx context.Context, data *entities.ClientCredentials) (accessToken *entities.AccessToken, scopes []string, err error)
+	RetireExpiredSecrets(ctx context.Context) (err error)
+}
+
+type serviceAccountUsecase struct {
+	sar repositories.ServiceAccountRepository
+	tdc caches.TokenDenylistCache
+	seu SecurityEventUsecase
+	jwt *services.JWTService
+	cfg *configs.Config
+}
+
+func NewServiceAccountUsecase(
+	sar repositories.ServiceAccountRepository,
+	tdc caches.TokenDenylistCache,
+	seu SecurityEventUsecase,
+	jwt *services.JWTService,
+	cfg *configs.Config,
+) ServiceAccountUsecase {
+	return &serviceAccountUsecase{sar, tdc, seu, jwt, cfg}
+}
+
+func (u *serviceAccountUsecase) IssueToken(ctx context.Context, data *entities.ClientCredentials) (*entities.AccessToken, []string, error) {
+	ctx, span := otel.Tracer(serviceAccountErrorTracer).Start(ctx, "IssueToken")
+	defer span.End()
+
+	serviceAccount, err := u.sar.GetByClientID(ctx, data.ClientID)
+	if err != nil {
+		var cErr *ce.Error
+		if errors.As(err, &cErr) && cErr.Code == ce.CodeClientInvalid {
+			u.recordAuthFailure(ctx, data.ClientID, constants.ClientAuthFailureUnknownClient)
+		}
+		return nil, nil, err
+	}
+	if serviceAccount.DisabledAt != nil {
+		u.recordAuthFailure(ctx, data.ClientID, constants.ClientAuthFailureDisabled)
+		err := fmt.Errorf("failed to issue token: %w", errors.New("service account disabled"))
+		return nil, nil, ce.NewError(span, ce.CodeClientInvalid, ce.MsgInvalidClient, err)
+	}
+	secretHash, isValid := checkClientSecret(serviceAccount, data.ClientSecret)
+	if !isValid {
+		u.recordAuthFailure(ctx, data.ClientID, constants.ClientAuthFailureWrongSecret)
+		err := fmt.Errorf("failed to issue token: %w", errors.New("wrong client secret"))
+		return nil, nil, ce.NewError(span, ce.CodeClientInvalid, ce.MsgInvalidClient, err)
+	}
+
+	// no requested scope means everything the account was granted
+	scopes := data.Scopes
+	if len(scopes) == 0 {
+		scopes = serviceAccount.Scopes
+	}
+	if err := checkScopes(span, scopes, serviceAccount.Scopes); err != nil {
+		return nil, nil, err
+	}
+
+	accessToken, err := u.jwt.CreateService(serviceAccount.ClientID, scopes, u.cfg.Auth.ServiceAccount.TokenDuration)
+	if err != nil {
+		wErr := fmt.Errorf("failed to issue token: %w", err)
+		return nil, nil, ce.NewError(span, ce.CodeJWTGenerationFailed, ce.MsgInternalServer, wErr)
+	}
+
+	// an untracked token could not be cut short by a disable or a rotation, so it is not handed out
+	if err := u.tdc.TrackClient(ctx, serviceAccount.ClientID, secretHash, accessToken); err != nil {
+		return nil, nil, err
+	}
+
+	details := map[string]any{"client_id": serviceAccount.ClientID, "scopes": scopes, "token_id": accessToken.ID}
+	u.seu.Record(ctx, nil, constants.EventTypeClientTokenIssued, details)
+
+	return accessToken, scopes, nil
+}
+
+func (u *serviceAccountUsecase) RetireExpiredSecrets(ctx context.Context) error {
+	ctx, span := otel.Tracer(serviceAccountErrorTracer).Start(ctx, "RetireExpiredSecrets")
+	defer span.End()
+
+	serviceAccounts, err := u.sar.GetExpiredPreviousSecrets(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the grace period is over, so are the tokens issued with the rotated-out secret
+	for _, serviceAccount := range serviceAccounts {
+		if err := u.tdc.DenyClientSecret(ctx, serviceAccount.ClientID, *serviceAccount.PreviousSecretHash); err != nil {
+			return err
+		}
+		if err := u.sar.ClearPreviousSecret(ctx, serviceAccount.ID, *serviceAccount.PreviousSecretHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *serviceAccountUsecase) recordAuthFailure(ctx context.Context, clientID, reason string) {
+	details := map[string]any{"client_id": clientID, "reason": reason}
+	u.seu.Record(ctx, nil, constants.EventTypeClientAuthFailed, details)
+}
+
+// secrets are random and long, so a fast hash is enough to keep them out of the database,
+// the matching hash is returned to tag the tokens issued with it
+func checkClientSecret(serviceAccount *entities.ServiceAccount, secret string) (string, bool) {
+	hash := utils.HashSHA256(secret)
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(serviceAccount.SecretHash)) == 1 {
+		return hash, true
+	}
+
+	// the secret replaced by the last rotation is honored until its grace period ends
+	if serviceAccount.PreviousSecretHash == nil || serviceAccount.PreviousSecretExpiresAt == nil {
+		return "", false
+	}
+	if time.Now().UTC().After(*serviceAccount.PreviousSecretExpiresAt) {
+		return "", false
+	}
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(*serviceAccount.PreviousSecretHash)) == 1 {
+		return hash, true
+	}
+	return "", false
+}
+
+func checkScopes(span trace.Span, scopes, allowed []string) error {
+	for _, scope := range scopes {
+		if !slices.Contains(allowed, scope) {
+			err := fmt.Errorf("failed to check scopes: %w", fmt.Errorf("scope %q not allowed", scope))
+			return ce.NewError(span, ce.CodeScopeInvalid, ce.MsgInvalidScope, err)
+		}
+	}
+	return nil
+}
+
+func newClientSecret() (secret, hash string) {
+	secret = utils.NewRandomToken() + utils.NewRandomToken()
+	return secret, utils.HashSHA256(secret)
+}
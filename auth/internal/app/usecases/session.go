@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/caches"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/publishers"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
 	"go.opentelemetry.io/otel"
 )
 
@@ -20,42 +25,80 @@ type SessionUsecase interface {
 	GetSession(ctx context.Context, token string) (session *entities.Session, err error)
 	RevokeSession(ctx context.Context, token string) (err error)
 	RefreshSession(ctx context.Context, authID int64, data *entities.CreateSession) (err error)
+	GetActiveSessions(ctx context.Context, authID int64) (sessions []entities.Session, err error)
+	GetRecentSessions(ctx context.Context, authID, excludeID int64, limit int) (sessions []entities.Session, err error)
+	RevokeSessionByID(ctx context.Context, authID, sessionID int64) (err error)
+	RevokeOtherSessions(ctx context.Context, authID int64, token string) (err error)
+	RevokeAllSessions(ctx context.Context, authID int64, exceptToken string) (err error)
+	GetReplacement(ctx context.Context, sessionID int64) (session *entities.Session, err error)
+	RevokeFamily(ctx context.Context, authID, sessionID int64) (err error)
+	TrackAccessToken(ctx context.Context, authID int64, token, parentToken string, accessToken *entities.AccessToken) (err error)
 }
 
 type sessionUsecase struct {
 	sr         repositories.SessionRepository
+	tdc        caches.TokenDenylistCache
+	aep        publishers.AuthEventPublisher
 	transactor *database.Transactor
+	cfg        *configs.Config
 }
 
 func NewSessionUsecase(
 	sr repositories.SessionRepository,
+	tdc caches.TokenDenylistCache,
+	aep publishers.AuthEventPublisher,
 	transactor *database.Transactor,
+	cfg *configs.Config,
 ) SessionUsecase {
-	return &sessionUsecase{sr, transactor}
+	return &sessionUsecase{sr, tdc, aep, transactor, cfg}
 }
 
 func (u *sessionUsecase) CreateSession(ctx context.Context, authID int64, data *entities.CreateSession) error {
 	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "CreateSession")
 	defer span.End()
 
-	return u.transactor.WithTx(ctx, func(ctx context.Context) error {
-		revokedSessionID, err := u.sr.RevokeActive(ctx, authID)
-		if err != nil {
-			return err
-		}
-		if revokedSessionID != 0 {
-			data.ParentID = &revokedSessionID
+	maxActive := u.cfg.Auth.Session.MaxActive
+
+	var evictedTokens []string
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		// make room for the new session by revoking the least recently used ones,
+		// there is no limit on concurrent sessions otherwise
+		if maxActive > 0 {
+			var err error
+			evictedTokens, err = u.sr.RevokeExcess(ctx, authID, maxActive-1)
+			if err != nil {
+				return err
+			}
 		}
 
-		return u.sr.Create(ctx, authID, data)
+		if err := u.sr.Create(ctx, authID, data); err != nil {
+			return err
+		}
+		return u.aep.PublishSessionCreated(ctx, authID, data)
 	})
+	if err != nil {
+		return err
+	}
+
+	// the access tokens of an evicted session would otherwise outlive it
+	for _, token := range evictedTokens {
+		if err := u.tdc.DenySession(ctx, authID, token); err != nil {
+			log.Println("WARNING ->", err.Error())
+		}
+	}
+	return nil
 }
 
 func (u *sessionUsecase) CreateFirstSession(ctx context.Context, authID int64, data *entities.CreateSession) error {
 	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "CreateFirstSession")
 	defer span.End()
 
-	return u.sr.Create(ctx, authID, data)
+	return u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		if err := u.sr.Create(ctx, authID, data); err != nil {
+			return err
+		}
+		return u.aep.PublishSessionCreated(ctx, authID, data)
+	})
 }
 
 func (u *sessionUsecase) GetSession(ctx context.Context, token string) (*entities.Session, error) {
@@ -69,7 +112,26 @@ func (u *sessionUsecase) RevokeSession(ctx context.Context, token string) error
 	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "RevokeSession")
 	defer span.End()
 
-	return u.sr.RevokeByToken(ctx, token)
+	session, err := u.sr.GetByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	err = u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		if err := u.sr.RevokeByToken(ctx, token); err != nil {
+			return err
+		}
+		return u.aep.PublishSessionRevoked(ctx, session.AuthID, constants.SessionRevokeReasonLogout)
+	})
+	if err != nil {
+		return err
+	}
+
+	// non-fatal: the session is revoked, its access tokens still run out on their own
+	if err := u.tdc.DenySession(ctx, session.AuthID, token); err != nil {
+		log.Println("WARNING ->", err.Error())
+	}
+	return nil
 }
 
 func (u *sessionUsecase) RefreshSession(ctx context.Context, authID int64, data *entities.CreateSession) error {
@@ -89,3 +151,115 @@ func (u *sessionUsecase) RefreshSession(ctx context.Context, authID int64, data
 		return u.sr.Create(ctx, authID, data)
 	})
 }
+
+func (u *sessionUsecase) GetActiveSessions(ctx context.Context, authID int64) ([]entities.Session, error) {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "GetActiveSessions")
+	defer span.End()
+
+	return u.sr.GetAllActiveByAuthID(ctx, authID)
+}
+
+func (u *sessionUsecase) GetRecentSessions(ctx context.Context, authID, excludeID int64, limit int) ([]entities.Session, error) {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "GetRecentSessions")
+	defer span.End()
+
+	return u.sr.GetRecentByAuthID(ctx, authID, excludeID, limit)
+}
+
+func (u *sessionUsecase) RevokeSessionByID(ctx context.Context, authID, sessionID int64) error {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "RevokeSessionByID")
+	defer span.End()
+
+	var token string
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		token, err = u.sr.RevokeOwned(ctx, authID, sessionID)
+		if err != nil {
+			return err
+		}
+		return u.aep.PublishSessionRevoked(ctx, authID, constants.SessionRevokeReasonRevoked)
+	})
+	if err != nil {
+		return err
+	}
+
+	// non-fatal: the session is revoked, its access tokens still run out on their own
+	if err := u.tdc.DenySession(ctx, authID, token); err != nil {
+		log.Println("WARNING ->", err.Error())
+	}
+	return nil
+}
+
+func (u *sessionUsecase) RevokeOtherSessions(ctx context.Context, authID int64, token string) error {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "RevokeOtherSessions")
+	defer span.End()
+
+	session, err := u.sr.GetByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	if session.AuthID != authID || session.RevokedAt != nil {
+		err := fmt.Errorf("failed to revoke other sessions: %w", errors.New("session not active for auth"))
+		return ce.NewError(span, ce.CodeSessionNotFound, ce.MsgUnauthenticated, err)
+	}
+
+	return u.RevokeAllSessions(ctx, authID, token)
+}
+
+func (u *sessionUsecase) RevokeAllSessions(ctx context.Context, authID int64, exceptToken string) error {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "RevokeAllSessions")
+	defer span.End()
+
+	// an empty token matches no session, so every session gets revoked
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		if err := u.sr.RevokeOthers(ctx, authID, exceptToken); err != nil {
+			return err
+		}
+		return u.aep.PublishSessionRevoked(ctx, authID, constants.SessionRevokeReasonRevokedAll)
+	})
+	if err != nil {
+		return err
+	}
+
+	// non-fatal: the sessions are revoked, their access tokens still run out on their own
+	if err := u.tdc.DenyAll(ctx, authID, exceptToken); err != nil {
+		log.Println("WARNING ->", err.Error())
+	}
+	return nil
+}
+
+func (u *sessionUsecase) GetReplacement(ctx context.Context, sessionID int64) (*entities.Session, error) {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "GetReplacement")
+	defer span.End()
+
+	return u.sr.GetByParentID(ctx, sessionID)
+}
+
+func (u *sessionUsecase) RevokeFamily(ctx context.Context, authID, sessionID int64) error {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "RevokeFamily")
+	defer span.End()
+
+	err := u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		if err := u.sr.RevokeFamily(ctx, sessionID); err != nil {
+			return err
+		}
+		return u.aep.PublishSessionRevoked(ctx, authID, constants.SessionRevokeReasonReuseDetected)
+	})
+	if err != nil {
+		return err
+	}
+
+	// whoever replayed the token may hold access tokens from any session,
+	// so none of them are trusted anymore, other devices simply refresh
+	if err := u.tdc.DenyAll(ctx, authID, ""); err != nil {
+		log.Println("WARNING ->", err.Error())
+	}
+	return nil
+}
+
+func (u *sessionUsecase) TrackAccessToken(ctx context.Context, authID int64, token, parentToken string, accessToken *entities.AccessToken) error {
+	ctx, span := otel.Tracer(sessionErrorTracer).Start(ctx, "TrackAccessToken")
+	defer span.End()
+
+	return u.tdc.Track(ctx, authID, token, parentToken, accessToken)
+}
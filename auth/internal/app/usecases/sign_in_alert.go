@@ -0,0 +1,186 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"math"
+	"net"
+
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/caches"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/publishers"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/repositories"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/geoip"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+)
+
+const signInAlertErrorTracer string = "usecase.sign_in_alert"
+
+type SignInAlertUsecase interface {
+	Check(ctx context.Context, authID int64, sessionToken string)
+	ReportSignIn(ctx context.Context, token string) (err error)
+}
+
+type signInAlertUsecase struct {
+	ar         repositories.AuthRepository
+	ac         caches.AuthCache
+	su         SessionUsecase
+	seu        SecurityEventUsecase
+	aep        publishers.AuthEventPublisher
+	transactor *database.Transactor
+	geoip      *geoip.Reader
+	cfg        *configs.Config
+}
+
+func NewSignInAlertUsecase(
+	ar repositories.AuthRepository,
+	ac caches.AuthCache,
+	su SessionUsecase,
+	seu SecurityEventUsecase,
+	aep publishers.AuthEventPublisher,
+	transactor *database.Transactor,
+	geoip *geoip.Reader,
+	cfg *configs.Config,
+) SignInAlertUsecase {
+	return &signInAlertUsecase{ar, ac, su, seu, aep, transactor, geoip, cfg}
+}
+
+// non-fatal and called once the session is committed: the sign-in has already succeeded
+func (u *signInAlertUsecase) Check(ctx context.Context, authID int64, sessionToken string) {
+	ctx, span := otel.Tracer(signInAlertErrorTracer).Start(ctx, "Check")
+	defer span.End()
+
+	if err := u.check(ctx, authID, sessionToken); err != nil {
+		log.Println("WARNING ->", err.Error())
+	}
+}
+
+func (u *signInAlertUsecase) check(ctx context.Context, authID int64, sessionToken string) error {
+	session, err := u.su.GetSession(ctx, sessionToken)
+	if err != nil {
+		return err
+	}
+
+	recent, err := u.su.GetRecentSessions(ctx, authID, session.ID, u.cfg.Auth.SignInAlert.HistorySize)
+	if err != nil {
+		return err
+	}
+	if len(recent) == 0 {
+		// the first sign-in of the account has nothing to be compared against
+		return nil
+	}
+	for _, previous := range recent {
+		if previous.UserAgent == session.UserAgent && previous.IPAddress == session.IPAddress {
+			return nil
+		}
+	}
+
+	location := u.locate(session.IPAddress)
+	alert := entities.SignInAlert{
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		Location:   location.String(),
+		SignedInAt: session.CreatedAt,
+	}
+	details := map[string]any{"session_id": session.ID, "location": alert.Location}
+
+	// measured against the latest activity, rotated sessions carry the time of their last refresh
+	previous := recent[0]
+	previousLocation := u.locate(previous.IPAddress)
+	if location.HasCoordinates() && previousLocation.HasCoordinates() {
+		distance := geoip.Distance(previousLocation, location)
+		hours := session.CreatedAt.Sub(previous.CreatedAt).Hours()
+		if distance >= u.cfg.Auth.SignInAlert.MinTravelDistance &&
+			(hours <= 0 || distance/hours > u.cfg.Auth.SignInAlert.MaxTravelSpeed) {
+			alert.IsImpossibleTravel = true
+			details["previous_location"] = previousLocation.String()
+			details["distance_km"] = math.Round(distance)
+		}
+	}
+	details["impossible_travel"] = alert.IsImpossibleTravel
+	u.seu.Record(ctx, &authID, constants.EventTypeNewSignInDetected, details)
+
+	auth, err := u.ar.GetByID(ctx, authID)
+	if err != nil {
+		return err
+	}
+
+	token := utils.NewRandomToken()
+	err = u.ac.CreateSignInReportToken(
+		ctx, authID, session.ID, token,
+		u.cfg.Auth.TokenDuration.SignInReport,
+	)
+	if err != nil {
+		return err
+	}
+
+	return u.aep.PublishNewSignInDetected(ctx, auth.ID, auth.Email, token, &alert)
+}
+
+func (u *signInAlertUsecase) ReportSignIn(ctx context.Context, token string) error {
+	ctx, span := otel.Tracer(signInAlertErrorTracer).Start(ctx, "ReportSignIn")
+	defer span.End()
+
+	// only used up once the report is carried out, a failed attempt leaves the link working
+	authID, sessionID, err := u.ac.GetSignInReportToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	err = u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		auth, err := u.ar.GetByID(ctx, authID)
+		if err != nil {
+			return err
+		}
+
+		// the reported session may have been refreshed or spread, so none is trusted
+		if err := u.su.RevokeAllSessions(ctx, authID, ""); err != nil {
+			return err
+		}
+		if auth.Password == nil {
+			// this is an oauth type account
+			// there is no password to reset, the provider's credentials are out of reach
+			return nil
+		}
+
+		// whoever signed in knows the password, so signing in is blocked until it is reset
+		if err := u.ar.RequirePasswordReset(ctx, authID); err != nil {
+			return err
+		}
+
+		resetToken := utils.NewUUID().String()
+		if err := u.ac.CreateResetToken(ctx, authID, resetToken, u.cfg.Auth.TokenDuration.Reset); err != nil {
+			return err
+		}
+		return u.aep.PublishPasswordResetRequested(ctx, authID, auth.Email, resetToken)
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, _, err := u.ac.UseSignInReportToken(ctx, token); err != nil {
+		// non-fatal: reporting the same sign-in twice changes nothing
+		log.Println("WARNING ->", err.Error())
+	}
+
+	u.seu.Record(ctx, &authID, constants.EventTypeSignInReported, map[string]any{"session_id": sessionID})
+	return nil
+}
+
+// nil when no database is configured or the address is not in it
+func (u *signInAlertUsecase) locate(ipAddress string) *geoip.Location {
+	if u.geoip == nil {
+		return nil
+	}
+
+	location, err := u.geoip.Lookup(net.ParseIP(ipAddress))
+	if err != nil {
+		log.Println("WARNING ->", err.Error())
+		return nil
+	}
+	return location
+}
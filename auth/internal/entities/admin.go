@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+// an account as seen from the back office
+type Account struct {
+	ID                    int64
+	Email                 string
+	RoleID                int16
+	IsVerified            bool
+	IsApproved            bool
+	HasPassword           bool
+	SuspendedAt           *time.Time
+	SuspensionReason      *string
+	PasswordResetRequired bool
+	DeletionScheduledAt   *time.Time
+	Application           *PharmacyApplication
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+type AccountFilter struct {
+	Email      string
+	RoleID     *int16
+	IsVerified *bool
+	IsApproved *bool
+	Page       int
+	Limit      int
+}
+
+type CreateAdminAudit struct {
+	TargetID  *int64
+	Action    string
+	Details   map[string]any
+	UserAgent string
+	IPAddress string
+}
@@ -3,24 +3,39 @@ package entities
 import "time"
 
 type Auth struct {
-	ID                int64
-	Email             string
-	Password          *string
-	RoleID            int16
-	IsVerified        bool
-	EmailChangedAt    *time.Time
-	PasswordChangedAt *time.Time
-	CreatedAt         time.Time
-	UpdatedAt         time.Time
+	ID                    int64
+	Email                 string
+	Password              *string
+	RoleID                int16
+	IsVerified            bool
+	IsApproved            bool
+	EmailChangedAt        *time.Time
+	PasswordChangedAt     *time.Time
+	SuspendedAt           *time.Time
+	PasswordResetRequired bool
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
 }
 
 type CreateAuth struct {
-	Email    string
-	Password *string
-	RoleID   int16
+	Email       string
+	Password    *string
+	RoleID      int16
+	IsApproved  bool
+	Application *CreatePharmacyApplication
 }
 
 type GetAuth struct {
 	Email    string
 	Password string
 }
+
+type ConfirmIdentity struct {
+	Password string
+	Code     string
+}
+
+type AccountDeletion struct {
+	RequestedAt time.Time
+	ScheduledAt time.Time
+}
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+type Lockout struct {
+	IsLocked   bool
+	RetryAfter time.Duration
+}
+
+type LockoutPolicy struct {
+	MaxAttempts int
+	Window      time.Duration
+	Duration    time.Duration
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+type MFA struct {
+	ID        int64
+	AuthID    int64
+	Secret    string
+	IsEnabled bool
+	EnabledAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type MFAEnrollment struct {
+	Secret string
+	URI    string
+}
+
+type VerifyMFA struct {
+	Token string
+	Code  string
+}
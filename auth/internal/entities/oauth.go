@@ -1,8 +1,25 @@
 package entities
 
+import "time"
+
 type OAuth struct {
 	Provider   int16
 	UID        string
 	Email      string
 	IsVerified bool
 }
+
+type OAuthState struct {
+	State      string
+	Provider   int16
+	Verifier   string
+	Nonce      string
+	RedirectTo string
+	AuthID     int64 // set when linking to an existing account
+}
+
+type OAuthIdentity struct {
+	Provider  int16
+	UID       string
+	CreatedAt time.Time
+}
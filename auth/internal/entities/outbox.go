@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+type OutboxMessage struct {
+	ID            int64
+	EventID       string
+	EventType     string
+	Topic         string
+	Key           string
+	Payload       []byte
+	TraceID       string
+	CorrelationID string
+	Attempts      int
+	CreatedAt     time.Time
+}
+
+type CreateOutboxMessage struct {
+	EventID       string
+	EventType     string
+	Topic         string
+	Key           string
+	Payload       []byte
+	TraceID       string
+	CorrelationID string
+}
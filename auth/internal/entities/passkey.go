@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+type Passkey struct {
+	ID           int64
+	AuthID       int64
+	CredentialID []byte
+	Credential   []byte
+	Name         string
+	LastUsedAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type CreatePasskey struct {
+	CredentialID []byte
+	Credential   []byte
+	Name         string
+}
+
+type RegisterPasskey struct {
+	Name     string
+	Response []byte
+}
+
+type LoginPasskey struct {
+	ChallengeID string
+	Response    []byte
+}
@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// the business details a pharmacy owner registers with, reviewed before approval
+type PharmacyApplication struct {
+	AuthID           int64
+	LegalName        string
+	LicenseNumber    string
+	LicenseAuthority string
+	LicenseExpiry    time.Time
+	ReviewedBy       *int64
+	ReviewedAt       *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+type CreatePharmacyApplication struct {
+	LegalName        string
+	LicenseNumber    string
+	LicenseAuthority string
+	LicenseExpiry    time.Time
+}
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+type RateLimit struct {
+	IsAllowed  bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+}
+
+type RateLimitPolicy struct {
+	IPLimit      int
+	AccountLimit int
+	Window       time.Duration
+}
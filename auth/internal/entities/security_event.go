@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+type SecurityEvent struct {
+	ID        int64
+	AuthID    *int64
+	EventType string
+	Details   map[string]any
+	UserAgent string
+	IPAddress string
+	RequestID string
+	TraceID   string
+	CreatedAt time.Time
+}
+
+type CreateSecurityEvent struct {
+	EventType string
+	Details   map[string]any
+	UserAgent string
+	IPAddress string
+	RequestID string
+	TraceID   string
+}
+
+// events are paged newest first, starting below the given event id
+type SecurityEventFilter struct {
+	AuthID    *int64
+	EventType string
+	IPAddress string
+	From      *time.Time
+	To        *time.Time
+	BeforeID  int64
+	Limit     int
+}
@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+type ServiceAccount struct {
+	ID                      int64
+	ClientID                string
+	Name                    string
+	SecretHash              string
+	Scopes                  []string
+	PreviousSecretHash      *string
+	PreviousSecretExpiresAt *time.Time
+	SecretRotatedAt         *time.Time
+	DisabledAt              *time.Time
+	CreatedBy               *int64
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+}
+
+type CreateServiceAccount struct {
+	Name   string
+	Scopes []string
+}
+
+type ClientCredentials struct {
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
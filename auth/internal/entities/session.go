@@ -3,15 +3,16 @@ package entities
 import "time"
 
 type Session struct {
-	ID        int64
-	AuthID    int64
-	ParentID  *int64
-	Token     string
-	UserAgent string
-	IPAddress string
-	CreatedAt time.Time
-	ExpiresAt time.Time
-	RevokedAt *time.Time
+	ID         int64
+	AuthID     int64
+	ParentID   *int64
+	Token      string
+	UserAgent  string
+	IPAddress  string
+	SignedInAt time.Time
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
 }
 
 type CreateSession struct {
@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// a sign-in from a device the account has not used recently
+type SignInAlert struct {
+	UserAgent          string
+	IPAddress          string
+	Location           string
+	IsImpossibleTravel bool
+	SignedInAt         time.Time
+}
@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+type SigningKey struct {
+	ID         int64
+	KID        string
+	Algorithm  string
+	PrivateKey string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+type CreateSigningKey struct {
+	KID        string
+	Algorithm  string
+	PrivateKey string
+	ExpiresAt  time.Time
+}
+
+type JWK struct {
+	KeyType   string
+	Curve     string
+	Algorithm string
+	Use       string
+	KeyID     string
+	Modulus   string
+	Exponent  string
+	X         string
+}
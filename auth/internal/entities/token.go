@@ -1,6 +1,14 @@
 package entities
 
+import "time"
+
 type AuthToken struct {
 	AccessToken  string
 	SessionToken string
 }
+
+type AccessToken struct {
+	Token     string
+	ID        string
+	ExpiresAt time.Time
+}
@@ -1,6 +1,9 @@
 package di
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/ritchieridanko/apotekly-api/auth/configs"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/app/caches"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/app/publishers"
@@ -11,51 +14,162 @@ import (
 	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/middlewares"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/router"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/services"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/breach"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/services/broker"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/services/cache"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/services/database"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/geoip"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/services/logger"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/services/oauth"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/workers"
 )
 
 type Container struct {
-	router *router.Router
+	router  *router.Router
+	workers []workers.Worker
 }
 
-func NewContainer(cfg *configs.Config, infra *infrastructure.Infrastructure) *Container {
+func NewContainer(cfg *configs.Config, infra *infrastructure.Infrastructure) (*Container, error) {
 	db := database.NewDatabase(infra.DB())
 	tx := database.NewTransactor(infra.DB())
 	cache := cache.NewCache(infra.Cache(), cfg.Cache.MaxRetries, cfg.Cache.BaseDelay)
 	bcrypt := services.NewBCryptService(cfg.Auth.BCrypt.Cost)
+	argon2id := services.NewArgon2IDService(&cfg.Auth)
+	var hasher *services.HasherService
+	switch cfg.Auth.Hasher.Algorithm {
+	case constants.HasherBCrypt:
+		hasher = services.NewHasherService(bcrypt, argon2id)
+	default:
+		hasher = services.NewHasherService(argon2id, bcrypt)
+	}
 	jwt := services.NewJWTService(&cfg.Auth)
 	cookie := services.NewCookieService(cfg.App.Env, true)
-	oauth := oauth.Initialize(&cfg.OAuth)
+	totp := services.NewTOTPService(cfg.Auth.MFA.Issuer)
+	cipher, err := services.NewCipherService(cfg.Auth.MFA.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mfa encryption key: %w", err)
+	}
+	keyCipher, err := services.NewCipherService(cfg.Auth.JWT.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwt encryption key: %w", err)
+	}
+	webauthn, err := services.NewWebAuthnService(&cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+	oauth, err := oauth.Initialize(&cfg.OAuth)
+	if err != nil {
+		return nil, err
+	}
 	logger := logger.NewLogger(infra.Logger())
 	producer := broker.NewProducer(infra.Broker().Producer())
 
+	var breachChecker *breach.Checker
+	switch cfg.Auth.PasswordPolicy.Breach.Source {
+	case constants.BreachSourceAPI:
+		source := breach.NewAPISource(cfg.Auth.PasswordPolicy.Breach.APIURL, cfg.Auth.PasswordPolicy.Breach.Timeout)
+		breachChecker = breach.NewChecker(source)
+	case constants.BreachSourceFile:
+		source, err := breach.NewFileSource(cfg.Auth.PasswordPolicy.Breach.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load breached passwords: %w", err)
+		}
+		breachChecker = breach.NewChecker(source)
+	case "":
+		// breached password check disabled
+	default:
+		return nil, fmt.Errorf("unsupported breach source %q", cfg.Auth.PasswordPolicy.Breach.Source)
+	}
+
+	var geo *geoip.Reader
+	if cfg.Auth.SignInAlert.GeoIPFile != "" {
+		geo, err = geoip.Open(cfg.Auth.SignInAlert.GeoIPFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load geoip database: %w", err)
+		}
+	}
+
 	ar := repositories.NewAuthRepository(db)
 	oar := repositories.NewOAuthRepository(db)
 	sr := repositories.NewSessionRepository(db)
+	mr := repositories.NewMFARepository(db)
+	pr := repositories.NewPasskeyRepository(db)
+	ser := repositories.NewSecurityEventRepository(db)
+	obr := repositories.NewOutboxRepository(db)
+	aar := repositories.NewAdminAuditRepository(db)
+	sar := repositories.NewServiceAccountRepository(db)
+	phr := repositories.NewPasswordHistoryRepository(db)
+	par := repositories.NewPharmacyApplicationRepository(db)
+
+	var skr repositories.SigningKeyRepository
+	switch cfg.Auth.JWT.KeyStore {
+	case constants.KeyStoreDatabase:
+		skr = repositories.NewSigningKeyRepository(db)
+	case constants.KeyStoreFile:
+		skr = repositories.NewFileSigningKeyRepository(cfg.Auth.JWT.KeyDir)
+	default:
+		return nil, fmt.Errorf("unsupported key store %q", cfg.Auth.JWT.KeyStore)
+	}
 
 	ac := caches.NewAuthCache(cache)
 	oac := caches.NewOAuthCache(cache)
+	mc := caches.NewMFACache(cache)
+	pc := caches.NewPasskeyCache(cache)
+	lc := caches.NewLockoutCache(cache)
+	rlc := caches.NewRateLimitCache(cache)
+	tdc := caches.NewTokenDenylistCache(cache, cfg.Auth.Denylist.LocalTTL, cfg.Auth.Denylist.LocalMaxEntries)
 
-	aep := publishers.NewAuthEventPublisher(producer, cfg.App.Name)
+	aep := publishers.NewAuthEventPublisher(obr, cfg.App.Name)
 
-	su := usecases.NewSessionUsecase(sr, tx)
-	au := usecases.NewAuthUsecase(ar, ac, su, aep, tx, bcrypt, jwt, cfg)
-	oau := usecases.NewOAuthUsecase(oar, ar, oac, ac, su, tx, jwt, cfg)
+	ku := usecases.NewKeyUsecase(skr, jwt, keyCipher, cfg)
+	if err := ku.LoadKeys(context.Background()); err != nil {
+		return nil, err
+	}
 
-	ah := handlers.NewAuthHandler(au, cookie, cfg)
-	oah := handlers.NewOAuthHandler(oau, au, oauth.Google(), oauth.Microsoft(), cookie, cfg)
+	obu := usecases.NewOutboxUsecase(obr, producer, tx, cfg)
+	seu := usecases.NewSecurityEventUsecase(ser, cfg)
+	su := usecases.NewSessionUsecase(sr, tdc, aep, tx, cfg)
+	sau := usecases.NewSignInAlertUsecase(ar, ac, su, seu, aep, tx, geo, cfg)
+	mu := usecases.NewMFAUsecase(mr, ar, mc, su, seu, sau, tx, totp, cipher, jwt, cfg)
+	pu := usecases.NewPasskeyUsecase(pr, ar, pc, su, seu, sau, tx, webauthn, jwt, cfg)
+	ppu := usecases.NewPasswordPolicyUsecase(phr, breachChecker, hasher, cfg)
+	au := usecases.NewAuthUsecase(ar, par, ac, lc, su, seu, sau, mu, ppu, aep, tx, hasher, jwt, cfg)
+	oau := usecases.NewOAuthUsecase(oar, ar, oac, ac, su, seu, sau, mu, aep, tx, jwt, cfg)
+	acu := usecases.NewAccountUsecase(ar, par, oar, mr, pr, phr, ser, au, su, aep, tx, cfg)
+	adu := usecases.NewAdminUsecase(ar, par, aar, sar, ac, tdc, su, seu, aep, tx, jwt, cfg)
+	sacu := usecases.NewServiceAccountUsecase(sar, tdc, seu, jwt, cfg)
 
-	am := middlewares.NewAuthMiddleware(jwt, cfg.App.Name)
+	ah := handlers.NewAuthHandler(au, sau, cookie, cfg)
+	mh := handlers.NewMFAHandler(mu, cookie, cfg)
+	ph := handlers.NewPasskeyHandler(pu, cookie, cfg)
+	sh := handlers.NewSessionHandler(su)
+	seh := handlers.NewSecurityEventHandler(seu)
+	jh := handlers.NewJWKSHandler(jwt)
+	oah := handlers.NewOAuthHandler(oau, au, oauth, cookie, cfg)
+	ach := handlers.NewAccountHandler(acu)
+	adh := handlers.NewAdminHandler(adu)
+	sah := handlers.NewServiceAccountHandler(sacu)
 
-	r := router.NewRouter(logger, am, ah, oah, cfg)
+	am := middlewares.NewAuthMiddleware(jwt, tdc, cfg.App.Name)
+	rlm := middlewares.NewRateLimitMiddleware(rlc, cfg.RateLimit.Enabled)
 
-	return &Container{router: r}
+	r := router.NewRouter(logger, am, rlm, ah, mh, ph, sh, seh, oah, ach, adh, sah, jh, cfg)
+
+	ws := []workers.Worker{
+		workers.NewKeyWorker(ku, cfg.Auth.JWT.Refresh),
+		workers.NewOutboxWorker(obu, cfg.Broker.Outbox.Interval),
+		workers.NewDeletionWorker(acu, cfg.Auth.Deletion.Interval),
+		workers.NewServiceAccountWorker(sacu, cfg.Auth.ServiceAccount.Interval),
+	}
+
+	return &Container{router: r, workers: ws}, nil
 }
 
 func (c *Container) Router() *router.Router {
 	return c.router
 }
+
+func (c *Container) Workers() []workers.Worker {
+	return c.workers
+}
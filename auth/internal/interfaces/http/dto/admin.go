@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+type SearchAccountsRequest struct {
+	Email      string `form:"email"`
+	Role       *int16 `form:"role"`
+	IsVerified *bool  `form:"is_verified"`
+	IsApproved *bool  `form:"is_approved"`
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1"`
+}
+
+type SuspendAccountRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type ImpersonationResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type AccountResponse struct {
+	ID                    int64                        `json:"id"`
+	Email                 string                       `json:"email"`
+	Role                  int16                        `json:"role"`
+	IsVerified            bool                         `json:"is_verified"`
+	IsApproved            bool                         `json:"is_approved"`
+	HasPassword           bool                         `json:"has_password"`
+	SuspendedAt           *time.Time                   `json:"suspended_at"`
+	SuspensionReason      *string                      `json:"suspension_reason"`
+	PasswordResetRequired bool                         `json:"password_reset_required"`
+	DeletionScheduledAt   *time.Time                   `json:"deletion_scheduled_at"`
+	Application           *PharmacyApplicationResponse `json:"pharmacy_application,omitempty"`
+	CreatedAt             time.Time                    `json:"created_at"`
+	UpdatedAt             time.Time                    `json:"updated_at"`
+}
+
+type PharmacyApplicationResponse struct {
+	LegalName        string     `json:"legal_name"`
+	LicenseNumber    string     `json:"license_number"`
+	LicenseAuthority string     `json:"license_authority"`
+	LicenseExpiry    time.Time  `json:"license_expiry"`
+	ReviewedBy       *int64     `json:"reviewed_by"`
+	ReviewedAt       *time.Time `json:"reviewed_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type AccountListResponse struct {
+	Accounts []AccountResponse `json:"accounts"`
+	Page     int               `json:"page"`
+	Limit    int               `json:"limit"`
+	Total    int64             `json:"total"`
+}
@@ -7,6 +7,15 @@ type RegisterRequest struct {
 	Password string `json:"password" binding:"required,password"`
 }
 
+type RegisterPharmacyRequest struct {
+	Email            string    `json:"email" binding:"required,email"`
+	Password         string    `json:"password" binding:"required,password"`
+	LegalName        string    `json:"legal_name" binding:"required,max=255"`
+	LicenseNumber    string    `json:"license_number" binding:"required,max=100"`
+	LicenseAuthority string    `json:"license_authority" binding:"required,max=255"`
+	LicenseExpiry    time.Time `json:"license_expiry" binding:"required"`
+}
+
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
@@ -16,11 +25,16 @@ type VerifyAccountRequest struct {
 	Token string `form:"token" binding:"required"`
 }
 
+type VerifyAccountCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
 type AuthResponse struct {
 	ID         int64     `json:"id"`
 	Email      string    `json:"email"`
 	RoleID     int16     `json:"role_id"`
 	IsVerified bool      `json:"is_verified"`
+	IsApproved bool      `json:"is_approved"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
@@ -43,3 +57,20 @@ type VerifyAccountResponse struct {
 type RefreshSessionResponse struct {
 	Token string `json:"token"`
 }
+
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ConfirmMagicLinkRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type LoginCodeRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ConfirmLoginCodeRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Code  string `json:"code" binding:"required,len=6,numeric"`
+}
@@ -8,6 +8,10 @@ type ConfirmEmailChangeRequest struct {
 	Token string `form:"token" binding:"required"`
 }
 
+type ConfirmEmailChangeCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
 type QueryEmailRequest struct {
 	Email string `form:"email" binding:"required,email"`
 }
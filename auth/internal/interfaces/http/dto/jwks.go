@@ -0,0 +1,16 @@
+package dto
+
+type JWKSResponse struct {
+	Keys []JWKResponse `json:"keys"`
+}
+
+type JWKResponse struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv,omitempty"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n,omitempty"`
+	Exponent  string `json:"e,omitempty"`
+	X         string `json:"x,omitempty"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type RequestUnlockRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type UnlockAccountRequest struct {
+	Token string `form:"token" binding:"required"`
+}
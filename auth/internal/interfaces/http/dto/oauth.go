@@ -1,7 +1,14 @@
 package dto
 
+import "time"
+
+type OAuthRequest struct {
+	RedirectTo string `form:"redirect_to"`
+}
+
 type AuthenticateRequest struct {
-	Code string `form:"code" binding:"required"`
+	Code  string `form:"code" binding:"required"`
+	State string `form:"state" binding:"required"`
 }
 
 type ExchangeCodeRequest struct {
@@ -13,14 +20,12 @@ type ExchangeCodeResponse struct {
 	Auth  AuthResponse `json:"auth"`
 }
 
-type GoogleUser struct {
-	ID         string `json:"id" binding:"required"`
-	Email      string `json:"email" binding:"required"`
-	IsVerified bool   `json:"verified_email" binding:"required"`
+type LinkIdentityResponse struct {
+	URL string `json:"url"`
 }
 
-type MicrosoftUser struct {
-	ID                string `json:"id"`
-	UserPrincipalName string `json:"userPrincipalName"`
-	Mail              string `json:"mail"`
+type OAuthIdentityResponse struct {
+	Provider  string    `json:"provider"`
+	UID       string    `json:"uid"`
+	CreatedAt time.Time `json:"created_at"`
 }
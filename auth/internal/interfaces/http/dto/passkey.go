@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+type RegisterPasskeyRequest struct {
+	Name       string          `json:"name" binding:"required,max=64"`
+	Credential json.RawMessage `json:"credential" binding:"required"`
+}
+
+type LoginPasskeyRequest struct {
+	ChallengeID string          `json:"challenge_id" binding:"required"`
+	Credential  json.RawMessage `json:"credential" binding:"required"`
+}
+
+type RenamePasskeyRequest struct {
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+type BeginPasskeyRegistrationResponse struct {
+	Options *protocol.CredentialCreation `json:"options"`
+}
+
+type BeginPasskeyLoginResponse struct {
+	ChallengeID string                        `json:"challenge_id"`
+	Options     *protocol.CredentialAssertion `json:"options"`
+}
+
+type PasskeyResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type LoginPasskeyResponse struct {
+	Token string       `json:"token"`
+	Auth  AuthResponse `json:"auth"`
+}
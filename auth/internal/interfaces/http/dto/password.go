@@ -5,6 +5,10 @@ type ChangePasswordRequest struct {
 	NewPassword string `json:"new_password" binding:"required,password"`
 }
 
+type SetPasswordRequest struct {
+	Password string `json:"password" binding:"required,password"`
+}
+
 type ForgotPasswordRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type GetSecurityEventsRequest struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1"`
+}
+
+type SearchSecurityEventsRequest struct {
+	AuthID    *int64     `form:"auth_id"`
+	EventType string     `form:"event_type"`
+	IPAddress string     `form:"ip_address"`
+	From      *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Cursor    string     `form:"cursor"`
+	Limit     int        `form:"limit" binding:"omitempty,min=1"`
+}
+
+type SecurityEventResponse struct {
+	ID        int64          `json:"id"`
+	AuthID    *int64         `json:"auth_id"`
+	EventType string         `json:"event_type"`
+	Details   map[string]any `json:"details"`
+	UserAgent string         `json:"user_agent"`
+	IPAddress string         `json:"ip_address"`
+	RequestID string         `json:"request_id"`
+	TraceID   string         `json:"trace_id"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type SecurityEventListResponse struct {
+	Events     []SecurityEventResponse `json:"events"`
+	NextCursor *string                 `json:"next_cursor"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+type CreateServiceAccountRequest struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+}
+
+type ServiceAccountResponse struct {
+	ID                      int64      `json:"id"`
+	ClientID                string     `json:"client_id"`
+	Name                    string     `json:"name"`
+	Scopes                  []string   `json:"scopes"`
+	SecretRotatedAt         *time.Time `json:"secret_rotated_at"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at"`
+	DisabledAt              *time.Time `json:"disabled_at"`
+	CreatedBy               *int64     `json:"created_by"`
+	CreatedAt               time.Time  `json:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at"`
+}
+
+type ServiceAccountSecretResponse struct {
+	ServiceAccount ServiceAccountResponse `json:"service_account"`
+	ClientSecret   string                 `json:"client_secret"`
+}
+
+type ClientTokenRequest struct {
+	GrantType    string `form:"grant_type" binding:"required"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	Scope        string `form:"scope"`
+}
+
+type ClientTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
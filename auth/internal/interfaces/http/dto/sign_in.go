@@ -0,0 +1,5 @@
+package dto
+
+type ReportSignInRequest struct {
+	Token string `form:"token" binding:"required"`
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/usecases"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/dto"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+)
+
+const accountErrorTracer string = "handler.account"
+
+type AccountHandler struct {
+	au usecases.AccountUsecase
+}
+
+func NewAccountHandler(au usecases.AccountUsecase) *AccountHandler {
+	return &AccountHandler{au}
+}
+
+func (h *AccountHandler) Delete(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(accountErrorTracer).Start(ctx.Request.Context(), "Delete")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to delete account: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	var payload dto.DeleteAccountRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to delete account: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	data := entities.ConfirmIdentity{
+		Password: payload.Password,
+		Code:     payload.Code,
+	}
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	deletion, err := h.au.RequestDeletion(ctxWithTracer, authID, &data, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	msg := fmt.Sprintf("Account scheduled for deletion on %s", deletion.ScheduledAt.Format("2006-01-02"))
+	utils.SetResponse(ctx, msg, h.toDeletionResponse(*deletion), http.StatusAccepted)
+}
+
+func (h *AccountHandler) GetDeletion(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(accountErrorTracer).Start(ctx.Request.Context(), "GetDeletion")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch account deletion: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	deletion, err := h.au.GetDeletion(ctxWithTracer, authID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "ok", h.toDeletionResponse(*deletion), http.StatusOK)
+}
+
+func (h *AccountHandler) CancelDeletion(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(accountErrorTracer).Start(ctx.Request.Context(), "CancelDeletion")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to cancel account deletion: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	if err := h.au.CancelDeletion(ctxWithTracer, authID); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "Account deletion cancelled successfully", nil, http.StatusOK)
+}
+
+func (h *AccountHandler) toDeletionResponse(deletion entities.AccountDeletion) dto.AccountDeletionResponse {
+	return dto.AccountDeletionResponse{
+		RequestedAt: deletion.RequestedAt,
+		ScheduledAt: deletion.ScheduledAt,
+	}
+}
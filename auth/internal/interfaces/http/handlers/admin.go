@@ -0,0 +1,552 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/usecases"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/dto"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const adminErrorTracer string = "handler.admin"
+
+type AdminHandler struct {
+	adu usecases.AdminUsecase
+}
+
+func NewAdminHandler(adu usecases.AdminUsecase) *AdminHandler {
+	return &AdminHandler{adu}
+}
+
+func (h *AdminHandler) SearchAccounts(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "SearchAccounts")
+	defer span.End()
+
+	adminID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to search accounts: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	var params dto.SearchAccountsRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		wErr := fmt.Errorf("failed to search accounts: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
+		return
+	}
+
+	filter := entities.AccountFilter{
+		Email:      utils.Normalize(params.Email),
+		RoleID:     params.Role,
+		IsVerified: params.IsVerified,
+		IsApproved: params.IsApproved,
+		Page:       params.Page,
+		Limit:      params.Limit,
+	}
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	accounts, total, err := h.adu.SearchAccounts(ctxWithTracer, adminID, &filter, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := dto.AccountListResponse{
+		Accounts: make([]dto.AccountResponse, 0, len(accounts)),
+		Page:     filter.Page,
+		Limit:    filter.Limit,
+		Total:    total,
+	}
+	for _, account := range accounts {
+		response.Accounts = append(response.Accounts, h.toAccountResponse(account))
+	}
+
+	utils.SetResponse(ctx, "ok", response, http.StatusOK)
+}
+
+func (h *AdminHandler) SearchSecurityEvents(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "SearchSecurityEvents")
+	defer span.End()
+
+	adminID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to search security events: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	var params dto.SearchSecurityEventsRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		wErr := fmt.Errorf("failed to search security events: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
+		return
+	}
+
+	filter := entities.SecurityEventFilter{
+		AuthID:    params.AuthID,
+		EventType: params.EventType,
+		IPAddress: params.IPAddress,
+		From:      params.From,
+		To:        params.To,
+		Limit:     params.Limit,
+	}
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	events, nextCursor, err := h.adu.SearchSecurityEvents(ctxWithTracer, adminID, &filter, params.Cursor, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "ok", toSecurityEventListResponse(events, nextCursor), http.StatusOK)
+}
+
+func (h *AdminHandler) GetAccount(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "GetAccount")
+	defer span.End()
+
+	adminID, targetID, ok := h.getIDs(ctxWithTracer, ctx, span, "failed to fetch account")
+	if !ok {
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	account, err := h.adu.GetAccount(ctxWithTracer, adminID, targetID, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "ok", h.toAccountResponse(*account), http.StatusOK)
+}
+
+func (h *AdminHandler) GetSessions(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "GetSessions")
+	defer span.End()
+
+	adminID, targetID, ok := h.getIDs(ctxWithTracer, ctx, span, "failed to fetch account sessions")
+	if !ok {
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	sessions, err := h.adu.GetSessions(ctxWithTracer, adminID, targetID, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, dto.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.SignedInAt,
+			LastUsedAt: session.CreatedAt,
+			ExpiresAt:  session.ExpiresAt,
+		})
+	}
+
+	utils.SetResponse(ctx, "ok", response, http.StatusOK)
+}
+
+func (h *AdminHandler) VerifyAccount(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "VerifyAccount")
+	defer span.End()
+
+	adminID, targetID, ok := h.getIDs(ctxWithTracer, ctx, span, "failed to verify account")
+	if !ok {
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	if err := h.adu.VerifyAccount(ctxWithTracer, adminID, targetID, &request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "", nil, http.StatusNoContent)
+}
+
+func (h *AdminHandler) ApproveAccount(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "ApproveAccount")
+	defer span.End()
+
+	adminID, targetID, ok := h.getIDs(ctxWithTracer, ctx, span, "failed to approve account")
+	if !ok {
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	if err := h.adu.ApproveAccount(ctxWithTracer, adminID, targetID, &request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "", nil, http.StatusNoContent)
+}
+
+func (h *AdminHandler) SuspendAccount(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "SuspendAccount")
+	defer span.End()
+
+	adminID, targetID, ok := h.getIDs(ctxWithTracer, ctx, span, "failed to suspend account")
+	if !ok {
+		return
+	}
+
+	var payload dto.SuspendAccountRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to suspend account: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	if err := h.adu.SuspendAccount(ctxWithTracer, adminID, targetID, payload.Reason, &request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "", nil, http.StatusNoContent)
+}
+
+func (h *AdminHandler) UnsuspendAccount(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "UnsuspendAccount")
+	defer span.End()
+
+	adminID, targetID, ok := h.getIDs(ctxWithTracer, ctx, span, "failed to unsuspend account")
+	if !ok {
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	if err := h.adu.UnsuspendAccount(ctxWithTracer, adminID, targetID, &request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "", nil, http.StatusNoContent)
+}
+
+func (h *AdminHandler) ForcePasswordReset(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "ForcePasswordReset")
+	defer span.End()
+
+	adminID, targetID, ok := h.getIDs(ctxWithTracer, ctx, span, "failed to force password reset")
+	if !ok {
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	if err := h.adu.ForcePasswordReset(ctxWithTracer, adminID, targetID, &request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "", nil, http.StatusNoContent)
+}
+
+func (h *AdminHandler) RevokeSessions(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "RevokeSessions")
+	defer span.End()
+
+	adminID, targetID, ok := h.getIDs(ctxWithTracer, ctx, span, "failed to revoke account sessions")
+	if !ok {
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	if err := h.adu.RevokeSessions(ctxWithTracer, adminID, targetID, &request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "", nil, http.StatusNoContent)
+}
+
+func (h *AdminHandler) Impersonate(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "Impersonate")
+	defer span.End()
+
+	adminID, targetID, ok := h.getIDs(ctxWithTracer, ctx, span, "failed to impersonate")
+	if !ok {
+		return
+	}
+
+	var payload dto.ImpersonateRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to impersonate: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	accessToken, err := h.adu.Impersonate(ctxWithTracer, adminID, targetID, payload.Reason, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := dto.ImpersonationResponse{
+		Token:     accessToken.Token,
+		ExpiresAt: accessToken.ExpiresAt,
+	}
+
+	utils.SetResponse(ctx, "ok", response, http.StatusCreated)
+}
+
+func (h *AdminHandler) GetServiceAccounts(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "GetServiceAccounts")
+	defer span.End()
+
+	adminID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch service accounts: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	serviceAccounts, err := h.adu.GetServiceAccounts(ctxWithTracer, adminID, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := make([]dto.ServiceAccountResponse, 0, len(serviceAccounts))
+	for _, serviceAccount := range serviceAccounts {
+		response = append(response, h.toServiceAccountResponse(serviceAccount))
+	}
+
+	utils.SetResponse(ctx, "ok", response, http.StatusOK)
+}
+
+func (h *AdminHandler) CreateServiceAccount(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "CreateServiceAccount")
+	defer span.End()
+
+	adminID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to create service account: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	var payload dto.CreateServiceAccountRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to create service account: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	data := entities.CreateServiceAccount{
+		Name:   strings.TrimSpace(payload.Name),
+		Scopes: payload.Scopes,
+	}
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	serviceAccount, secret, err := h.adu.CreateServiceAccount(ctxWithTracer, adminID, &data, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := dto.ServiceAccountSecretResponse{
+		ServiceAccount: h.toServiceAccountResponse(*serviceAccount),
+		ClientSecret:   secret,
+	}
+
+	utils.SetResponse(ctx, "ok", response, http.StatusCreated)
+}
+
+func (h *AdminHandler) RotateServiceAccountSecret(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "RotateServiceAccountSecret")
+	defer span.End()
+
+	adminID, serviceAccountID, ok := h.getServiceAccountIDs(ctxWithTracer, ctx, span, "failed to rotate service account secret")
+	if !ok {
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	serviceAccount, secret, err := h.adu.RotateServiceAccountSecret(ctxWithTracer, adminID, serviceAccountID, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := dto.ServiceAccountSecretResponse{
+		ServiceAccount: h.toServiceAccountResponse(*serviceAccount),
+		ClientSecret:   secret,
+	}
+
+	utils.SetResponse(ctx, "ok", response, http.StatusOK)
+}
+
+func (h *AdminHandler) DisableServiceAccount(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(adminErrorTracer).Start(ctx.Request.Context(), "DisableServiceAccount")
+	defer span.End()
+
+	adminID, serviceAccountID, ok := h.getServiceAccountIDs(ctxWithTracer, ctx, span, "failed to disable service account")
+	if !ok {
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	if err := h.adu.DisableServiceAccount(ctxWithTracer, adminID, serviceAccountID, &request); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "", nil, http.StatusNoContent)
+}
+
+func (h *AdminHandler) getIDs(ctx context.Context, gctx *gin.Context, span trace.Span, msg string) (int64, int64, bool) {
+	adminID, err := utils.CtxGetAuthID(ctx)
+	if err != nil {
+		wErr := fmt.Errorf("%s: %w", msg, err)
+		gctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return 0, 0, false
+	}
+
+	targetID, err := utils.ToInt64(gctx.Param("auth_id"))
+	if err != nil {
+		wErr := fmt.Errorf("%s: %w", msg, err)
+		gctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
+		return 0, 0, false
+	}
+
+	return adminID, targetID, true
+}
+
+func (h *AdminHandler) getServiceAccountIDs(ctx context.Context, gctx *gin.Context, span trace.Span, msg string) (int64, int64, bool) {
+	adminID, err := utils.CtxGetAuthID(ctx)
+	if err != nil {
+		wErr := fmt.Errorf("%s: %w", msg, err)
+		gctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return 0, 0, false
+	}
+
+	serviceAccountID, err := utils.ToInt64(gctx.Param("service_account_id"))
+	if err != nil {
+		wErr := fmt.Errorf("%s: %w", msg, err)
+		gctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
+		return 0, 0, false
+	}
+
+	return adminID, serviceAccountID, true
+}
+
+func (h *AdminHandler) toAccountResponse(account entities.Account) dto.AccountResponse {
+	var application *dto.PharmacyApplicationResponse
+	if account.Application != nil {
+		application = &dto.PharmacyApplicationResponse{
+			LegalName:        account.Application.LegalName,
+			LicenseNumber:    account.Application.LicenseNumber,
+			LicenseAuthority: account.Application.LicenseAuthority,
+			LicenseExpiry:    account.Application.LicenseExpiry,
+			ReviewedBy:       account.Application.ReviewedBy,
+			ReviewedAt:       account.Application.ReviewedAt,
+			CreatedAt:        account.Application.CreatedAt,
+		}
+	}
+
+	return dto.AccountResponse{
+		ID:                    account.ID,
+		Email:                 account.Email,
+		Role:                  account.RoleID,
+		IsVerified:            account.IsVerified,
+		IsApproved:            account.IsApproved,
+		HasPassword:           account.HasPassword,
+		SuspendedAt:           account.SuspendedAt,
+		SuspensionReason:      account.SuspensionReason,
+		PasswordResetRequired: account.PasswordResetRequired,
+		DeletionScheduledAt:   account.DeletionScheduledAt,
+		Application:           application,
+		CreatedAt:             account.CreatedAt,
+		UpdatedAt:             account.UpdatedAt,
+	}
+}
+
+func (h *AdminHandler) toServiceAccountResponse(serviceAccount entities.ServiceAccount) dto.ServiceAccountResponse {
+	return dto.ServiceAccountResponse{
+		ID:                      serviceAccount.ID,
+		ClientID:                serviceAccount.ClientID,
+		Name:                    serviceAccount.Name,
+		Scopes:                  serviceAccount.Scopes,
+		SecretRotatedAt:         serviceAccount.SecretRotatedAt,
+		PreviousSecretExpiresAt: serviceAccount.PreviousSecretExpiresAt,
+		DisabledAt:              serviceAccount.DisabledAt,
+		CreatedBy:               serviceAccount.CreatedBy,
+		CreatedAt:               serviceAccount.CreatedAt,
+		UpdatedAt:               serviceAccount.UpdatedAt,
+	}
+}
@@ -24,12 +24,18 @@ const authErrorTracer string = "handler.auth"
 
 type AuthHandler struct {
 	au     usecases.AuthUsecase
+	sau    usecases.SignInAlertUsecase
 	cookie *services.CookieService
 	cfg    *configs.Config
 }
 
-func NewAuthHandler(au usecases.AuthUsecase, cookie *services.CookieService, cfg *configs.Config) *AuthHandler {
-	return &AuthHandler{au, cookie, cfg}
+func NewAuthHandler(
+	au usecases.AuthUsecase,
+	sau usecases.SignInAlertUsecase,
+	cookie *services.CookieService,
+	cfg *configs.Config,
+) *AuthHandler {
+	return &AuthHandler{au, sau, cookie, cfg}
 }
 
 func (h *AuthHandler) Register(ctx *gin.Context) {
@@ -67,6 +73,52 @@ func (h *AuthHandler) Register(ctx *gin.Context) {
 	utils.SetResponse(ctx, "Registered successfully", response, http.StatusCreated)
 }
 
+func (h *AuthHandler) RegisterPharmacy(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "RegisterPharmacy")
+	defer span.End()
+
+	var payload dto.RegisterPharmacyRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to register pharmacy: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	data := entities.CreateAuth{
+		Email:    payload.Email,
+		Password: &payload.Password,
+		Application: &entities.CreatePharmacyApplication{
+			LegalName:        payload.LegalName,
+			LicenseNumber:    payload.LicenseNumber,
+			LicenseAuthority: payload.LicenseAuthority,
+			LicenseExpiry:    payload.LicenseExpiry,
+		},
+	}
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	authToken, auth, err := h.au.RegisterPharmacy(ctxWithTracer, &data, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := dto.RegisterResponse{
+		Token: authToken.AccessToken,
+		Auth:  h.toAuthResponse(*auth),
+	}
+
+	message := "Registered successfully"
+	if !auth.IsApproved {
+		message = "Registered successfully, the account is pending approval"
+	}
+
+	h.setCookie(ctx, authToken.SessionToken)
+	utils.SetResponse(ctx, message, response, http.StatusCreated)
+}
+
 func (h *AuthHandler) Login(ctx *gin.Context) {
 	ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "Login")
 	defer span.End()
@@ -87,11 +139,20 @@ func (h *AuthHandler) Login(ctx *gin.Context) {
 		IPAddress: ctx.ClientIP(),
 	}
 
-	authToken, auth, err := h.au.Login(ctxWithTracer, &data, &request)
+	authToken, auth, mfaToken, err := h.au.Login(ctxWithTracer, &data, &request)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
+	if mfaToken != "" {
+		response := dto.LoginMFAResponse{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		}
+
+		utils.SetResponse(ctx, "Two-factor authentication required", response, http.StatusOK)
+		return
+	}
 
 	response := dto.LoginResponse{
 		Token: authToken.AccessToken,
@@ -187,7 +248,65 @@ func (h *AuthHandler) ConfirmEmailChange(ctx *gin.Context) {
 		)
 	}
 
-	authToken, auth, err := h.au.ConfirmEmailChange(ctxWithTracer, token, sessionToken)
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	authToken, auth, err := h.au.ConfirmEmailChange(ctxWithTracer, token, sessionToken, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	msg := "Email changed successfully"
+	if authToken == nil {
+		utils.SetResponse(ctx, msg, nil, http.StatusOK)
+		return
+	}
+
+	response := dto.ConfirmEmailChangeResponse{
+		Token: authToken.AccessToken,
+		Auth:  h.toAuthResponse(*auth),
+	}
+
+	h.setCookie(ctx, authToken.SessionToken)
+	utils.SetResponse(ctx, msg, response, http.StatusOK)
+}
+
+func (h *AuthHandler) ConfirmEmailChangeCode(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "ConfirmEmailChangeCode")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to confirm email change code: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	var payload dto.ConfirmEmailChangeCodeRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to confirm email change code: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	sessionToken, err := ctx.Cookie(constants.CookieKeySessionToken)
+	if err != nil {
+		// non-fatal: trace the failure, but continue
+		span.AddEvent(
+			"session cookie not found",
+			trace.WithAttributes(attribute.String("error", err.Error())),
+		)
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	authToken, auth, err := h.au.ConfirmEmailChangeCode(ctxWithTracer, authID, payload.Code, sessionToken, &request)
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -230,7 +349,14 @@ func (h *AuthHandler) ChangePassword(ctx *gin.Context) {
 		OldPassword: payload.OldPassword,
 		NewPassword: payload.NewPassword,
 	}
-	if err := h.au.ChangePassword(ctxWithTracer, authID, &data); err != nil {
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+	// the session making the change stays signed in, every other one is revoked
+	sessionToken, _ := ctx.Cookie(constants.CookieKeySessionToken)
+
+	if err := h.au.ChangePassword(ctxWithTracer, authID, sessionToken, &data, &request); err != nil {
 		ctx.Error(err)
 		return
 	}
@@ -238,6 +364,32 @@ func (h *AuthHandler) ChangePassword(ctx *gin.Context) {
 	utils.SetResponse(ctx, "Password changed successfully", nil, http.StatusOK)
 }
 
+func (h *AuthHandler) SetPassword(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "SetPassword")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to set password: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	var payload dto.SetPasswordRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to set password: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	if err := h.au.SetPassword(ctxWithTracer, authID, payload.Password); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "Password set successfully", nil, http.StatusOK)
+}
+
 func (h *AuthHandler) ForgotPassword(ctx *gin.Context) {
 	ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "ForgotPassword")
 	defer span.End()
@@ -281,7 +433,11 @@ func (h *AuthHandler) ResetPassword(ctx *gin.Context) {
 		Token:       token,
 		NewPassword: payload.NewPassword,
 	}
-	if err := h.au.ResetPassword(ctxWithTracer, &data); err != nil {
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+	if err := h.au.ResetPassword(ctxWithTracer, &data, &request); err != nil {
 		ctx.Error(err)
 		return
 	}
@@ -289,6 +445,79 @@ func (h *AuthHandler) ResetPassword(ctx *gin.Context) {
 	utils.SetResponse(ctx, "Password changed successfully", nil, http.StatusOK)
 }
 
+func (h *AuthHandler) RequestUnlock(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "RequestUnlock")
+	defer span.End()
+
+	var payload dto.RequestUnlockRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to request unlock: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	email, err := h.au.RequestUnlock(ctxWithTracer, payload.Email)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	msg := fmt.Sprintf("Link to unlock account sent to %s", email)
+	utils.SetResponse(ctx, msg, nil, http.StatusOK)
+}
+
+func (h *AuthHandler) UnlockAccount(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "UnlockAccount")
+	defer span.End()
+
+	var params dto.UnlockAccountRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		wErr := fmt.Errorf("failed to unlock account: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
+		return
+	}
+
+	token := strings.TrimSpace(params.Token)
+	if token == "" {
+		err := fmt.Errorf("failed to unlock account: %w", ce.ErrTokenNotFound)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, err))
+		return
+	}
+
+	if err := h.au.UnlockAccount(ctxWithTracer, token); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "Account unlocked successfully", nil, http.StatusOK)
+}
+
+func (h *AuthHandler) ReportSignIn(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "ReportSignIn")
+	defer span.End()
+
+	var params dto.ReportSignInRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		wErr := fmt.Errorf("failed to report sign-in: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
+		return
+	}
+
+	token := strings.TrimSpace(params.Token)
+	if token == "" {
+		err := fmt.Errorf("failed to report sign-in: %w", ce.ErrTokenNotFound)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, err))
+		return
+	}
+
+	if err := h.sau.ReportSignIn(ctxWithTracer, token); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "Sign-in reported successfully", nil, http.StatusOK)
+}
+
 func (h *AuthHandler) ResendVerification(ctx *gin.Context) {
 	ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "ResendVerification")
 	defer span.End()
@@ -337,7 +566,65 @@ func (h *AuthHandler) VerifyAccount(ctx *gin.Context) {
 		)
 	}
 
-	authToken, auth, err := h.au.VerifyAccount(ctxWithTracer, token, sessionToken)
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	authToken, auth, err := h.au.VerifyAccount(ctxWithTracer, token, sessionToken, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	msg := "Account verified successfully"
+	if authToken == nil {
+		utils.SetResponse(ctx, msg, nil, http.StatusOK)
+		return
+	}
+
+	response := dto.VerifyAccountResponse{
+		Token: authToken.AccessToken,
+		Auth:  h.toAuthResponse(*auth),
+	}
+
+	h.setCookie(ctx, authToken.SessionToken)
+	utils.SetResponse(ctx, msg, response, http.StatusOK)
+}
+
+func (h *AuthHandler) VerifyAccountCode(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "VerifyAccountCode")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to verify account code: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	var payload dto.VerifyAccountCodeRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to verify account code: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	sessionToken, err := ctx.Cookie(constants.CookieKeySessionToken)
+	if err != nil {
+		// non-fatal: trace the failure, but continue
+		span.AddEvent(
+			"session cookie not found",
+			trace.WithAttributes(attribute.String("error", err.Error())),
+		)
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	authToken, auth, err := h.au.VerifyAccountCode(ctxWithTracer, authID, payload.Code, sessionToken, &request)
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -381,7 +668,12 @@ func (h *AuthHandler) RefreshSession(ctx *gin.Context) {
 		return
 	}
 
-	authToken, err := h.au.RefreshSession(ctxWithTracer, sessionToken)
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	authToken, err := h.au.RefreshSession(ctxWithTracer, sessionToken, &request)
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -450,12 +742,159 @@ func (h *AuthHandler) IsResetTokenValid(ctx *gin.Context) {
 	utils.SetResponse(ctx, "ok", response, http.StatusOK)
 }
 
+func (h *AuthHandler) RequestMagicLink(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "RequestMagicLink")
+	defer span.End()
+
+	var payload dto.MagicLinkRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to request magic link: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	// binds the link to this browser, only a confirmation presenting the same cookie is accepted
+	deviceID := utils.NewRandomToken()
+	email, err := h.au.RequestMagicLink(ctxWithTracer, payload.Email, deviceID, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	h.cookie.Set(ctx, constants.CookieKeyMagicLinkDevice, deviceID, h.cfg.Auth.TokenDuration.MagicLink, "/", h.cfg.Server.Host)
+
+	msg := fmt.Sprintf("Link to sign in sent to %s", email)
+	utils.SetResponse(ctx, msg, nil, http.StatusOK)
+}
+
+func (h *AuthHandler) ConfirmMagicLink(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "ConfirmMagicLink")
+	defer span.End()
+
+	var payload dto.ConfirmMagicLinkRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to confirm magic link: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	deviceID, err := ctx.Cookie(constants.CookieKeyMagicLinkDevice)
+	if err != nil || deviceID == "" {
+		wErr := fmt.Errorf("failed to confirm magic link: %w", ce.ErrCookieNotFound)
+		ctx.Error(ce.NewError(span, ce.CodeMagicLinkDeviceMismatch, ce.MsgMagicLinkDeviceMismatch, wErr))
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	authToken, auth, mfaToken, err := h.au.ConfirmMagicLink(ctxWithTracer, payload.Token, deviceID, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	h.cookie.Delete(ctx, constants.CookieKeyMagicLinkDevice, "/", h.cfg.Server.Host)
+
+	if mfaToken != "" {
+		response := dto.LoginMFAResponse{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		}
+
+		utils.SetResponse(ctx, "Two-factor authentication required", response, http.StatusOK)
+		return
+	}
+
+	response := dto.LoginResponse{
+		Token: authToken.AccessToken,
+		Auth:  h.toAuthResponse(*auth),
+	}
+
+	h.setCookie(ctx, authToken.SessionToken)
+	utils.SetResponse(ctx, "Logged in successfully", response, http.StatusOK)
+}
+
+func (h *AuthHandler) RequestLoginCode(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "RequestLoginCode")
+	defer span.End()
+
+	var payload dto.LoginCodeRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to request login code: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	email, err := h.au.RequestLoginCode(ctxWithTracer, payload.Email, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	msg := fmt.Sprintf("Code to sign in sent to %s", email)
+	utils.SetResponse(ctx, msg, nil, http.StatusOK)
+}
+
+func (h *AuthHandler) ConfirmLoginCode(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "ConfirmLoginCode")
+	defer span.End()
+
+	var payload dto.ConfirmLoginCodeRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to confirm login code: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	authToken, auth, mfaToken, err := h.au.ConfirmLoginCode(ctxWithTracer, payload.Email, payload.Code, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if mfaToken != "" {
+		response := dto.LoginMFAResponse{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		}
+
+		utils.SetResponse(ctx, "Two-factor authentication required", response, http.StatusOK)
+		return
+	}
+
+	response := dto.LoginResponse{
+		Token: authToken.AccessToken,
+		Auth:  h.toAuthResponse(*auth),
+	}
+
+	h.setCookie(ctx, authToken.SessionToken)
+	utils.SetResponse(ctx, "Logged in successfully", response, http.StatusOK)
+}
+
 func (h *AuthHandler) toAuthResponse(auth entities.Auth) dto.AuthResponse {
 	return dto.AuthResponse{
 		ID:         auth.ID,
 		Email:      auth.Email,
 		RoleID:     auth.RoleID,
 		IsVerified: auth.IsVerified,
+		IsApproved: auth.IsApproved,
 		CreatedAt:  auth.CreatedAt,
 		UpdatedAt:  auth.UpdatedAt,
 	}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/dto"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services"
+	"go.opentelemetry.io/otel"
+)
+
+const jwksErrorTracer string = "handler.jwks"
+
+type JWKSHandler struct {
+	jwt *services.JWTService
+}
+
+func NewJWKSHandler(jwt *services.JWTService) *JWKSHandler {
+	return &JWKSHandler{jwt}
+}
+
+func (h *JWKSHandler) GetJWKS(ctx *gin.Context) {
+	_, span := otel.Tracer(jwksErrorTracer).Start(ctx.Request.Context(), "GetJWKS")
+	defer span.End()
+
+	keys := h.jwt.JWKS()
+
+	response := dto.JWKSResponse{
+		Keys: make([]dto.JWKResponse, 0, len(keys)),
+	}
+	for _, key := range keys {
+		response.Keys = append(response.Keys, h.toJWKResponse(key))
+	}
+
+	// served as a bare key set, as verifiers expect
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (h *JWKSHandler) toJWKResponse(key entities.JWK) dto.JWKResponse {
+	return dto.JWKResponse{
+		KeyType:   key.KeyType,
+		Curve:     key.Curve,
+		Algorithm: key.Algorithm,
+		Use:       key.Use,
+		KeyID:     key.KeyID,
+		Modulus:   key.Modulus,
+		Exponent:  key.Exponent,
+		X:         key.X,
+	}
+}
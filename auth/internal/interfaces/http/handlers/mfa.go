@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/usecases"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/dto"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+)
+
+const mfaErrorTracer string = "handler.mfa"
+
+type MFAHandler struct {
+	mu     usecases.MFAUsecase
+	cookie *services.CookieService
+	cfg    *configs.Config
+}
+
+func NewMFAHandler(mu usecases.MFAUsecase, cookie *services.CookieService, cfg *configs.Config) *MFAHandler {
+	return &MFAHandler{mu, cookie, cfg}
+}
+
+func (h *MFAHandler) Enroll(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(mfaErrorTracer).Start(ctx.Request.Context(), "Enroll")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to enroll mfa: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	enrollment, err := h.mu.Enroll(ctxWithTracer, authID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := dto.EnrollMFAResponse{
+		Secret: enrollment.Secret,
+		URI:    enrollment.URI,
+	}
+
+	utils.SetResponse(ctx, "Two-factor authentication enrollment started", response, http.StatusOK)
+}
+
+func (h *MFAHandler) ConfirmEnrollment(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(mfaErrorTracer).Start(ctx.Request.Context(), "ConfirmEnrollment")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to confirm mfa enrollment: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	var payload dto.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to confirm mfa enrollment: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	recoveryCodes, err := h.mu.ConfirmEnrollment(ctxWithTracer, authID, strings.TrimSpace(payload.Code))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := dto.RecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}
+
+	utils.SetResponse(ctx, "Two-factor authentication enabled successfully", response, http.StatusOK)
+}
+
+func (h *MFAHandler) Disable(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(mfaErrorTracer).Start(ctx.Request.Context(), "Disable")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to disable mfa: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	var payload dto.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to disable mfa: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	if err := h.mu.Disable(ctxWithTracer, authID, strings.TrimSpace(payload.Code)); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "Two-factor authentication disabled successfully", nil, http.StatusOK)
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(mfaErrorTracer).Start(ctx.Request.Context(), "RegenerateRecoveryCodes")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to regenerate recovery codes: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	var payload dto.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to regenerate recovery codes: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	recoveryCodes, err := h.mu.RegenerateRecoveryCodes(ctxWithTracer, authID, strings.TrimSpace(payload.Code))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := dto.RecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}
+
+	utils.SetResponse(ctx, "Recovery codes regenerated successfully", response, http.StatusOK)
+}
+
+func (h *MFAHandler) VerifyLogin(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(mfaErrorTracer).Start(ctx.Request.Context(), "VerifyLogin")
+	defer span.End()
+
+	var payload dto.VerifyMFARequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to verify mfa login: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	token := strings.TrimSpace(payload.MFAToken)
+	if token == "" {
+		err := fmt.Errorf("failed to verify mfa login: %w", ce.ErrTokenNotFound)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, err))
+		return
+	}
+
+	data := entities.VerifyMFA{
+		Token: token,
+		Code:  strings.TrimSpace(payload.Code),
+	}
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	authToken, auth, err := h.mu.VerifyLogin(ctxWithTracer, &data, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := dto.VerifyMFAResponse{
+		Token: authToken.AccessToken,
+		Auth:  h.toAuthResponse(*auth),
+	}
+
+	h.setCookie(ctx, authToken.SessionToken)
+	utils.SetResponse(ctx, "Logged in successfully", response, http.StatusOK)
+}
+
+func (h *MFAHandler) toAuthResponse(auth entities.Auth) dto.AuthResponse {
+	return dto.AuthResponse{
+		ID:         auth.ID,
+		Email:      auth.Email,
+		RoleID:     auth.RoleID,
+		IsVerified: auth.IsVerified,
+		CreatedAt:  auth.CreatedAt,
+		UpdatedAt:  auth.UpdatedAt,
+	}
+}
+
+func (h *MFAHandler) setCookie(ctx *gin.Context, sessionToken string) {
+	h.cookie.Set(ctx, constants.CookieKeySessionToken, sessionToken, h.cfg.Auth.TokenDuration.Session, "/", h.cfg.Server.Host)
+}
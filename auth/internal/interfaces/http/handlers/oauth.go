@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -13,6 +15,7 @@ import (
 	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/dto"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/services"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services/oauth"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
@@ -23,130 +26,80 @@ import (
 const oAuthErrorTracer string = "handler.oauth"
 
 type OAuthHandler struct {
-	oau       usecases.OAuthUsecase
-	au        usecases.AuthUsecase
-	google    *oauth2.Config
-	microsoft *oauth2.Config
-	cookie    *services.CookieService
-	cfg       *configs.Config
+	oau    usecases.OAuthUsecase
+	au     usecases.AuthUsecase
+	oauth  *oauth.OAuth
+	cookie *services.CookieService
+	cfg    *configs.Config
 }
 
 func NewOAuthHandler(
 	oau usecases.OAuthUsecase,
 	au usecases.AuthUsecase,
-	google, microsoft *oauth2.Config,
+	oauth *oauth.OAuth,
 	cookie *services.CookieService,
 	cfg *configs.Config,
 ) *OAuthHandler {
-	return &OAuthHandler{oau, au, google, microsoft, cookie, cfg}
+	return &OAuthHandler{oau, au, oauth, cookie, cfg}
 }
 
-func (h *OAuthHandler) GoogleOAuth(ctx *gin.Context) {
-	url := h.google.AuthCodeURL("random-state-string", oauth2.AccessTypeOffline)
-	ctx.Redirect(http.StatusTemporaryRedirect, url)
-}
-
-func (h *OAuthHandler) GoogleCallback(ctx *gin.Context) {
-	ctxWithTracer, span := otel.Tracer(oAuthErrorTracer).Start(ctx.Request.Context(), "GoogleCallback")
+func (h *OAuthHandler) OAuth(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(oAuthErrorTracer).Start(ctx.Request.Context(), "OAuth")
 	defer span.End()
 
-	var params dto.AuthenticateRequest
-	if err := ctx.ShouldBindQuery(&params); err != nil {
-		wErr := fmt.Errorf("failed to handle google callback: %w", err)
-		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
-		return
-	}
-
-	token, err := h.google.Exchange(ctxWithTracer, params.Code)
-	if err != nil {
-		wErr := fmt.Errorf("failed to handle google callback: %w", err)
-		ctx.Error(ce.NewError(span, ce.CodeOAuthCodeExchangeFailed, ce.MsgInternalServer, wErr))
+	provider, ok := h.oauth.Provider(ctx.Param("provider"))
+	if !ok {
+		err := fmt.Errorf("failed to handle oauth: %w", fmt.Errorf("unknown provider '%s'", ctx.Param("provider")))
+		ctx.Error(ce.NewError(span, ce.CodeOAuthProviderNotFound, "Provider not found", err))
 		return
 	}
 
-	user, err := h.googleGetUserInfo(ctxWithTracer, token, h.google)
-	if err != nil {
-		ctx.Error(err)
+	var params dto.OAuthRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		wErr := fmt.Errorf("failed to handle oauth: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
 		return
 	}
 
-	data := entities.OAuth{
-		Provider:   constants.OAuthProviderGoogle,
-		UID:        user.ID,
-		Email:      user.Email,
-		IsVerified: user.IsVerified,
-	}
-	request := entities.Request{
-		UserAgent: ctx.Request.UserAgent(),
-		IPAddress: ctx.ClientIP(),
-	}
-
-	sessionToken, exchangeCode, err := h.oau.Authenticate(ctxWithTracer, &data, &request)
+	url, err := h.authCodeURL(ctxWithTracer, ctx, provider, 0, params.RedirectTo)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
-	h.setCookie(ctx, sessionToken)
-
-	url := h.setRedirectURL(exchangeCode)
-	ctx.Redirect(http.StatusTemporaryRedirect, url)
-}
-
-func (h *OAuthHandler) MicrosoftOAuth(ctx *gin.Context) {
-	url := h.microsoft.AuthCodeURL("random-state-string", oauth2.AccessTypeOffline)
 	ctx.Redirect(http.StatusTemporaryRedirect, url)
 }
 
-func (h *OAuthHandler) MicrosoftCallback(ctx *gin.Context) {
-	ctxWithTracer, span := otel.Tracer(oAuthErrorTracer).Start(ctx.Request.Context(), "MicrosoftCallback")
+func (h *OAuthHandler) Callback(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(oAuthErrorTracer).Start(ctx.Request.Context(), "Callback")
 	defer span.End()
 
-	var params dto.AuthenticateRequest
-	if err := ctx.ShouldBindQuery(&params); err != nil {
-		wErr := fmt.Errorf("failed to handle microsoft callback: %w", err)
-		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
+	provider, ok := h.oauth.Provider(ctx.Param("provider"))
+	if !ok {
+		err := fmt.Errorf("failed to handle oauth callback: %w", fmt.Errorf("unknown provider '%s'", ctx.Param("provider")))
+		ctx.Error(ce.NewError(span, ce.CodeOAuthProviderNotFound, "Provider not found", err))
 		return
 	}
 
-	token, err := h.microsoft.Exchange(ctxWithTracer, params.Code)
-	if err != nil {
-		wErr := fmt.Errorf("failed to handle microsoft callback: %w", err)
-		ctx.Error(ce.NewError(span, ce.CodeOAuthCodeExchangeFailed, ce.MsgInternalServer, wErr))
+	var params dto.AuthenticateRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		wErr := fmt.Errorf("failed to handle oauth callback: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
 		return
 	}
 
-	user, err := h.microsoftGetUserInfo(ctxWithTracer, token.AccessToken)
+	data, state, err := h.exchange(ctxWithTracer, ctx, provider, &params)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
-	email := user.Mail
-	if email == "" {
-		email = user.UserPrincipalName
-	}
-
-	data := entities.OAuth{
-		Provider:   constants.OAuthProviderMicrosoft,
-		UID:        user.ID,
-		Email:      email,
-		IsVerified: true,
-	}
-	request := entities.Request{
-		UserAgent: ctx.Request.UserAgent(),
-		IPAddress: ctx.ClientIP(),
-	}
-
-	sessionToken, exchangeCode, err := h.oau.Authenticate(ctxWithTracer, &data, &request)
+	url, err := h.complete(ctxWithTracer, ctx, state, data)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
-	h.setCookie(ctx, sessionToken)
-
-	url := h.setRedirectURL(exchangeCode)
 	ctx.Redirect(http.StatusTemporaryRedirect, url)
 }
 
@@ -168,7 +121,10 @@ func (h *OAuthHandler) OAuthExchange(ctx *gin.Context) {
 		return
 	}
 
-	auth, accessToken, err := h.oau.ExchangeCode(ctxWithTracer, code)
+	// the session cookie is set on the callback, tokens are tied to it when present
+	sessionToken, _ := ctx.Cookie(constants.CookieKeySessionToken)
+
+	auth, accessToken, err := h.oau.ExchangeCode(ctxWithTracer, code, sessionToken)
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -182,63 +138,182 @@ func (h *OAuthHandler) OAuthExchange(ctx *gin.Context) {
 	utils.SetResponse(ctx, "Code exchanged successfully", response, http.StatusOK)
 }
 
-func (h *OAuthHandler) googleGetUserInfo(ctx context.Context, token *oauth2.Token, cfg *oauth2.Config) (*dto.GoogleUser, error) {
-	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "googleGetUserInfo")
+func (h *OAuthHandler) authCodeURL(ctx context.Context, gctx *gin.Context, provider *oauth.Provider, authID int64, redirectTo string) (string, error) {
+	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "authCodeURL")
 	defer span.End()
 
-	client := cfg.Client(ctx, token)
+	state, err := h.oau.CreateState(ctx, provider.ID(), authID, redirectTo)
+	if err != nil {
+		return "", err
+	}
+
+	url, err := provider.AuthCodeURL(
+		ctx, state.State,
+		oauth2.S256ChallengeOption(state.Verifier),
+		oauth2.SetAuthURLParam("nonce", state.Nonce),
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to build authorization url: %w", err)
+		return "", ce.NewError(span, ce.CodeOAuthDiscoveryFailed, ce.MsgInternalServer, wErr)
+	}
+
+	// binds the flow to the browser that started it, the callback must present the same state
+	h.cookie.Set(gctx, constants.CookieKeyOAuthState, state.State, h.cfg.OAuth.Duration.State, "/", h.cfg.Server.Host)
+
+	return url, nil
+}
+
+func (h *OAuthHandler) exchange(ctx context.Context, gctx *gin.Context, provider *oauth.Provider, params *dto.AuthenticateRequest) (*entities.OAuth, *entities.OAuthState, error) {
+	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "exchange")
+	defer span.End()
+
+	// the state cookie is single use whatever the outcome
+	cookieState, err := gctx.Cookie(constants.CookieKeyOAuthState)
+	h.cookie.Delete(gctx, constants.CookieKeyOAuthState, "/", h.cfg.Server.Host)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookieState), []byte(params.State)) != 1 {
+		err := fmt.Errorf("failed to exchange oauth code: %w", ce.ErrOAuthStateMismatch)
+		return nil, nil, ce.NewError(span, ce.CodeOAuthStateInvalid, ce.MsgInvalidOAuthState, err)
+	}
+
+	state, err := h.oau.UseState(ctx, provider.ID(), params.State)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token, err := provider.Exchange(ctx, params.Code, oauth2.VerifierOption(state.Verifier))
+	if err != nil {
+		wErr := fmt.Errorf("failed to exchange oauth code: %w", err)
+		return nil, nil, ce.NewError(span, ce.CodeOAuthCodeExchangeFailed, ce.MsgInternalServer, wErr)
+	}
+
+	idToken, ok := token.Extra("id_token").(string)
+	if !ok || idToken == "" {
+		err := fmt.Errorf("failed to exchange oauth code: %w", errors.New("id token not found"))
+		return nil, nil, ce.NewError(span, ce.CodeOAuthIDTokenInvalid, ce.MsgInvalidOAuthState, err)
+	}
+
+	data, err := provider.VerifyIDToken(ctx, idToken, state.Nonce)
+	if err != nil {
+		wErr := fmt.Errorf("failed to exchange oauth code: %w", err)
+		if errors.Is(err, oauth.ErrNonceInvalid) {
+			return nil, nil, ce.NewError(span, ce.CodeOAuthNonceMismatch, ce.MsgInvalidOAuthState, wErr)
+		}
+		return nil, nil, ce.NewError(span, ce.CodeOAuthIDTokenInvalid, ce.MsgInvalidOAuthState, wErr)
+	}
+
+	return data, state, nil
+}
+
+func (h *OAuthHandler) GetIdentities(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(oAuthErrorTracer).Start(ctx.Request.Context(), "GetIdentities")
+	defer span.End()
 
-	response, err := client.Get("https://www.googleapis.com/oauth2/v2/userinfo")
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
 	if err != nil {
-		wErr := fmt.Errorf("failed to fetch google user info: %w", err)
-		return nil, ce.NewError(span, ce.CodeOAuthGetUserInfoFailed, ce.MsgInternalServer, wErr)
+		wErr := fmt.Errorf("failed to fetch identities: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
 	}
-	defer response.Body.Close()
 
-	if response.StatusCode != http.StatusOK {
-		err := fmt.Errorf("failed to fetch google user info: %w", fmt.Errorf("response status '%s'", response.Status))
-		return nil, ce.NewError(span, ce.CodeOAuthGetUserInfoFailed, ce.MsgInternalServer, err)
+	identities, err := h.oau.GetIdentities(ctxWithTracer, authID)
+	if err != nil {
+		ctx.Error(err)
+		return
 	}
 
-	var user dto.GoogleUser
-	if err := json.NewDecoder(response.Body).Decode(&user); err != nil {
-		wErr := fmt.Errorf("failed to fetch google user info: %w", err)
-		return nil, ce.NewError(span, ce.CodeOAuthGetUserInfoFailed, ce.MsgInternalServer, wErr)
+	response := make([]dto.OAuthIdentityResponse, 0, len(identities))
+	for _, identity := range identities {
+		response = append(response, h.toOAuthIdentityResponse(identity))
 	}
 
-	return &user, nil
+	utils.SetResponse(ctx, "ok", response, http.StatusOK)
 }
 
-func (h *OAuthHandler) microsoftGetUserInfo(ctx context.Context, accessToken string) (*dto.MicrosoftUser, error) {
-	ctx, span := otel.Tracer(oAuthErrorTracer).Start(ctx, "microsoftGetUserInfo")
+func (h *OAuthHandler) LinkIdentity(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(oAuthErrorTracer).Start(ctx.Request.Context(), "LinkIdentity")
 	defer span.End()
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://graph.microsoft.com/v1.0/me", nil)
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
 	if err != nil {
-		wErr := fmt.Errorf("failed to fetch microsoft user info: %w", err)
-		return nil, ce.NewError(span, ce.CodeOAuthGetUserInfoFailed, ce.MsgInternalServer, wErr)
+		wErr := fmt.Errorf("failed to link identity: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
 	}
-	request.Header.Set("Authorization", "Bearer "+accessToken)
 
-	response, err := http.DefaultClient.Do(request)
+	provider, ok := h.oauth.Provider(ctx.Param("provider"))
+	if !ok {
+		err := fmt.Errorf("failed to link identity: %w", fmt.Errorf("unknown provider '%s'", ctx.Param("provider")))
+		ctx.Error(ce.NewError(span, ce.CodeOAuthProviderNotFound, "Provider not found", err))
+		return
+	}
+
+	var params dto.OAuthRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		wErr := fmt.Errorf("failed to link identity: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
+		return
+	}
+
+	// the client follows this url, the provider's callback then links instead of signing in
+	url, err := h.authCodeURL(ctxWithTracer, ctx, provider, authID, params.RedirectTo)
 	if err != nil {
-		wErr := fmt.Errorf("failed to fetch microsoft user info: %w", err)
-		return nil, ce.NewError(span, ce.CodeOAuthGetUserInfoFailed, ce.MsgInternalServer, wErr)
+		ctx.Error(err)
+		return
 	}
-	defer response.Body.Close()
 
-	if response.StatusCode != http.StatusOK {
-		err := fmt.Errorf("failed to fetch microsoft user info: %w", fmt.Errorf("response status '%s'", response.Status))
-		return nil, ce.NewError(span, ce.CodeOAuthGetUserInfoFailed, ce.MsgInternalServer, err)
+	utils.SetResponse(ctx, "ok", dto.LinkIdentityResponse{URL: url}, http.StatusOK)
+}
+
+func (h *OAuthHandler) UnlinkIdentity(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(oAuthErrorTracer).Start(ctx.Request.Context(), "UnlinkIdentity")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to unlink identity: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
 	}
 
-	var user dto.MicrosoftUser
-	if err := json.NewDecoder(response.Body).Decode(&user); err != nil {
-		wErr := fmt.Errorf("failed to fetch microsoft user info: %w", err)
-		return nil, ce.NewError(span, ce.CodeOAuthGetUserInfoFailed, ce.MsgInternalServer, wErr)
+	provider, ok := h.oauth.Provider(ctx.Param("provider"))
+	if !ok {
+		err := fmt.Errorf("failed to unlink identity: %w", fmt.Errorf("unknown provider '%s'", ctx.Param("provider")))
+		ctx.Error(ce.NewError(span, ce.CodeOAuthProviderNotFound, "Provider not found", err))
+		return
+	}
+
+	if err := h.oau.UnlinkIdentity(ctxWithTracer, authID, provider.ID()); err != nil {
+		ctx.Error(err)
+		return
 	}
 
-	return &user, nil
+	utils.SetResponse(ctx, "", nil, http.StatusNoContent)
+}
+
+func (h *OAuthHandler) complete(ctx context.Context, gctx *gin.Context, state *entities.OAuthState, data *entities.OAuth) (string, error) {
+	if state.AuthID != 0 {
+		if err := h.oau.LinkIdentity(ctx, state.AuthID, data); err != nil {
+			return "", err
+		}
+		return h.setRedirectURL("linked", h.providerName(data.Provider), state.RedirectTo), nil
+	}
+
+	request := entities.Request{
+		UserAgent: gctx.Request.UserAgent(),
+		IPAddress: gctx.ClientIP(),
+	}
+
+	sessionToken, exchangeCode, mfaToken, err := h.oau.Authenticate(ctx, data, &request)
+	if err != nil {
+		return "", err
+	}
+	if mfaToken != "" {
+		// the client finishes the sign-in through the regular mfa verification
+		return h.setRedirectURL("mfa_token", mfaToken, state.RedirectTo), nil
+	}
+
+	h.setCookie(gctx, sessionToken)
+	return h.setRedirectURL("code", exchangeCode, state.RedirectTo), nil
 }
 
 func (h *OAuthHandler) toAuthResponse(auth entities.Auth) dto.AuthResponse {
@@ -247,15 +322,41 @@ func (h *OAuthHandler) toAuthResponse(auth entities.Auth) dto.AuthResponse {
 		Email:      auth.Email,
 		RoleID:     auth.RoleID,
 		IsVerified: auth.IsVerified,
+		IsApproved: auth.IsApproved,
 		CreatedAt:  auth.CreatedAt,
 		UpdatedAt:  auth.UpdatedAt,
 	}
 }
 
+func (h *OAuthHandler) toOAuthIdentityResponse(identity entities.OAuthIdentity) dto.OAuthIdentityResponse {
+	return dto.OAuthIdentityResponse{
+		Provider:  h.providerName(identity.Provider),
+		UID:       identity.UID,
+		CreatedAt: identity.CreatedAt,
+	}
+}
+
+func (h *OAuthHandler) providerName(id int16) string {
+	if provider, ok := h.oauth.ProviderByID(id); ok {
+		return provider.Name()
+	}
+	// identities of a provider that has since been removed from the config
+	return strconv.Itoa(int(id))
+}
+
 func (h *OAuthHandler) setCookie(ctx *gin.Context, sessionToken string) {
 	h.cookie.Set(ctx, constants.CookieKeySessionToken, sessionToken, h.cfg.Auth.TokenDuration.Session, "/", h.cfg.Server.Host)
 }
 
-func (h *OAuthHandler) setRedirectURL(code string) string {
-	return fmt.Sprintf("%s/auth/oauth-callback?code=%s", h.cfg.Client.BaseURL, code)
+func (h *OAuthHandler) setRedirectURL(key, value, redirectTo string) string {
+	if redirectTo == "" {
+		return fmt.Sprintf("%s/auth/oauth-callback?%s=%s", h.cfg.Client.BaseURL, key, value)
+	}
+
+	// allow-listed targets (e.g. a mobile app scheme) get the same result
+	separator := "?"
+	if strings.Contains(redirectTo, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%s%s=%s", redirectTo, separator, key, value)
 }
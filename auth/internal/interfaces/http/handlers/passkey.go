@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/usecases"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/dto"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/services"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+)
+
+const passkeyErrorTracer string = "handler.passkey"
+
+type PasskeyHandler struct {
+	pu     usecases.PasskeyUsecase
+	cookie *services.CookieService
+	cfg    *configs.Config
+}
+
+func NewPasskeyHandler(pu usecases.PasskeyUsecase, cookie *services.CookieService, cfg *configs.Config) *PasskeyHandler {
+	return &PasskeyHandler{pu, cookie, cfg}
+}
+
+func (h *PasskeyHandler) BeginRegistration(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(passkeyErrorTracer).Start(ctx.Request.Context(), "BeginRegistration")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to begin passkey registration: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	options, err := h.pu.BeginRegistration(ctxWithTracer, authID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := dto.BeginPasskeyRegistrationResponse{
+		Options: options,
+	}
+
+	utils.SetResponse(ctx, "ok", response, http.StatusOK)
+}
+
+func (h *PasskeyHandler) FinishRegistration(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(passkeyErrorTracer).Start(ctx.Request.Context(), "FinishRegistration")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to finish passkey registration: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	var payload dto.RegisterPasskeyRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to finish passkey registration: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	data := entities.RegisterPasskey{
+		Name:     strings.TrimSpace(payload.Name),
+		Response: payload.Credential,
+	}
+
+	passkey, err := h.pu.FinishRegistration(ctxWithTracer, authID, &data)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "Passkey registered successfully", h.toPasskeyResponse(*passkey), http.StatusCreated)
+}
+
+func (h *PasskeyHandler) BeginLogin(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(passkeyErrorTracer).Start(ctx.Request.Context(), "BeginLogin")
+	defer span.End()
+
+	challengeID, options, err := h.pu.BeginLogin(ctxWithTracer)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := dto.BeginPasskeyLoginResponse{
+		ChallengeID: challengeID,
+		Options:     options,
+	}
+
+	utils.SetResponse(ctx, "ok", response, http.StatusOK)
+}
+
+func (h *PasskeyHandler) FinishLogin(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(passkeyErrorTracer).Start(ctx.Request.Context(), "FinishLogin")
+	defer span.End()
+
+	var payload dto.LoginPasskeyRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to finish passkey login: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	data := entities.LoginPasskey{
+		ChallengeID: strings.TrimSpace(payload.ChallengeID),
+		Response:    payload.Credential,
+	}
+	request := entities.Request{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	authToken, auth, err := h.pu.FinishLogin(ctxWithTracer, &data, &request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := dto.LoginPasskeyResponse{
+		Token: authToken.AccessToken,
+		Auth:  h.toAuthResponse(*auth),
+	}
+
+	h.setCookie(ctx, authToken.SessionToken)
+	utils.SetResponse(ctx, "Logged in successfully", response, http.StatusOK)
+}
+
+func (h *PasskeyHandler) GetAll(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(passkeyErrorTracer).Start(ctx.Request.Context(), "GetAll")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch passkeys: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	passkeys, err := h.pu.GetAll(ctxWithTracer, authID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := make([]dto.PasskeyResponse, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		response = append(response, h.toPasskeyResponse(passkey))
+	}
+
+	utils.SetResponse(ctx, "ok", response, http.StatusOK)
+}
+
+func (h *PasskeyHandler) Rename(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(passkeyErrorTracer).Start(ctx.Request.Context(), "Rename")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to rename passkey: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	passkeyID, err := utils.ToInt64(ctx.Param("passkey_id"))
+	if err != nil {
+		wErr := fmt.Errorf("failed to rename passkey: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
+		return
+	}
+
+	var payload dto.RenamePasskeyRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		wErr := fmt.Errorf("failed to rename passkey: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+
+	passkey, err := h.pu.Rename(ctxWithTracer, authID, passkeyID, strings.TrimSpace(payload.Name))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "Passkey renamed successfully", h.toPasskeyResponse(*passkey), http.StatusOK)
+}
+
+func (h *PasskeyHandler) Delete(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(passkeyErrorTracer).Start(ctx.Request.Context(), "Delete")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to delete passkey: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	passkeyID, err := utils.ToInt64(ctx.Param("passkey_id"))
+	if err != nil {
+		wErr := fmt.Errorf("failed to delete passkey: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
+		return
+	}
+
+	if err := h.pu.Delete(ctxWithTracer, authID, passkeyID); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "", nil, http.StatusNoContent)
+}
+
+func (h *PasskeyHandler) toPasskeyResponse(passkey entities.Passkey) dto.PasskeyResponse {
+	return dto.PasskeyResponse{
+		ID:         passkey.ID,
+		Name:       passkey.Name,
+		LastUsedAt: passkey.LastUsedAt,
+		CreatedAt:  passkey.CreatedAt,
+		UpdatedAt:  passkey.UpdatedAt,
+	}
+}
+
+func (h *PasskeyHandler) toAuthResponse(auth entities.Auth) dto.AuthResponse {
+	return dto.AuthResponse{
+		ID:         auth.ID,
+		Email:      auth.Email,
+		RoleID:     auth.RoleID,
+		IsVerified: auth.IsVerified,
+		CreatedAt:  auth.CreatedAt,
+		UpdatedAt:  auth.UpdatedAt,
+	}
+}
+
+func (h *PasskeyHandler) setCookie(ctx *gin.Context, sessionToken string) {
+	h.cookie.Set(ctx, constants.CookieKeySessionToken, sessionToken, h.cfg.Auth.TokenDuration.Session, "/", h.cfg.Server.Host)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/usecases"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/dto"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+)
+
+const securityEventErrorTracer string = "handler.security_event"
+
+type SecurityEventHandler struct {
+	seu usecases.SecurityEventUsecase
+}
+
+func NewSecurityEventHandler(seu usecases.SecurityEventUsecase) *SecurityEventHandler {
+	return &SecurityEventHandler{seu}
+}
+
+func (h *SecurityEventHandler) GetAll(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(securityEventErrorTracer).Start(ctx.Request.Context(), "GetAll")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch security events: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	var params dto.GetSecurityEventsRequest
+	if err := ctx.ShouldBindQuery(&params); err != nil {
+		wErr := fmt.Errorf("failed to fetch security events: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
+		return
+	}
+
+	events, nextCursor, err := h.seu.GetEvents(ctxWithTracer, authID, params.Cursor, params.Limit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "ok", toSecurityEventListResponse(events, nextCursor), http.StatusOK)
+}
+
+// shared with the admin handler, which lists the same events across accounts
+func toSecurityEventListResponse(events []entities.SecurityEvent, nextCursor string) dto.SecurityEventListResponse {
+	response := dto.SecurityEventListResponse{
+		Events: make([]dto.SecurityEventResponse, 0, len(events)),
+	}
+	if nextCursor != "" {
+		response.NextCursor = &nextCursor
+	}
+	for _, event := range events {
+		response.Events = append(response.Events, dto.SecurityEventResponse{
+			ID:        event.ID,
+			AuthID:    event.AuthID,
+			EventType: event.EventType,
+			Details:   event.Details,
+			UserAgent: event.UserAgent,
+			IPAddress: event.IPAddress,
+			RequestID: event.RequestID,
+			TraceID:   event.TraceID,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+	return response
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/usecases"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/dto"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"go.opentelemetry.io/otel"
+)
+
+const serviceAccountErrorTracer string = "handler.service_account"
+
+type ServiceAccountHandler struct {
+	sau usecases.ServiceAccountUsecase
+}
+
+func NewServiceAccountHandler(sau usecases.ServiceAccountUsecase) *ServiceAccountHandler {
+	return &ServiceAccountHandler{sau}
+}
+
+func (h *ServiceAccountHandler) IssueToken(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(serviceAccountErrorTracer).Start(ctx.Request.Context(), "IssueToken")
+	defer span.End()
+
+	var payload dto.ClientTokenRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		wErr := fmt.Errorf("failed to issue token: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+		return
+	}
+	if payload.GrantType != constants.GrantTypeClientCredentials {
+		wErr := fmt.Errorf("failed to issue token: %w", fmt.Errorf("unsupported grant type %q", payload.GrantType))
+		ctx.Error(ce.NewError(span, ce.CodeGrantTypeUnsupported, ce.MsgUnsupportedGrantType, wErr))
+		return
+	}
+
+	// clients may authenticate with either http basic or the request body
+	clientID, clientSecret, ok := ctx.Request.BasicAuth()
+	if !ok {
+		clientID, clientSecret = payload.ClientID, payload.ClientSecret
+	}
+	if clientID == "" || clientSecret == "" {
+		wErr := fmt.Errorf("failed to issue token: %w", errors.New("missing client credentials"))
+		ctx.Error(ce.NewError(span, ce.CodeClientInvalid, ce.MsgInvalidClient, wErr))
+		return
+	}
+
+	data := entities.ClientCredentials{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       strings.Fields(payload.Scope),
+	}
+
+	accessToken, scopes, err := h.sau.IssueToken(ctxWithTracer, &data)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := dto.ClientTokenResponse{
+		AccessToken: accessToken.Token,
+		TokenType:   constants.TokenTypeBearer,
+		ExpiresIn:   int64(time.Until(accessToken.ExpiresAt).Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}
+
+	// served as a bare token response, as oauth clients expect
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/usecases"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/dto"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const sessionErrorTracer string = "handler.session"
+
+type SessionHandler struct {
+	su usecases.SessionUsecase
+}
+
+func NewSessionHandler(su usecases.SessionUsecase) *SessionHandler {
+	return &SessionHandler{su}
+}
+
+func (h *SessionHandler) GetAll(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(sessionErrorTracer).Start(ctx.Request.Context(), "GetAll")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch sessions: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	sessionToken, err := ctx.Cookie(constants.CookieKeySessionToken)
+	if err != nil {
+		// non-fatal: trace the failure, but continue
+		span.AddEvent(
+			"session cookie not found",
+			trace.WithAttributes(attribute.String("error", err.Error())),
+		)
+	}
+
+	sessions, err := h.su.GetActiveSessions(ctxWithTracer, authID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, h.toSessionResponse(session, sessionToken))
+	}
+
+	utils.SetResponse(ctx, "ok", response, http.StatusOK)
+}
+
+func (h *SessionHandler) Revoke(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(sessionErrorTracer).Start(ctx.Request.Context(), "Revoke")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to revoke session: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	sessionID, err := utils.ToInt64(ctx.Param("session_id"))
+	if err != nil {
+		wErr := fmt.Errorf("failed to revoke session: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
+		return
+	}
+
+	if err := h.su.RevokeSessionByID(ctxWithTracer, authID, sessionID); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "", nil, http.StatusNoContent)
+}
+
+func (h *SessionHandler) RevokeOthers(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(sessionErrorTracer).Start(ctx.Request.Context(), "RevokeOthers")
+	defer span.End()
+
+	authID, err := utils.CtxGetAuthID(ctxWithTracer)
+	if err != nil {
+		wErr := fmt.Errorf("failed to revoke other sessions: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextValueNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+
+	sessionToken, err := ctx.Cookie(constants.CookieKeySessionToken)
+	if errors.Is(err, ce.ErrCookieNotFound) {
+		wErr := fmt.Errorf("failed to revoke other sessions: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextCookieNotFound, ce.MsgUnauthenticated, wErr))
+		return
+	}
+	if err != nil {
+		wErr := fmt.Errorf("failed to revoke other sessions: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeContextCookieNotFound, ce.MsgInternalServer, wErr))
+		return
+	}
+	if sessionToken == "" {
+		wErr := fmt.Errorf("failed to revoke other sessions: %w", ce.ErrCookieNotFound)
+		ctx.Error(ce.NewError(span, ce.CodeSessionNotFound, ce.MsgUnauthenticated, wErr))
+		return
+	}
+
+	if err := h.su.RevokeOtherSessions(ctxWithTracer, authID, sessionToken); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SetResponse(ctx, "", nil, http.StatusNoContent)
+}
+
+func (h *SessionHandler) toSessionResponse(session entities.Session, sessionToken string) dto.SessionResponse {
+	return dto.SessionResponse{
+		ID:         session.ID,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		IsCurrent:  sessionToken != "" && session.Token == sessionToken,
+		CreatedAt:  session.SignedInAt,
+		LastUsedAt: session.CreatedAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+}
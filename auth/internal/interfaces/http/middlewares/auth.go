@@ -4,24 +4,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/caches"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/services"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const authErrorTracer string = "middleware.auth"
 
 type AuthMiddleware struct {
 	jwt     *services.JWTService
+	tdc     caches.TokenDenylistCache
 	appName string
 }
 
-func NewAuthMiddleware(jwt *services.JWTService, appName string) *AuthMiddleware {
-	return &AuthMiddleware{jwt, appName}
+func NewAuthMiddleware(jwt *services.JWTService, tdc caches.TokenDenylistCache, appName string) *AuthMiddleware {
+	return &AuthMiddleware{jwt, tdc, appName}
 }
 
 func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
@@ -53,6 +59,8 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 				err = ce.NewError(span, ce.CodeAuthTokenExpired, ce.MsgUnauthenticated, wErr)
 			case errors.Is(err, ce.ErrTokenMalformed):
 				err = ce.NewError(span, ce.CodeAuthTokenMalformed, ce.MsgUnauthenticated, wErr)
+			case errors.Is(err, ce.ErrTokenSignature), errors.Is(err, ce.ErrTokenUnverifiable):
+				err = ce.NewError(span, ce.CodeAuthTokenInvalid, ce.MsgUnauthenticated, wErr)
 			case errors.Is(err, ce.ErrInvalidTokenClaim):
 				err = ce.NewError(span, ce.CodeInvalidTokenClaim, ce.MsgUnauthenticated, wErr)
 			default:
@@ -71,9 +79,41 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 			return
 		}
 
+		if claim.SubjectType == constants.SubjectTypeService {
+			// every route here acts on an account, which a service account does not have
+			wErr := fmt.Errorf("failed to authenticate: %w", errors.New("service account token"))
+			ctx.Error(ce.NewError(span, ce.CodeScopeUnauthorized, ce.MsgScopeUnauthorized, wErr))
+			ctx.Abort()
+			return
+		}
+
+		isDenied, err := m.tdc.IsDenied(ctxWithTracer, claim.ID, claim.ExpiresAt.Time)
+		if err != nil {
+			// non-fatal: fail open, the token is still signed and unexpired
+			span.AddEvent(
+				"token denylist check failed",
+				trace.WithAttributes(attribute.String("error", err.Error())),
+			)
+		}
+		if isDenied {
+			wErr := fmt.Errorf("failed to authenticate: %w", ce.ErrTokenRevoked)
+			ctx.Error(ce.NewError(span, ce.CodeAuthTokenRevoked, ce.MsgUnauthenticated, wErr))
+			ctx.Abort()
+			return
+		}
+
 		ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyAuthID, claim.AuthID)
 		ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyRoleID, claim.RoleID)
 		ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyIsVerified, claim.IsVerified)
+		if claim.Act != nil {
+			// impersonated, logged for the audit trail
+			log.Printf(
+				"IMPERSONATION -> admin %d acting as auth %d: %s %s",
+				claim.Act.AuthID, claim.AuthID, ctx.Request.Method, ctx.Request.URL.Path,
+			)
+			span.SetAttributes(attribute.Int64("impersonator_id", claim.Act.AuthID))
+			ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyImpersonatorID, claim.Act.AuthID)
+		}
 
 		ctx.Request = ctx.Request.WithContext(ctxWithTracer)
 		ctx.Next()
@@ -98,6 +138,23 @@ func (m *AuthMiddleware) Authorize(roleID int16) gin.HandlerFunc {
 	}
 }
 
+// guards actions an admin must not take on behalf of the owner, such as credential changes
+func (m *AuthMiddleware) DenyImpersonation() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "DenyImpersonation")
+		defer span.End()
+
+		if _, ok := utils.CtxGetImpersonatorID(ctxWithTracer); ok {
+			wErr := fmt.Errorf("failed to deny impersonation: %w", errors.New("action not allowed while impersonating"))
+			ctx.Error(ce.NewError(span, ce.CodeImpersonationForbidden, ce.MsgImpersonationForbidden, wErr))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
 func (m *AuthMiddleware) RequireVerified() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "RequireVerified")
@@ -2,7 +2,9 @@ package middlewares
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/services/logger"
@@ -31,6 +33,10 @@ func ErrorHandler(l *logger.Logger) gin.HandlerFunc {
 			}
 
 			l.Log(ctx, constants.LogLevelError, "Request Error", customErr.HTTPStatus(), fields...)
+			if customErr.RetryAfter > 0 {
+				retryAfter := int(math.Ceil(customErr.RetryAfter.Seconds()))
+				ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+			}
 			utils.SetErrorResponse(ctx, customErr.Message, customErr.HTTPStatus())
 			return
 		}
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/services/logger"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
 	"go.uber.org/zap"
 )
 
@@ -15,13 +16,18 @@ func Logger(l *logger.Logger) gin.HandlerFunc {
 		start := time.Now().UTC()
 		ctx.Next()
 
-		latency := zap.String("latency", time.Since(start).String())
+		fields := []zap.Field{zap.String("latency", time.Since(start).String())}
 		statusCode := ctx.Writer.Status()
 
+		// the request context is replaced on authentication, so it is read after the handlers ran
+		if impersonatorID, ok := utils.CtxGetImpersonatorID(ctx.Request.Context()); ok {
+			fields = append(fields, zap.Int64("impersonator_id", impersonatorID))
+		}
+
 		if statusCode < http.StatusBadRequest {
-			l.Log(ctx, constants.LogLevelInfo, "Request", statusCode, latency)
+			l.Log(ctx, constants.LogLevelInfo, "Request", statusCode, fields...)
 		} else {
-			l.Log(ctx, constants.LogLevelWarn, "Request Warning", statusCode, latency)
+			l.Log(ctx, constants.LogLevelWarn, "Request Warning", statusCode, fields...)
 		}
 	}
 }
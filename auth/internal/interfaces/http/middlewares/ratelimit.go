@@ -0,0 +1,122 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/caches"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const rateLimitErrorTracer string = "middleware.ratelimit"
+
+// large enough for any credential payload, the body is buffered to find the account
+const rateLimitMaxPeekSize int64 = 1 << 20
+
+type RateLimitMiddleware struct {
+	rlc     caches.RateLimitCache
+	enabled bool
+}
+
+func NewRateLimitMiddleware(rlc caches.RateLimitCache, enabled bool) *RateLimitMiddleware {
+	return &RateLimitMiddleware{rlc, enabled}
+}
+
+func (m *RateLimitMiddleware) Limit(route string, rule configs.RateLimitRule) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !m.enabled {
+			ctx.Next()
+			return
+		}
+
+		ctxWithTracer, span := otel.Tracer(rateLimitErrorTracer).Start(ctx.Request.Context(), "Limit")
+		defer span.End()
+
+		policy := entities.RateLimitPolicy{
+			IPLimit:      rule.IPLimit,
+			AccountLimit: rule.AccountLimit,
+			Window:       rule.Window,
+		}
+
+		account, err := m.account(ctx)
+		if err != nil {
+			wErr := fmt.Errorf("failed to limit rate: %w", err)
+			ctx.Error(ce.NewError(span, ce.CodeInvalidPayload, ce.MsgInvalidPayload, wErr))
+			ctx.Abort()
+			return
+		}
+
+		rateLimit, err := m.rlc.Allow(ctxWithTracer, route, ctx.ClientIP(), account, &policy)
+		if err != nil {
+			// non-fatal: fail open so an unavailable cache does not take authentication down with it
+			span.AddEvent(
+				"rate limit evaluation failed",
+				trace.WithAttributes(attribute.String("error", err.Error())),
+			)
+			ctx.Next()
+			return
+		}
+
+		if rateLimit.Limit > 0 {
+			ctx.Header("X-RateLimit-Limit", strconv.Itoa(rateLimit.Limit))
+			ctx.Header("X-RateLimit-Remaining", strconv.Itoa(rateLimit.Remaining))
+			ctx.Header("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(rateLimit.ResetAfter.Seconds()))))
+		}
+
+		if !rateLimit.IsAllowed {
+			wErr := fmt.Errorf("failed to limit rate: %w", errors.New("rate limit exceeded"))
+			ctx.Error(ce.NewError(span, ce.CodeRateLimitExceeded, ce.MsgTooManyRequests, wErr).WithRetryAfter(rateLimit.ResetAfter))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// the account a request targets: the signed-in account, the email, or else the
+// token of a link or code, which caps how often a single one can be tried
+func (m *RateLimitMiddleware) account(ctx *gin.Context) (string, error) {
+	if authID, err := utils.CtxGetAuthID(ctx.Request.Context()); err == nil {
+		return strconv.FormatInt(authID, 10), nil
+	}
+
+	email, token := ctx.Query("email"), ctx.Query("token")
+	if email == "" && ctx.Request.Body != nil && ctx.Request.Body != http.NoBody {
+		// peek at the payload, then restore it for the handler
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, rateLimitMaxPeekSize))
+		if err != nil {
+			return "", fmt.Errorf("failed to read request body: %w", err)
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var payload struct {
+			Email string `json:"email"`
+			Token string `json:"token"`
+		}
+		if err := json.Unmarshal(body, &payload); err == nil {
+			email = payload.Email
+			if payload.Token != "" {
+				token = payload.Token
+			}
+		}
+	}
+
+	if email == "" && token != "" {
+		return "t:" + utils.HashSHA256(token), nil
+	}
+	return utils.Normalize(email), nil
+}
@@ -24,3 +24,32 @@ func RequestID() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// carried along so events recorded deep in a flow know where the request came from
+func Client() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		c := context.WithValue(ctx.Request.Context(), constants.CtxKeyUserAgent, ctx.Request.UserAgent())
+		c = context.WithValue(c, constants.CtxKeyIPAddress, ctx.ClientIP())
+		ctx.Request = ctx.Request.WithContext(c)
+
+		ctx.Next()
+	}
+}
+
+func Locale() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// only the primary language of the most preferred tag is kept, e.g. "id" from "id-ID,en;q=0.8"
+		tag, _, _ := strings.Cut(ctx.GetHeader("Accept-Language"), ",")
+		tag, _, _ = strings.Cut(tag, ";")
+		language, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+		language = strings.ToLower(language)
+
+		if language != "" && language != "*" {
+			ctx.Request = ctx.Request.WithContext(
+				context.WithValue(ctx.Request.Context(), constants.CtxKeyLocale, language),
+			)
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,24 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/handlers"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/middlewares"
+)
+
+type accountRouter struct {
+	h    *handlers.AccountHandler
+	auth *middlewares.AuthMiddleware
+}
+
+func newAccountRouter(h *handlers.AccountHandler, auth *middlewares.AuthMiddleware) *accountRouter {
+	return &accountRouter{h, auth}
+}
+
+func (r *accountRouter) register(rg *gin.RouterGroup) {
+	rg.GET("/deletion", r.auth.Authenticate(), r.h.GetDeletion)
+
+	rg.POST("/deletion/cancel", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.h.CancelDeletion)
+
+	rg.DELETE("", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.h.Delete)
+}
@@ -0,0 +1,38 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/handlers"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/middlewares"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+)
+
+type adminRouter struct {
+	h    *handlers.AdminHandler
+	auth *middlewares.AuthMiddleware
+}
+
+func newAdminRouter(h *handlers.AdminHandler, auth *middlewares.AuthMiddleware) *adminRouter {
+	return &adminRouter{h, auth}
+}
+
+func (r *adminRouter) register(rg *gin.RouterGroup) {
+	rg.Use(r.auth.Authenticate(), r.auth.DenyImpersonation(), r.auth.Authorize(constants.RoleAdmin))
+
+	rg.GET("/accounts", r.h.SearchAccounts)
+	rg.GET("/accounts/:auth_id", r.h.GetAccount)
+	rg.GET("/accounts/:auth_id/sessions", r.h.GetSessions)
+	rg.GET("/security-events", r.h.SearchSecurityEvents)
+	rg.GET("/service-accounts", r.h.GetServiceAccounts)
+
+	rg.POST("/accounts/:auth_id/verify", r.h.VerifyAccount)
+	rg.POST("/accounts/:auth_id/approve", r.h.ApproveAccount)
+	rg.POST("/accounts/:auth_id/suspend", r.h.SuspendAccount)
+	rg.POST("/accounts/:auth_id/unsuspend", r.h.UnsuspendAccount)
+	rg.POST("/accounts/:auth_id/password-reset", r.h.ForcePasswordReset)
+	rg.POST("/accounts/:auth_id/sessions/revoke", r.h.RevokeSessions)
+	rg.POST("/accounts/:auth_id/impersonate", r.h.Impersonate)
+	rg.POST("/service-accounts", r.h.CreateServiceAccount)
+	rg.POST("/service-accounts/:service_account_id/rotate-secret", r.h.RotateServiceAccountSecret)
+	rg.POST("/service-accounts/:service_account_id/disable", r.h.DisableServiceAccount)
+}
@@ -2,33 +2,58 @@ package router
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/handlers"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/middlewares"
 )
 
 type authRouter struct {
-	h    *handlers.AuthHandler
-	auth *middlewares.AuthMiddleware
+	h     *handlers.AuthHandler
+	auth  *middlewares.AuthMiddleware
+	rl    *middlewares.RateLimitMiddleware
+	rlCfg *configs.RateLimit
 }
 
-func newAuthRouter(h *handlers.AuthHandler, auth *middlewares.AuthMiddleware) *authRouter {
-	return &authRouter{h, auth}
+func newAuthRouter(
+	h *handlers.AuthHandler,
+	auth *middlewares.AuthMiddleware,
+	rl *middlewares.RateLimitMiddleware,
+	rlCfg *configs.RateLimit,
+) *authRouter {
+	return &authRouter{h, auth, rl, rlCfg}
 }
 
 func (r *authRouter) register(rg *gin.RouterGroup) {
-	rg.GET("/email/available", r.h.IsEmailRegistered)
+	rg.GET("/email/available", r.rl.Limit("email_available", r.rlCfg.EmailAvailable), r.h.IsEmailRegistered)
 	rg.GET("/verify-account/confirm", r.h.VerifyAccount)
 	rg.GET("/change-email/confirm", r.h.ConfirmEmailChange)
+	rg.GET("/unlock/confirm", r.h.UnlockAccount)
+	rg.GET("/sign-in/report", r.rl.Limit("sign_in_report", r.rlCfg.SignInReport), r.h.ReportSignIn)
 
-	rg.POST("/register", r.h.Register)
-	rg.POST("/login", r.h.Login)
-	rg.POST("/logout", r.auth.Authenticate(), r.h.Logout)
+	rg.POST("/register", r.rl.Limit("register", r.rlCfg.Register), r.h.Register)
+	rg.POST("/register/pharmacy", r.rl.Limit("register", r.rlCfg.Register), r.h.RegisterPharmacy)
+	rg.POST("/login", r.rl.Limit("login", r.rlCfg.Login), r.h.Login)
+	rg.POST("/logout", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.h.Logout)
 	rg.POST("/refresh-session", r.h.RefreshSession)
-	rg.POST("/forgot-password", r.h.ForgotPassword)
-	rg.POST("/reset-password/confirm", r.h.ResetPassword)
-	rg.POST("/reset-password/validate", r.h.IsResetTokenValid)
-	rg.POST("/verify-account/resend", r.auth.Authenticate(), r.h.ResendVerification)
+	rg.POST("/forgot-password", r.rl.Limit("forgot_password", r.rlCfg.ForgotPassword), r.h.ForgotPassword)
+	rg.POST("/reset-password/confirm", r.rl.Limit("reset_password", r.rlCfg.ResetPassword), r.h.ResetPassword)
+	rg.POST("/reset-password/validate", r.rl.Limit("validate_reset_token", r.rlCfg.ValidateResetToken), r.h.IsResetTokenValid)
+	rg.POST("/unlock/request", r.rl.Limit("unlock", r.rlCfg.Unlock), r.h.RequestUnlock)
+	rg.POST("/magic-link/request", r.rl.Limit("magic_link", r.rlCfg.MagicLink), r.h.RequestMagicLink)
+	rg.POST("/magic-link/confirm", r.rl.Limit("magic_link_confirm", r.rlCfg.MagicLinkConfirm), r.h.ConfirmMagicLink)
+	rg.POST("/login/code/request", r.rl.Limit("login_code", r.rlCfg.LoginCode), r.h.RequestLoginCode)
+	rg.POST("/login/code/confirm", r.rl.Limit("login", r.rlCfg.Login), r.h.ConfirmLoginCode)
+	rg.POST("/verify-account/code", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.h.VerifyAccountCode)
+	rg.POST("/change-email/code", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.auth.RequireVerified(), r.h.ConfirmEmailChangeCode)
+	rg.POST("/password", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.auth.RequireVerified(), r.h.SetPassword)
+	rg.POST(
+		"/verify-account/resend",
+		r.auth.Authenticate(),
+		r.auth.DenyImpersonation(),
+		r.rl.Limit("resend_verification", r.rlCfg.ResendVerification),
+		r.h.ResendVerification,
+	)
 
-	rg.PATCH("/change-email/request", r.auth.Authenticate(), r.auth.RequireVerified(), r.h.ChangeEmail)
-	rg.PATCH("/password", r.auth.Authenticate(), r.auth.RequireVerified(), r.h.ChangePassword)
+	rg.PATCH("/change-email/request", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.auth.RequireVerified(), r.h.ChangeEmail)
+	rg.PATCH("/password", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.auth.RequireVerified(), r.h.ChangePassword)
 }
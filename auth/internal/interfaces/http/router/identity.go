@@ -0,0 +1,24 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/handlers"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/middlewares"
+)
+
+type identityRouter struct {
+	h    *handlers.OAuthHandler
+	auth *middlewares.AuthMiddleware
+}
+
+func newIdentityRouter(h *handlers.OAuthHandler, auth *middlewares.AuthMiddleware) *identityRouter {
+	return &identityRouter{h, auth}
+}
+
+func (r *identityRouter) register(rg *gin.RouterGroup) {
+	rg.GET("", r.auth.Authenticate(), r.h.GetIdentities)
+
+	rg.POST("/:provider", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.auth.RequireVerified(), r.h.LinkIdentity)
+
+	rg.DELETE("/:provider", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.h.UnlinkIdentity)
+}
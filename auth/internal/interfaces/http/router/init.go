@@ -16,8 +16,17 @@ type Router struct {
 func NewRouter(
 	l *logger.Logger,
 	am *middlewares.AuthMiddleware,
+	rlm *middlewares.RateLimitMiddleware,
 	ah *handlers.AuthHandler,
+	mh *handlers.MFAHandler,
+	ph *handlers.PasskeyHandler,
+	sh *handlers.SessionHandler,
+	seh *handlers.SecurityEventHandler,
 	oah *handlers.OAuthHandler,
+	ach *handlers.AccountHandler,
+	adh *handlers.AdminHandler,
+	sah *handlers.ServiceAccountHandler,
+	jh *handlers.JWKSHandler,
 	cfg *configs.Config,
 ) *Router {
 	r := gin.New()
@@ -34,14 +43,40 @@ func NewRouter(
 		ctx.JSON(200, gin.H{"status": "ok"})
 	})
 
-	api := r.Group("/api/v1", middlewares.RequestID())
+	r.GET("/.well-known/jwks.json", jh.GetJWKS)
 
-	auth := newAuthRouter(ah, am)
+	api := r.Group("/api/v1", middlewares.RequestID(), middlewares.Client(), middlewares.Locale())
+
+	auth := newAuthRouter(ah, am, rlm, &cfg.RateLimit)
 	auth.register(api.Group("/auth"))
 
+	token := newTokenRouter(sah, rlm, &cfg.RateLimit)
+	token.register(api.Group("/auth/token"))
+
+	mfa := newMFARouter(mh, am)
+	mfa.register(api.Group("/auth/mfa"))
+
+	passkey := newPasskeyRouter(ph, am)
+	passkey.register(api.Group("/auth/passkeys"))
+
+	session := newSessionRouter(sh, am)
+	session.register(api.Group("/auth/sessions"))
+
+	securityEvent := newSecurityEventRouter(seh, am)
+	securityEvent.register(api.Group("/auth/security-events"))
+
+	identity := newIdentityRouter(oah, am)
+	identity.register(api.Group("/auth/identities"))
+
+	account := newAccountRouter(ach, am)
+	account.register(api.Group("/auth/me"))
+
 	oauth := newOAuthRouter(oah)
 	oauth.register(api.Group("/oauth"))
 
+	admin := newAdminRouter(adh, am)
+	admin.register(api.Group("/admin"))
+
 	return &Router{router: r}
 }
 
@@ -0,0 +1,24 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/handlers"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/middlewares"
+)
+
+type mfaRouter struct {
+	h    *handlers.MFAHandler
+	auth *middlewares.AuthMiddleware
+}
+
+func newMFARouter(h *handlers.MFAHandler, auth *middlewares.AuthMiddleware) *mfaRouter {
+	return &mfaRouter{h, auth}
+}
+
+func (r *mfaRouter) register(rg *gin.RouterGroup) {
+	rg.POST("/verify", r.h.VerifyLogin)
+	rg.POST("/enroll", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.auth.RequireVerified(), r.h.Enroll)
+	rg.POST("/enroll/confirm", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.auth.RequireVerified(), r.h.ConfirmEnrollment)
+	rg.POST("/disable", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.auth.RequireVerified(), r.h.Disable)
+	rg.POST("/recovery-codes", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.auth.RequireVerified(), r.h.RegenerateRecoveryCodes)
+}
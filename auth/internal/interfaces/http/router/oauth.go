@@ -14,13 +14,8 @@ func newOAuthRouter(h *handlers.OAuthHandler) *oAuthRouter {
 }
 
 func (r *oAuthRouter) register(rg *gin.RouterGroup) {
-	rg.POST("/exchange", r.h.OAuthExchange)
-
-	google := rg.Group("/google")
-	google.GET("", r.h.GoogleOAuth)
-	google.GET("/callback", r.h.GoogleCallback)
+	rg.GET("/:provider", r.h.OAuth)
+	rg.GET("/:provider/callback", r.h.Callback)
 
-	microsoft := rg.Group("/microsoft")
-	microsoft.GET("", r.h.MicrosoftOAuth)
-	microsoft.GET("/callback", r.h.MicrosoftCallback)
+	rg.POST("/exchange", r.h.OAuthExchange)
 }
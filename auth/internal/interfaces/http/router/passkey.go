@@ -0,0 +1,29 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/handlers"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/middlewares"
+)
+
+type passkeyRouter struct {
+	h    *handlers.PasskeyHandler
+	auth *middlewares.AuthMiddleware
+}
+
+func newPasskeyRouter(h *handlers.PasskeyHandler, auth *middlewares.AuthMiddleware) *passkeyRouter {
+	return &passkeyRouter{h, auth}
+}
+
+func (r *passkeyRouter) register(rg *gin.RouterGroup) {
+	rg.GET("", r.auth.Authenticate(), r.h.GetAll)
+
+	rg.POST("/login/begin", r.h.BeginLogin)
+	rg.POST("/login/finish", r.h.FinishLogin)
+	rg.POST("/register/begin", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.auth.RequireVerified(), r.h.BeginRegistration)
+	rg.POST("/register/finish", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.auth.RequireVerified(), r.h.FinishRegistration)
+
+	rg.PATCH("/:passkey_id", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.h.Rename)
+
+	rg.DELETE("/:passkey_id", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.h.Delete)
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/handlers"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/middlewares"
+)
+
+type securityEventRouter struct {
+	h    *handlers.SecurityEventHandler
+	auth *middlewares.AuthMiddleware
+}
+
+func newSecurityEventRouter(h *handlers.SecurityEventHandler, auth *middlewares.AuthMiddleware) *securityEventRouter {
+	return &securityEventRouter{h, auth}
+}
+
+func (r *securityEventRouter) register(rg *gin.RouterGroup) {
+	rg.GET("", r.auth.Authenticate(), r.h.GetAll)
+}
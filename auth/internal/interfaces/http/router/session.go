@@ -0,0 +1,24 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/handlers"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/middlewares"
+)
+
+type sessionRouter struct {
+	h    *handlers.SessionHandler
+	auth *middlewares.AuthMiddleware
+}
+
+func newSessionRouter(h *handlers.SessionHandler, auth *middlewares.AuthMiddleware) *sessionRouter {
+	return &sessionRouter{h, auth}
+}
+
+func (r *sessionRouter) register(rg *gin.RouterGroup) {
+	rg.GET("", r.auth.Authenticate(), r.h.GetAll)
+
+	rg.POST("/revoke-others", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.h.RevokeOthers)
+
+	rg.DELETE("/:session_id", r.auth.Authenticate(), r.auth.DenyImpersonation(), r.h.Revoke)
+}
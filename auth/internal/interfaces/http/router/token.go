@@ -0,0 +1,22 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/handlers"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/interfaces/http/middlewares"
+)
+
+type tokenRouter struct {
+	h     *handlers.ServiceAccountHandler
+	rl    *middlewares.RateLimitMiddleware
+	rlCfg *configs.RateLimit
+}
+
+func newTokenRouter(h *handlers.ServiceAccountHandler, rl *middlewares.RateLimitMiddleware, rlCfg *configs.RateLimit) *tokenRouter {
+	return &tokenRouter{h, rl, rlCfg}
+}
+
+func (r *tokenRouter) register(rg *gin.RouterGroup) {
+	rg.POST("", r.rl.Limit("client_token", r.rlCfg.ClientToken), r.h.IssueToken)
+}
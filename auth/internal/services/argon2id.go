@@ -0,0 +1,117 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"golang.org/x/crypto/argon2"
+)
+
+const argon2IDPrefix string = "$argon2id$"
+
+type Argon2IDService struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	saltLength  uint32
+	keyLength   uint32
+}
+
+type argon2IDHash struct {
+	version     int
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func NewArgon2IDService(cfg *configs.Auth) *Argon2IDService {
+	return &Argon2IDService{
+		memory:      cfg.Argon2ID.Memory,
+		iterations:  cfg.Argon2ID.Iterations,
+		parallelism: cfg.Argon2ID.Parallelism,
+		saltLength:  cfg.Argon2ID.SaltLength,
+		keyLength:   cfg.Argon2ID.KeyLength,
+	}
+}
+
+// PHC string format: $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+func (s *Argon2IDService) Hash(password string) (string, error) {
+	salt := make([]byte, s.saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, s.iterations, s.memory, s.parallelism, s.keyLength)
+
+	return fmt.Sprintf(
+		"%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2IDPrefix, argon2.Version, s.memory, s.iterations, s.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (s *Argon2IDService) Validate(hashedPassword, password string) error {
+	hash, err := s.decode(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	// checked against the params the hash was made with, not the current ones
+	key := argon2.IDKey([]byte(password), hash.salt, hash.iterations, hash.memory, hash.parallelism, uint32(len(hash.key)))
+	if subtle.ConstantTimeCompare(key, hash.key) != 1 {
+		return ce.ErrPasswordMismatch
+	}
+	return nil
+}
+
+func (s *Argon2IDService) NeedsRehash(hashedPassword string) bool {
+	hash, err := s.decode(hashedPassword)
+	if err != nil {
+		return true
+	}
+	return hash.version != argon2.Version ||
+		hash.memory != s.memory ||
+		hash.iterations != s.iterations ||
+		hash.parallelism != s.parallelism ||
+		uint32(len(hash.salt)) != s.saltLength ||
+		uint32(len(hash.key)) != s.keyLength
+}
+
+func (s *Argon2IDService) Owns(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, argon2IDPrefix)
+}
+
+func (s *Argon2IDService) decode(hashedPassword string) (*argon2IDHash, error) {
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, ce.ErrPasswordHashUnknown
+	}
+
+	var hash argon2IDHash
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &hash.version); err != nil {
+		return nil, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &hash.memory, &hash.iterations, &hash.parallelism)
+	if err != nil {
+		return nil, fmt.Errorf("invalid argon2id params: %w", err)
+	}
+
+	hash.salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	hash.key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, fmt.Errorf("invalid argon2id key: %w", err)
+	}
+
+	return &hash, nil
+}
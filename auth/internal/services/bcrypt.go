@@ -1,6 +1,10 @@
 package services
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 type BCryptService struct {
 	cost int
@@ -21,3 +25,15 @@ func (s *BCryptService) Hash(password string) (string, error) {
 func (s *BCryptService) Validate(hashedPassword, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
+
+func (s *BCryptService) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	return err != nil || cost != s.cost
+}
+
+// bcrypt hashes are modular crypt strings, which the PHC format grew out of
+func (s *BCryptService) Owns(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, "$2a$") ||
+		strings.HasPrefix(hashedPassword, "$2b$") ||
+		strings.HasPrefix(hashedPassword, "$2y$")
+}
@@ -0,0 +1,58 @@
+package breach
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type apiSource struct {
+	url    string
+	client *http.Client
+}
+
+func NewAPISource(url string, timeout time.Duration) Source {
+	return &apiSource{
+		url:    strings.TrimSuffix(url, "/") + "/",
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *apiSource) Range(ctx context.Context, prefix string) ([]string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+prefix, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// padded responses keep the response size from hinting at the prefix
+	request.Header.Set("Add-Padding", "true")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected range api status %d", response.StatusCode)
+	}
+
+	suffixes := make([]string, 0)
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		// SUFFIX:COUNT, padding entries have a count of 0
+		suffix, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || count == "0" {
+			continue
+		}
+		suffixes = append(suffixes, strings.ToUpper(suffix))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return suffixes, nil
+}
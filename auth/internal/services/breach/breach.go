@@ -0,0 +1,34 @@
+package breach
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"slices"
+	"strings"
+)
+
+// a source only ever sees the first 5 characters of a password hash (k-anonymity)
+type Source interface {
+	Range(ctx context.Context, prefix string) (suffixes []string, err error)
+}
+
+type Checker struct {
+	source Source
+}
+
+func NewChecker(source Source) *Checker {
+	return &Checker{source}
+}
+
+func (c *Checker) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := c.source.Range(ctx, hash[:5])
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(suffixes, hash[5:]), nil
+}
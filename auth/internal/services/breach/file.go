@@ -0,0 +1,48 @@
+package breach
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// an offline stand-in for the range api, read from a file of SHA-1 hashes (optionally HASH:COUNT)
+type fileSource struct {
+	ranges map[string][]string
+}
+
+func NewFileSource(path string) (Source, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ranges := make(map[string][]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(line, ":")
+		if len(hash) != 40 {
+			return nil, fmt.Errorf("invalid hash %q in breached password file", hash)
+		}
+
+		hash = strings.ToUpper(hash)
+		ranges[hash[:5]] = append(ranges[hash[:5]], hash[5:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &fileSource{ranges}, nil
+}
+
+func (s *fileSource) Range(ctx context.Context, prefix string) ([]string, error) {
+	return s.ranges[prefix], nil
+}
@@ -24,19 +24,27 @@ func (p *Producer) Publish(ctx context.Context, topic, key string, event proto.M
 		return err
 	}
 
-	traceID := trace.SpanFromContext(ctx).SpanContext().TraceID().String()
-	requestID := fmt.Sprintf("%s", ctx.Value(constants.CtxKeyRequestID))
+	traceID, correlationID := TraceHeaders(ctx)
+	return p.PublishEncoded(ctx, topic, key, bytes, traceID, correlationID)
+}
 
+func (p *Producer) PublishEncoded(ctx context.Context, topic, key string, value []byte, traceID, correlationID string) error {
 	message := kafka.Message{
 		Topic: topic,
 		Key:   []byte(key),
-		Value: bytes,
+		Value: value,
 		Headers: []kafka.Header{
 			{Key: "trace_id", Value: []byte(traceID)},
-			{Key: "correlation_id", Value: []byte(requestID)},
+			{Key: "correlation_id", Value: []byte(correlationID)},
 			{Key: "content_type", Value: []byte("application/x-protobuf")},
 		},
 	}
 
 	return p.producer.WriteMessages(ctx, message)
 }
+
+func TraceHeaders(ctx context.Context) (traceID, correlationID string) {
+	traceID = trace.SpanFromContext(ctx).SpanContext().TraceID().String()
+	correlationID = fmt.Sprintf("%s", ctx.Value(constants.CtxKeyRequestID))
+	return traceID, correlationID
+}
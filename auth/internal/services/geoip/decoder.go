@@ -0,0 +1,218 @@
+package geoip
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// data section field types, see https://maxmind.github.io/MaxMind-DB/
+const (
+	typeExtended uint = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat
+)
+
+// nested maps and arrays in real databases stay far below this
+const maxDepth int = 32
+
+var errTruncated = errors.New("unexpected end of data")
+
+type decoder struct {
+	buffer []byte
+}
+
+func newDecoder(buffer []byte) *decoder {
+	return &decoder{buffer}
+}
+
+// returns the decoded value and the offset right after it
+func (d *decoder) decode(offset uint) (any, uint, error) {
+	return d.decodeAt(offset, 0)
+}
+
+func (d *decoder) decodeAt(offset uint, depth int) (any, uint, error) {
+	if depth > maxDepth {
+		return nil, 0, errors.New("data nested too deep")
+	}
+
+	kind, size, offset, err := d.readControl(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if kind == typePointer {
+		pointer, next, err := d.readPointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		// the value is read from where the pointer leads, decoding continues after the pointer
+		value, _, err := d.decodeAt(pointer, depth+1)
+		return value, next, err
+	}
+
+	switch kind {
+	case typeMap:
+		return d.decodeMap(size, offset, depth)
+	case typeArray:
+		return d.decodeArray(size, offset, depth)
+	case typeBool:
+		return size != 0, offset, nil
+	}
+
+	if offset+size > uint(len(d.buffer)) {
+		return nil, 0, errTruncated
+	}
+	data, next := d.buffer[offset:offset+size], offset+size
+
+	switch kind {
+	case typeString:
+		return string(data), next, nil
+	case typeBytes:
+		return append([]byte(nil), data...), next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid double size %d", size)
+		}
+		return math.Float64frombits(uint64(readUint(data))), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid float size %d", size)
+		}
+		return float64(math.Float32frombits(uint32(readUint(data)))), next, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("invalid unsigned integer size %d", size)
+		}
+		return uint64(readUint(data)), next, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("invalid signed integer size %d", size)
+		}
+		return int64(int32(uint32(readUint(data)))), next, nil
+	case typeUint128:
+		// too wide for any field a location needs, kept as raw bytes
+		return append([]byte(nil), data...), next, nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported data type %d", kind)
+	}
+}
+
+func (d *decoder) decodeMap(size, offset uint, depth int) (any, uint, error) {
+	values := make(map[string]any, size)
+	for i := uint(0); i < size; i++ {
+		key, next, err := d.decodeAt(offset, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		name, ok := key.(string)
+		if !ok {
+			return nil, 0, errors.New("map key is not a string")
+		}
+
+		value, next, err := d.decodeAt(next, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		values[name] = value
+		offset = next
+	}
+	return values, offset, nil
+}
+
+func (d *decoder) decodeArray(size, offset uint, depth int) (any, uint, error) {
+	values := make([]any, 0, size)
+	for i := uint(0); i < size; i++ {
+		value, next, err := d.decodeAt(offset, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		values = append(values, value)
+		offset = next
+	}
+	return values, offset, nil
+}
+
+// the control byte holds the type in its top 3 bits and the size in the rest
+func (d *decoder) readControl(offset uint) (uint, uint, uint, error) {
+	if offset >= uint(len(d.buffer)) {
+		return 0, 0, 0, errTruncated
+	}
+	control := d.buffer[offset]
+	offset++
+
+	kind := uint(control >> 5)
+	if kind == typeExtended {
+		if offset >= uint(len(d.buffer)) {
+			return 0, 0, 0, errTruncated
+		}
+		kind = 7 + uint(d.buffer[offset])
+		offset++
+	}
+	if kind == typePointer {
+		// pointers keep their own size encoding, resolved in readPointer
+		return kind, uint(control & 0x1F), offset, nil
+	}
+
+	size := uint(control & 0x1F)
+	if size < 29 {
+		return kind, size, offset, nil
+	}
+
+	extra := size - 28
+	if offset+extra > uint(len(d.buffer)) {
+		return 0, 0, 0, errTruncated
+	}
+	value := readUint(d.buffer[offset : offset+extra])
+	switch extra {
+	case 1:
+		size = 29 + value
+	case 2:
+		size = 285 + value
+	default:
+		size = 65821 + value
+	}
+	return kind, size, offset + extra, nil
+}
+
+func (d *decoder) readPointer(bits, offset uint) (uint, uint, error) {
+	length := (bits >> 3) + 1
+	if offset+length > uint(len(d.buffer)) {
+		return 0, 0, errTruncated
+	}
+	data := d.buffer[offset : offset+length]
+
+	var pointer uint
+	switch length {
+	case 1:
+		pointer = (bits&0x7)<<8 | readUint(data)
+	case 2:
+		pointer = ((bits&0x7)<<16 | readUint(data)) + 2048
+	case 3:
+		pointer = ((bits&0x7)<<24 | readUint(data)) + 526336
+	default:
+		pointer = readUint(data)
+	}
+	return pointer, offset + length, nil
+}
+
+func readUint(data []byte) uint {
+	var value uint
+	for _, b := range data {
+		value = value<<8 | uint(b)
+	}
+	return value
+}
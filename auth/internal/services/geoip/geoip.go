@@ -0,0 +1,195 @@
+package geoip
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+)
+
+// marks the start of the metadata section at the end of a MaxMind DB (.mmdb) file
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// a coarse location, good enough to tell cities apart but not streets
+type Location struct {
+	CountryCode string
+	City        string
+	Latitude    float64
+	Longitude   float64
+}
+
+// an offline lookup over a MaxMind DB file, such as GeoLite2-City
+type Reader struct {
+	buffer     []byte
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	dataStart  uint
+	ipv4Start  uint
+}
+
+func Open(path string) (*Reader, error) {
+	buffer, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	markerAt := bytes.LastIndex(buffer, metadataMarker)
+	if markerAt < 0 {
+		return nil, errors.New("invalid geoip database: metadata not found")
+	}
+
+	metadataStart := uint(markerAt + len(metadataMarker))
+	metadata, _, err := newDecoder(buffer[metadataStart:]).decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid geoip database: %w", err)
+	}
+
+	fields, ok := metadata.(map[string]any)
+	if !ok {
+		return nil, errors.New("invalid geoip database: malformed metadata")
+	}
+	nodeCount, ok1 := fields["node_count"].(uint64)
+	recordSize, ok2 := fields["record_size"].(uint64)
+	ipVersion, ok3 := fields["ip_version"].(uint64)
+	if !ok1 || !ok2 || !ok3 {
+		return nil, errors.New("invalid geoip database: malformed metadata")
+	}
+	if recordSize != 24 && recordSize != 28 && recordSize != 32 {
+		return nil, fmt.Errorf("invalid geoip database: unsupported record size %d", recordSize)
+	}
+
+	// the search tree is followed by 16 zero bytes, then the data section
+	treeSize := uint(nodeCount) * uint(recordSize) / 4
+	if treeSize+16 > metadataStart {
+		return nil, errors.New("invalid geoip database: truncated search tree")
+	}
+
+	r := &Reader{
+		buffer:     buffer[:markerAt],
+		nodeCount:  uint(nodeCount),
+		recordSize: uint(recordSize),
+		ipVersion:  uint(ipVersion),
+		dataStart:  treeSize + 16,
+	}
+
+	// ipv4 addresses live under ::/96 of an ipv6 tree
+	if r.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < r.nodeCount; i++ {
+			node = r.readNode(node, 0)
+		}
+		r.ipv4Start = node
+	}
+
+	return r, nil
+}
+
+// returns nil when the address is not in the database (e.g. private ranges)
+func (r *Reader) Lookup(ip net.IP) (*Location, error) {
+	if ip == nil {
+		return nil, errors.New("invalid ip address")
+	}
+
+	address, node := ip.To4(), uint(0)
+	if address != nil {
+		node = r.ipv4Start
+	} else if r.ipVersion == 4 {
+		// an ipv6 address cannot be found in an ipv4 only database
+		return nil, nil
+	} else {
+		address = ip.To16()
+	}
+
+	bitCount := uint(len(address) * 8)
+	for i := uint(0); i < bitCount && node < r.nodeCount; i++ {
+		bit := uint(address[i>>3]>>(7-(i&7))) & 1
+		node = r.readNode(node, bit)
+	}
+	if node == r.nodeCount {
+		return nil, nil
+	}
+	if node < r.nodeCount {
+		return nil, errors.New("invalid geoip database: search tree too deep")
+	}
+
+	offset := node - r.nodeCount - 16
+	record, _, err := newDecoder(r.buffer[r.dataStart:]).decode(offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode geoip record: %w", err)
+	}
+
+	return toLocation(record), nil
+}
+
+func (r *Reader) readNode(node, bit uint) uint {
+	b := r.buffer[node*r.recordSize/4:]
+	switch r.recordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		b = b[bit*4:]
+		return uint(b[0])<<24 | uint(b[1])<<16 | uint(b[2])<<8 | uint(b[3])
+	}
+}
+
+func toLocation(record any) *Location {
+	fields, _ := record.(map[string]any)
+
+	var location Location
+	if country, ok := fields["country"].(map[string]any); ok {
+		location.CountryCode, _ = country["iso_code"].(string)
+	}
+	if city, ok := fields["city"].(map[string]any); ok {
+		if names, ok := city["names"].(map[string]any); ok {
+			location.City, _ = names["en"].(string)
+		}
+	}
+
+	coordinates, ok := fields["location"].(map[string]any)
+	if !ok {
+		// country level only, too coarse to measure travel with
+		return &location
+	}
+	location.Latitude, _ = coordinates["latitude"].(float64)
+	location.Longitude, _ = coordinates["longitude"].(float64)
+	return &location
+}
+
+func (l *Location) HasCoordinates() bool {
+	return l != nil && (l.Latitude != 0 || l.Longitude != 0)
+}
+
+func (l *Location) String() string {
+	if l == nil {
+		return ""
+	}
+	if l.City == "" {
+		return l.CountryCode
+	}
+	if l.CountryCode == "" {
+		return l.City
+	}
+	return l.City + ", " + l.CountryCode
+}
+
+// great-circle distance in kilometers
+func Distance(a, b *Location) float64 {
+	const earthRadius = 6371.0
+
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+	dLat := toRadians(b.Latitude - a.Latitude)
+	dLon := toRadians(b.Longitude - a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(a.Latitude))*math.Cos(toRadians(b.Latitude))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
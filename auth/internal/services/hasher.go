@@ -0,0 +1,54 @@
+package services
+
+import "github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+
+type PasswordHasher interface {
+	Hash(password string) (hashedPassword string, err error)
+	Validate(hashedPassword, password string) (err error)
+	NeedsRehash(hashedPassword string) (needsRehash bool)
+	Owns(hashedPassword string) (owns bool)
+}
+
+// hashes with the current algorithm, but still validates whatever an older one produced
+type HasherService struct {
+	current PasswordHasher
+	hashers []PasswordHasher
+}
+
+func NewHasherService(current PasswordHasher, legacy ...PasswordHasher) *HasherService {
+	return &HasherService{current, append([]PasswordHasher{current}, legacy...)}
+}
+
+func (s *HasherService) Hash(password string) (string, error) {
+	return s.current.Hash(password)
+}
+
+func (s *HasherService) Validate(hashedPassword, password string) error {
+	hasher, ok := s.owner(hashedPassword)
+	if !ok {
+		return ce.ErrPasswordHashUnknown
+	}
+	return hasher.Validate(hashedPassword, password)
+}
+
+func (s *HasherService) NeedsRehash(hashedPassword string) bool {
+	hasher, ok := s.owner(hashedPassword)
+	if !ok {
+		return true
+	}
+	return hasher != s.current || hasher.NeedsRehash(hashedPassword)
+}
+
+func (s *HasherService) Owns(hashedPassword string) bool {
+	_, ok := s.owner(hashedPassword)
+	return ok
+}
+
+func (s *HasherService) owner(hashedPassword string) (PasswordHasher, bool) {
+	for _, hasher := range s.hashers {
+		if hasher.Owns(hashedPassword) {
+			return hasher, true
+		}
+	}
+	return nil, false
+}
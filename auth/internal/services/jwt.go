@@ -1,45 +1,117 @@
 package services
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"math/big"
 	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/ritchieridanko/apotekly-api/auth/configs"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
 	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/shared/utils"
 )
 
 type JWTService struct {
-	secret    string
 	issuer    string
 	audiences []string
 	duration  time.Duration
+
+	mu      sync.RWMutex
+	current *jwtKey
+	keys    map[string]*jwtKey
+}
+
+type jwtKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
 }
 
 func NewJWTService(cfg *configs.Auth) *JWTService {
-	return &JWTService{cfg.JWT.Secret, cfg.JWT.Issuer, cfg.JWT.Audiences, cfg.JWT.Duration}
+	return &JWTService{
+		issuer:    cfg.JWT.Issuer,
+		audiences: cfg.JWT.Audiences,
+		duration:  cfg.JWT.Duration,
+		keys:      make(map[string]*jwtKey),
+	}
 }
 
-func (s *JWTService) Create(authID int64, roleID int16, isVerified bool) (string, error) {
-	now := time.Now().UTC()
+func (s *JWTService) Create(auth *entities.Auth) (*entities.AccessToken, error) {
+	return s.sign(userClaim(auth, nil), s.duration)
+}
+
+// the token is issued to the admin but acts as the account, marked by the act claim
+func (s *JWTService) CreateImpersonation(auth *entities.Auth, adminID int64, duration time.Duration) (*entities.AccessToken, error) {
+	return s.sign(userClaim(auth, &entities.Actor{AuthID: adminID}), duration)
+}
 
+// service accounts are no one's account: the subject is the client id and access comes from scopes only
+func (s *JWTService) CreateService(clientID string, scopes []string, duration time.Duration) (*entities.AccessToken, error) {
 	claim := entities.Claim{
-		AuthID:     authID,
-		RoleID:     roleID,
-		IsVerified: isVerified,
+		SubjectType: constants.SubjectTypeService,
+		Scope:       strings.Join(scopes, " "),
 		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    s.issuer,
-			Subject:   fmt.Sprintf("%d", authID),
-			Audience:  jwt.ClaimStrings(s.audiences),
-			IssuedAt:  &jwt.NumericDate{Time: now},
-			ExpiresAt: &jwt.NumericDate{Time: now.Add(s.duration)},
+			Subject: clientID,
 		},
 	}
+	return s.sign(claim, duration)
+}
+
+func userClaim(auth *entities.Auth, act *entities.Actor) entities.Claim {
+	return entities.Claim{
+		AuthID:      auth.ID,
+		RoleID:      auth.RoleID,
+		IsVerified:  auth.IsVerified,
+		IsApproved:  auth.IsApproved,
+		SubjectType: constants.SubjectTypeUser,
+		Act:         act,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: fmt.Sprintf("%d", auth.ID),
+		},
+	}
+}
+
+func (s *JWTService) sign(claim entities.Claim, duration time.Duration) (*entities.AccessToken, error) {
+	s.mu.RLock()
+	key := s.current
+	s.mu.RUnlock()
+
+	if key == nil {
+		return nil, ce.ErrSigningKeyNotFound
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(duration)
+	jti := utils.NewUUID().String()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claim)
-	return token.SignedString([]byte(s.secret))
+	claim.Issuer = s.issuer
+	claim.Audience = jwt.ClaimStrings(s.audiences)
+	claim.IssuedAt = &jwt.NumericDate{Time: now}
+	claim.ExpiresAt = &jwt.NumericDate{Time: expiresAt}
+	claim.ID = jti
+
+	token := jwt.NewWithClaims(key.method, claim)
+	token.Header["kid"] = key.kid
+
+	signed, err := token.SignedString(key.private)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.AccessToken{Token: signed, ID: jti, ExpiresAt: expiresAt}, nil
 }
 
 func (s *JWTService) Parse(tokenString string) (*entities.Claim, error) {
@@ -47,8 +119,18 @@ func (s *JWTService) Parse(tokenString string) (*entities.Claim, error) {
 		tokenString,
 		&entities.Claim{},
 		func(t *jwt.Token) (interface{}, error) {
-			return []byte(s.secret), nil
+			kid, _ := t.Header["kid"].(string)
+
+			s.mu.RLock()
+			key, ok := s.keys[kid]
+			s.mu.RUnlock()
+
+			if !ok || key.method.Alg() != t.Method.Alg() {
+				return nil, ce.ErrSigningKeyNotFound
+			}
+			return key.private.Public(), nil
 		},
+		jwt.WithValidMethods([]string{constants.KeyAlgorithmEdDSA, constants.KeyAlgorithmRS256}),
 	)
 	if err != nil {
 		return nil, err
@@ -65,3 +147,123 @@ func (s *JWTService) Parse(tokenString string) (*entities.Claim, error) {
 func (s *JWTService) ValidateAudience(appName string) bool {
 	return slices.Contains(s.audiences, appName)
 }
+
+func (s *JWTService) GenerateKey(algorithm string) (string, error) {
+	var private crypto.Signer
+	switch algorithm {
+	case constants.KeyAlgorithmEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return "", err
+		}
+		private = key
+	case constants.KeyAlgorithmRS256:
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return "", err
+		}
+		private = key
+	default:
+		return "", fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return "", err
+	}
+
+	block := pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	return string(pem.EncodeToMemory(&block)), nil
+}
+
+func (s *JWTService) SetKeys(keys []entities.SigningKey, currentKID string) error {
+	parsed := make(map[string]*jwtKey, len(keys))
+	for _, key := range keys {
+		k, err := s.parseKey(key)
+		if err != nil {
+			return fmt.Errorf("invalid signing key %s: %w", key.KID, err)
+		}
+		parsed[key.KID] = k
+	}
+
+	current, ok := parsed[currentKID]
+	if !ok {
+		return ce.ErrSigningKeyNotFound
+	}
+
+	s.mu.Lock()
+	s.current = current
+	s.keys = parsed
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *JWTService) JWKS() []entities.JWK {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jwks := make([]entities.JWK, 0, len(s.keys))
+	for _, key := range s.keys {
+		jwk := entities.JWK{
+			Algorithm: key.method.Alg(),
+			Use:       "sig",
+			KeyID:     key.kid,
+		}
+
+		switch public := key.private.Public().(type) {
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.Modulus = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		default:
+			continue
+		}
+
+		jwks = append(jwks, jwk)
+	}
+
+	// keep the output stable across calls
+	slices.SortFunc(jwks, func(a, b entities.JWK) int {
+		if a.KeyID < b.KeyID {
+			return -1
+		}
+		if a.KeyID > b.KeyID {
+			return 1
+		}
+		return 0
+	})
+
+	return jwks
+}
+
+func (s *JWTService) parseKey(key entities.SigningKey) (*jwtKey, error) {
+	block, _ := pem.Decode([]byte(key.PrivateKey))
+	if block == nil {
+		return nil, errors.New("failed to decode pem block")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch private := parsed.(type) {
+	case ed25519.PrivateKey:
+		if key.Algorithm != constants.KeyAlgorithmEdDSA {
+			return nil, errors.New("algorithm does not match key type")
+		}
+		return &jwtKey{key.KID, jwt.SigningMethodEdDSA, private}, nil
+	case *rsa.PrivateKey:
+		if key.Algorithm != constants.KeyAlgorithmRS256 {
+			return nil, errors.New("algorithm does not match key type")
+		}
+		return &jwtKey{key.KID, jwt.SigningMethodRS256, private}, nil
+	default:
+		return nil, errors.New("unsupported key type")
+	}
+}
@@ -1,27 +1,54 @@
 package oauth
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/ritchieridanko/apotekly-api/auth/configs"
-	"github.com/ritchieridanko/apotekly-api/auth/internal/services/oauth/google"
-	"github.com/ritchieridanko/apotekly-api/auth/internal/services/oauth/microsoft"
-	"golang.org/x/oauth2"
 )
 
 type OAuth struct {
-	google    *oauth2.Config
-	microsoft *oauth2.Config
+	providers map[string]*Provider
+	byID      map[int16]*Provider
 }
 
-func Initialize(cfg *configs.OAuth) *OAuth {
-	g := google.NewProvider(cfg)
-	m := microsoft.NewProvider(cfg)
-	return &OAuth{google: g, microsoft: m}
+func Initialize(cfg *configs.OAuth) (*OAuth, error) {
+	o := OAuth{
+		providers: make(map[string]*Provider),
+		byID:      make(map[int16]*Provider),
+	}
+
+	for name, pCfg := range cfg.Providers {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if pCfg.ClientID == "" {
+			// listed but not configured for this environment
+			continue
+		}
+		if pCfg.ID <= 0 {
+			return nil, fmt.Errorf("oauth provider %q needs a positive id", name)
+		}
+		if pCfg.DiscoveryURL == "" {
+			return nil, fmt.Errorf("oauth provider %q needs a discovery url", name)
+		}
+		if existing, ok := o.byID[pCfg.ID]; ok {
+			// the id is what linked identities are stored under, it must never be shared
+			return nil, fmt.Errorf("oauth providers %q and %q share id %d", existing.Name(), name, pCfg.ID)
+		}
+
+		p := newProvider(name, pCfg)
+		o.providers[name] = p
+		o.byID[pCfg.ID] = p
+	}
+
+	return &o, nil
 }
 
-func (o *OAuth) Google() *oauth2.Config {
-	return o.google
+func (o *OAuth) Provider(name string) (*Provider, bool) {
+	p, ok := o.providers[strings.ToLower(name)]
+	return p, ok
 }
 
-func (o *OAuth) Microsoft() *oauth2.Config {
-	return o.microsoft
+func (o *OAuth) ProviderByID(id int16) (*Provider, bool) {
+	p, ok := o.byID[id]
+	return p, ok
 }
@@ -0,0 +1,207 @@
+package oauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+	"github.com/ritchieridanko/apotekly-api/auth/internal/entities"
+	"golang.org/x/oauth2"
+)
+
+// microsoft's multi-tenant endpoints publish the issuer with this placeholder
+const tenantPlaceholder string = "{tenantid}"
+
+var (
+	ErrClaimMissing  error = errors.New("required claim missing from id token")
+	ErrIssuerInvalid error = errors.New("id token issuer mismatch")
+	ErrNonceInvalid  error = errors.New("id token nonce mismatch")
+)
+
+type Provider struct {
+	id     int16
+	name   string
+	cfg    configs.OAuthProvider
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	oauth2    *oauth2.Config
+	jwks      *jwks
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func newProvider(name string, cfg configs.OAuthProvider) *Provider {
+	if cfg.Claims.Subject == "" {
+		cfg.Claims.Subject = "sub"
+	}
+	if cfg.Claims.Email == "" {
+		cfg.Claims.Email = "email"
+	}
+	if cfg.Claims.EmailVerified == "" {
+		cfg.Claims.EmailVerified = "email_verified"
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"email", "profile"}
+	}
+	if !slices.Contains(scopes, "openid") {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+	cfg.Scopes = scopes
+
+	return &Provider{
+		id:     cfg.ID,
+		name:   name,
+		cfg:    cfg,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (p *Provider) ID() int16 {
+	return p.id
+}
+
+func (p *Provider) Name() string {
+	return p.name
+}
+
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, opts ...oauth2.AuthCodeOption) (string, error) {
+	cfg, _, err := p.load(ctx)
+	if err != nil {
+		return "", err
+	}
+	return cfg.AuthCodeURL(state, opts...), nil
+}
+
+func (p *Provider) Exchange(ctx context.Context, code string, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+	cfg, _, err := p.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Exchange(ctx, code, opts...)
+}
+
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*entities.OAuth, error) {
+	_, d, err := p.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(
+		rawIDToken, claims,
+		func(t *jwt.Token) (interface{}, error) {
+			return p.jwks.keyfunc(ctx, t)
+		},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := d.Issuer
+	if strings.Contains(issuer, tenantPlaceholder) {
+		tenantID, _ := claims["tid"].(string)
+		issuer = strings.ReplaceAll(issuer, tenantPlaceholder, tenantID)
+	}
+	if iss, _ := claims["iss"].(string); iss == "" || iss != issuer {
+		return nil, ErrIssuerInvalid
+	}
+
+	tokenNonce, _ := claims["nonce"].(string)
+	if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return nil, ErrNonceInvalid
+	}
+
+	subject, _ := claims[p.cfg.Claims.Subject].(string)
+	email, _ := claims[p.cfg.Claims.Email].(string)
+	if subject == "" || email == "" {
+		return nil, fmt.Errorf("%w: %s or %s", ErrClaimMissing, p.cfg.Claims.Subject, p.cfg.Claims.Email)
+	}
+
+	isVerified := p.cfg.TrustEmail
+	switch v := claims[p.cfg.Claims.EmailVerified].(type) {
+	case bool:
+		isVerified = isVerified || v
+	case string:
+		// some issuers send it as a string
+		isVerified = isVerified || v == "true"
+	}
+
+	data := entities.OAuth{
+		Provider:   p.id,
+		UID:        subject,
+		Email:      email,
+		IsVerified: isVerified,
+	}
+
+	return &data, nil
+}
+
+func (p *Provider) load(ctx context.Context) (*oauth2.Config, *discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.oauth2, p.discovery, nil
+	}
+
+	// discovered on first use and kept, so a provider being down never blocks startup
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.DiscoveryURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch %s discovery document: %w", p.name, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed to fetch %s discovery document: response status '%s'", p.name, response.Status)
+	}
+
+	var d discovery
+	if err := json.NewDecoder(response.Body).Decode(&d); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode %s discovery document: %w", p.name, err)
+	}
+	if d.Issuer == "" || d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, nil, fmt.Errorf("incomplete %s discovery document", p.name)
+	}
+
+	p.discovery = &d
+	p.jwks = newJWKS(d.JWKSURI, p.client)
+	p.oauth2 = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.Secret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Scopes:       p.cfg.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  d.AuthorizationEndpoint,
+			TokenURL: d.TokenEndpoint,
+		},
+	}
+
+	return p.oauth2, p.discovery, nil
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+type TOTPService struct {
+	issuer string
+	period uint
+	skew   uint
+}
+
+func NewTOTPService(issuer string) *TOTPService {
+	return &TOTPService{issuer: issuer, period: 30, skew: 1}
+}
+
+func (s *TOTPService) Generate(accountName string) (secret, uri string, err error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.issuer,
+		AccountName: accountName,
+		Period:      s.period,
+		Algorithm:   otp.AlgorithmSHA1,
+		Digits:      otp.DigitsSix,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return key.Secret(), key.URL(), nil
+}
+
+func (s *TOTPService) Validate(code, secret string) bool {
+	valid, err := totp.ValidateCustom(code, secret, time.Now().UTC(), totp.ValidateOpts{
+		Period:    s.period,
+		Skew:      s.skew,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+	return err == nil && valid
+}
+
+func (s *TOTPService) ValidityWindow() time.Duration {
+	// a code stays acceptable for the current period plus the skewed periods around it
+	return time.Duration(s.period*(2*s.skew+1)) * time.Second
+}
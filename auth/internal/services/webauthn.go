@@ -0,0 +1,95 @@
+package services
+
+import (
+	"strconv"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/ritchieridanko/apotekly-api/auth/configs"
+)
+
+type WebAuthnService struct {
+	webauthn *webauthn.WebAuthn
+}
+
+func NewWebAuthnService(cfg *configs.Auth) (*WebAuthnService, error) {
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    cfg.TokenDuration.WebAuthn,
+		TimeoutUVD: cfg.TokenDuration.WebAuthn,
+	}
+
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.WebAuthn.RPID,
+		RPDisplayName: cfg.WebAuthn.RPName,
+		RPOrigins:     cfg.WebAuthn.RPOrigins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebAuthnService{w}, nil
+}
+
+func (s *WebAuthnService) BeginRegistration(user webauthn.User) (*protocol.CredentialCreation, *webauthn.SessionData, error) {
+	// credentials must be discoverable so that login does not require an email
+	return s.webauthn.BeginRegistration(
+		user,
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithExclusions(webauthn.Credentials(user.WebAuthnCredentials()).CredentialDescriptors()),
+	)
+}
+
+func (s *WebAuthnService) FinishRegistration(user webauthn.User, session *webauthn.SessionData, response []byte) (*webauthn.Credential, error) {
+	parsedResponse, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, err
+	}
+	return s.webauthn.CreateCredential(user, *session, parsedResponse)
+}
+
+func (s *WebAuthnService) BeginLogin() (*protocol.CredentialAssertion, *webauthn.SessionData, error) {
+	return s.webauthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+}
+
+func (s *WebAuthnService) FinishLogin(handler webauthn.DiscoverableUserHandler, session *webauthn.SessionData, response []byte) (webauthn.User, *webauthn.Credential, error) {
+	parsedResponse, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.webauthn.ValidatePasskeyLogin(handler, *session, parsedResponse)
+}
+
+type WebAuthnUser struct {
+	authID      int64
+	email       string
+	credentials []webauthn.Credential
+}
+
+func NewWebAuthnUser(authID int64, email string, credentials []webauthn.Credential) *WebAuthnUser {
+	return &WebAuthnUser{authID, email, credentials}
+}
+
+func (u *WebAuthnUser) WebAuthnID() []byte {
+	return []byte(strconv.FormatInt(u.authID, 10))
+}
+
+func (u *WebAuthnUser) WebAuthnName() string {
+	return u.email
+}
+
+func (u *WebAuthnUser) WebAuthnDisplayName() string {
+	return u.email
+}
+
+func (u *WebAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+func (u *WebAuthnUser) AuthID() int64 {
+	return u.authID
+}
@@ -13,14 +13,25 @@ type errCode string
 
 // internal error codes (for logs/debugging)
 const (
+	CodeAccountDeletionNotFound errCode = "ACCOUNT_DELETION_NOT_FOUND_ERROR"
+	CodeAccountDeletionExists   errCode = "ACCOUNT_DELETION_SCHEDULED_ERROR"
+	CodeAccountApproved         errCode = "ACCOUNT_APPROVED_ERROR"
+	CodeAccountNotFound         errCode = "ACCOUNT_NOT_FOUND_ERROR"
+	CodeAccountSuspended        errCode = "ACCOUNT_SUSPENDED_ERROR"
+	CodeAccountSuspendState     errCode = "ACCOUNT_SUSPEND_STATE_ERROR"
+	CodeAdminSelfAction         errCode = "ADMIN_SELF_ACTION_ERROR"
+	CodeAdminTargetForbidden    errCode = "ADMIN_TARGET_FORBIDDEN_ERROR"
 	CodeAuthAudienceNotFound    errCode = "AUTH_AUDIENCE_NOT_FOUND_ERROR"
 	CodeAuthEmailConflict       errCode = "AUTH_EMAIL_CONFLICT_ERROR"
 	CodeAuthLocked              errCode = "AUTH_LOCKED_ERROR"
 	CodeAuthNotFound            errCode = "AUTH_NOT_FOUND_ERROR"
 	CodeAuthNotVerified         errCode = "AUTH_NOT_VERIFIED_ERROR"
+	CodeAuthThrottled           errCode = "AUTH_THROTTLED_ERROR"
 	CodeAuthTokenExpired        errCode = "AUTH_TOKEN_EXPIRED_ERROR"
+	CodeAuthTokenInvalid        errCode = "AUTH_TOKEN_INVALID_ERROR"
 	CodeAuthTokenMalformed      errCode = "AUTH_TOKEN_MALFORMED_ERROR"
 	CodeAuthTokenParsing        errCode = "AUTH_TOKEN_PARSING_ERROR"
+	CodeAuthTokenRevoked        errCode = "AUTH_TOKEN_REVOKED_ERROR"
 	CodeAuthUnauthenticated     errCode = "AUTH_UNAUTHENTICATED_ERROR"
 	CodeAuthVerified            errCode = "AUTH_VERIFIED_ERROR"
 	CodeAuthWrongPassword       errCode = "AUTH_WRONG_PASSWORD_ERROR"
@@ -28,43 +39,110 @@ const (
 	CodeCacheQueryExecution     errCode = "CACHE_QUERY_EXECUTION_ERROR"
 	CodeCacheValueNotFound      errCode = "CACHE_VALUE_NOT_FOUND_ERROR"
 	CodeCacheScriptExecution    errCode = "CACHE_SCRIPT_EXECUTION_ERROR"
+	CodeClientInvalid           errCode = "CLIENT_INVALID_ERROR"
 	CodeContextCookieNotFound   errCode = "CONTEXT_COOKIE_NOT_FOUND_ERROR"
 	CodeContextValueNotFound    errCode = "CONTEXT_VALUE_NOT_FOUND_ERROR"
 	CodeDBDuplicateData         errCode = "DB_DUPLICATE_DATA_ERROR"
 	CodeDBQueryExecution        errCode = "DB_QUERY_EXECUTION_ERROR"
 	CodeDBTransaction           errCode = "DB_TRANSACTION_ERROR"
-	CodeEmailDelivery           errCode = "EMAIL_DELIVERY_ERROR"
-	CodeEmailTemplateParsing    errCode = "EMAIL_TEMPLATE_PARSING_ERROR"
+	CodeDecryptionFailed        errCode = "DECRYPTION_FAILED_ERROR"
+	CodeEncryptionFailed        errCode = "ENCRYPTION_FAILED_ERROR"
 	CodeEventPublishingFailed   errCode = "EVENT_PUBLISHING_FAILED_ERROR"
+	CodeFileOperationFailed     errCode = "FILE_OPERATION_FAILED_ERROR"
+	CodeGrantTypeUnsupported    errCode = "GRANT_TYPE_UNSUPPORTED_ERROR"
+	CodeImpersonationForbidden  errCode = "IMPERSONATION_FORBIDDEN_ERROR"
 	CodeInvalidParams           errCode = "INVALID_PARAMS_ERROR"
 	CodeInvalidPayload          errCode = "INVALID_PAYLOAD_ERROR"
 	CodeInvalidTokenClaim       errCode = "INVALID_TOKEN_CLAIM_ERROR"
 	CodeJWTGenerationFailed     errCode = "JWT_GENERATION_FAILED_ERROR"
+	CodeMagicLinkDeviceMismatch errCode = "MAGIC_LINK_DEVICE_MISMATCH_ERROR"
+	CodeMFAEnabled              errCode = "MFA_ENABLED_ERROR"
+	CodeMFAGenerationFailed     errCode = "MFA_GENERATION_FAILED_ERROR"
+	CodeMFAInvalidCode          errCode = "MFA_INVALID_CODE_ERROR"
+	CodeMFANotEnabled           errCode = "MFA_NOT_ENABLED_ERROR"
+	CodeMFANotEnrolled          errCode = "MFA_NOT_ENROLLED_ERROR"
 	CodeOAuthCodeExchangeFailed errCode = "OAUTH_CODE_EXCHANGE_FAILED_ERROR"
 	CodeOAuthEmailChange        errCode = "OAUTH_EMAIL_CHANGE_ERROR"
-	CodeOAuthGetUserInfoFailed  errCode = "OAUTH_GET_USER_INFO_FAILED_ERROR"
+	CodeOAuthDiscoveryFailed    errCode = "OAUTH_DISCOVERY_FAILED_ERROR"
+	CodeOAuthIDTokenInvalid     errCode = "OAUTH_ID_TOKEN_INVALID_ERROR"
+	CodeOAuthIdentityConflict   errCode = "OAUTH_IDENTITY_CONFLICT_ERROR"
+	CodeOAuthIdentityNotFound   errCode = "OAUTH_IDENTITY_NOT_FOUND_ERROR"
+	CodeOAuthLastLoginMethod    errCode = "OAUTH_LAST_LOGIN_METHOD_ERROR"
+	CodeOAuthMFAEnrollment      errCode = "OAUTH_MFA_ENROLLMENT_ERROR"
+	CodeOAuthNonceMismatch      errCode = "OAUTH_NONCE_MISMATCH_ERROR"
 	CodeOAuthNotVerified        errCode = "OAUTH_NOT_VERIFIED_ERROR"
 	CodeOAuthPasswordChange     errCode = "OAUTH_PASSWORD_CHANGE_ERROR"
+	CodeOAuthPasswordSet        errCode = "OAUTH_PASSWORD_SET_ERROR"
+	CodeOAuthProviderNotFound   errCode = "OAUTH_PROVIDER_NOT_FOUND_ERROR"
+	CodeOAuthRedirectNotAllowed errCode = "OAUTH_REDIRECT_NOT_ALLOWED_ERROR"
 	CodeOAuthRegularExists      errCode = "OAUTH_REGULAR_EXISTS_ERROR"
 	CodeOAuthRegularLogin       errCode = "OAUTH_REGULAR_LOGIN_ERROR"
+	CodeOAuthStateInvalid       errCode = "OAUTH_STATE_INVALID_ERROR"
+	CodeOTPAttemptsExceeded     errCode = "OTP_ATTEMPTS_EXCEEDED_ERROR"
+	CodeOTPInvalid              errCode = "OTP_INVALID_ERROR"
+	CodePasskeyCeremonyFailed   errCode = "PASSKEY_CEREMONY_FAILED_ERROR"
+	CodePasskeyLoginFailed      errCode = "PASSKEY_LOGIN_FAILED_ERROR"
+	CodePasskeyNotFound         errCode = "PASSKEY_NOT_FOUND_ERROR"
+	CodePasskeyRegistration     errCode = "PASSKEY_REGISTRATION_ERROR"
+	CodePasswordBreached        errCode = "PASSWORD_BREACHED_ERROR"
+	CodePasswordContainsEmail   errCode = "PASSWORD_CONTAINS_EMAIL_ERROR"
 	CodePasswordHashingFailed   errCode = "PASSWORD_HASHING_FAILED_ERROR"
+	CodePasswordResetRequired   errCode = "PASSWORD_RESET_REQUIRED_ERROR"
+	CodePasswordReused          errCode = "PASSWORD_REUSED_ERROR"
+	CodePharmacyAppNotFound     errCode = "PHARMACY_APPLICATION_NOT_FOUND_ERROR"
+	CodePharmacyLicenseConflict errCode = "PHARMACY_LICENSE_CONFLICT_ERROR"
+	CodePharmacyLicenseExpired  errCode = "PHARMACY_LICENSE_EXPIRED_ERROR"
+	CodeRateLimitExceeded       errCode = "RATE_LIMIT_EXCEEDED_ERROR"
 	CodeRoleUnauthorized        errCode = "ROLE_UNAUTHORIZED_ERROR"
+	CodeScopeInvalid            errCode = "SCOPE_INVALID_ERROR"
+	CodeScopeUnauthorized       errCode = "SCOPE_UNAUTHORIZED_ERROR"
+	CodeServiceAccountDisabled  errCode = "SERVICE_ACCOUNT_DISABLED_ERROR"
+	CodeServiceAccountNotFound  errCode = "SERVICE_ACCOUNT_NOT_FOUND_ERROR"
 	CodeSessionExpired          errCode = "SESSION_EXPIRED_ERROR"
+	CodeSessionIDNotFound       errCode = "SESSION_ID_NOT_FOUND_ERROR"
 	CodeSessionNotFound         errCode = "SESSION_NOT_FOUND_ERROR"
+	CodeSessionReused           errCode = "SESSION_REUSED_ERROR"
 	CodeSessionRevoked          errCode = "SESSION_REVOKED_ERROR"
+	CodeSigningKeyGeneration    errCode = "SIGNING_KEY_GENERATION_ERROR"
+	CodeSigningKeyInvalid       errCode = "SIGNING_KEY_INVALID_ERROR"
+	CodeStepUpUnavailable       errCode = "STEP_UP_UNAVAILABLE_ERROR"
 	CodeTypeAssertionFailed     errCode = "TYPE_ASSERTION_FAILED_ERROR"
 	CodeTypeConversionFailed    errCode = "TYPE_CONVERSION_FAILED_ERROR"
 )
 
 // external error messages (for end-users)
 const (
-	MsgEmailAlreadyRegistered string = "Email is already registered"
-	MsgInternalServer         string = "Internal server error"
-	MsgInvalidCredentials     string = "Invalid credentials"
-	MsgInvalidParams          string = "Invalid params"
-	MsgInvalidPayload         string = "Invalid payload"
-	MsgInvalidToken           string = "Invalid token"
-	MsgUnauthenticated        string = "Unauthenticated"
+	MsgAccountDeletionNotFound  string = "No account deletion is scheduled"
+	MsgAccountDeletionScheduled string = "Account deletion is already scheduled"
+	MsgAccountNotFound          string = "Account not found"
+	MsgAccountSuspended         string = "Account is suspended"
+	MsgAuthLocked               string = "Account is temporarily locked due to too many failed attempts"
+	MsgAuthThrottled            string = "Too many failed attempts, please try again later"
+	MsgEmailAlreadyRegistered   string = "Email is already registered"
+	MsgImpersonationForbidden   string = "This action is not allowed while impersonating"
+	MsgInternalServer           string = "Internal server error"
+	MsgInvalidClient            string = "Invalid client credentials"
+	MsgInvalidCredentials       string = "Invalid credentials"
+	MsgMagicLinkDeviceMismatch  string = "Open the link on the device that requested it"
+	MsgInvalidMFACode           string = "Invalid authentication code"
+	MsgInvalidOAuthState        string = "Invalid or expired sign-in attempt, please try again"
+	MsgInvalidOTP               string = "Invalid or expired code"
+	MsgInvalidParams            string = "Invalid params"
+	MsgInvalidPayload           string = "Invalid payload"
+	MsgInvalidScope             string = "Invalid scope"
+	MsgInvalidToken             string = "Invalid token"
+	MsgPasskeyNotFound          string = "Passkey not found"
+	MsgPasswordResetRequired    string = "Password has to be reset before signing in"
+	MsgPharmacyAppNotFound      string = "Pharmacy application not found"
+	MsgPharmacyLicenseConflict  string = "License number is already registered"
+	MsgPharmacyLicenseExpired   string = "Pharmacy license has expired"
+	MsgScopeUnauthorized        string = "Insufficient scope"
+	MsgServiceAccountDisabled   string = "Service account is disabled"
+	MsgServiceAccountNotFound   string = "Service account not found"
+	MsgSessionNotFound          string = "Session not found"
+	MsgTooManyRequests          string = "Too many requests, please try again later"
+	MsgUnauthenticated          string = "Unauthenticated"
+	MsgUnsupportedGrantType     string = "Unsupported grant type"
 )
 
 // internal error logs
@@ -76,10 +154,20 @@ var (
 	ErrEmailConflict       error = errors.New("email conflict")
 	ErrInvalidTokenClaim   error = errors.New("invalid token claim")
 	ErrOAuthCodeNotFound   error = errors.New("oauth code not found")
+	ErrOAuthLinkConflict   error = errors.New("oauth identity already linked")
+	ErrOAuthNonceMismatch  error = errors.New("oauth nonce mismatch")
+	ErrOAuthStateMismatch  error = errors.New("oauth state mismatch")
+	ErrPasswordHashUnknown error = errors.New("unknown password hash format")
+	ErrPasswordMismatch    error = errors.New("password does not match hash")
 	ErrSessionExpired      error = errors.New("session expired")
+	ErrSessionReused       error = errors.New("session reused")
 	ErrSessionRevoked      error = errors.New("session revoked")
+	ErrSigningKeyNotFound  error = errors.New("signing key not found")
 	ErrTokenExpired        error = jwt.ErrTokenExpired
 	ErrTokenMalformed      error = jwt.ErrTokenMalformed
+	ErrTokenSignature      error = jwt.ErrTokenSignatureInvalid
+	ErrTokenUnverifiable   error = jwt.ErrTokenUnverifiable
 	ErrTokenNotFound       error = errors.New("token not found")
+	ErrTokenRevoked        error = errors.New("token revoked")
 	ErrTypeAssertionFailed error = errors.New("type assertion failed")
 )
@@ -2,15 +2,17 @@ package ce
 
 import (
 	"net/http"
+	"time"
 
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type Error struct {
-	Code    errCode
-	Message string
-	Err     error
+	Code       errCode
+	Message    string
+	Err        error
+	RetryAfter time.Duration
 }
 
 func NewError(span trace.Span, code errCode, message string, err error) *Error {
@@ -30,35 +32,97 @@ func (e *Error) Error() string {
 	return e.Err.Error()
 }
 
+func (e *Error) WithRetryAfter(duration time.Duration) *Error {
+	e.RetryAfter = duration
+	return e
+}
+
 func (e *Error) HTTPStatus() int {
 	switch e.Code {
-	case CodeAuthVerified, CodeCacheValueNotFound, CodeInvalidParams, CodeInvalidPayload:
+	case
+		CodeAuthVerified,
+		CodeCacheValueNotFound,
+		CodeGrantTypeUnsupported,
+		CodeInvalidParams,
+		CodeInvalidPayload,
+		CodeMFANotEnabled,
+		CodeMFANotEnrolled,
+		CodeOAuthLastLoginMethod,
+		CodeOAuthRedirectNotAllowed,
+		CodeOAuthStateInvalid,
+		CodeOTPInvalid,
+		CodePasskeyRegistration,
+		CodePasswordBreached,
+		CodePasswordContainsEmail,
+		CodePasswordReused,
+		CodePharmacyLicenseExpired,
+		CodeScopeInvalid:
 		return http.StatusBadRequest
 	case
 		CodeAuthAudienceNotFound,
 		CodeAuthNotFound,
 		CodeAuthTokenExpired,
+		CodeAuthTokenInvalid,
 		CodeAuthTokenMalformed,
+		CodeAuthTokenRevoked,
 		CodeAuthUnauthenticated,
 		CodeAuthWrongPassword,
+		CodeClientInvalid,
 		CodeContextCookieNotFound,
 		CodeInvalidTokenClaim,
+		CodeMFAInvalidCode,
+		CodeOAuthIDTokenInvalid,
+		CodeOAuthNonceMismatch,
+		CodePasskeyLoginFailed,
 		CodeRoleUnauthorized,
 		CodeSessionExpired,
 		CodeSessionNotFound,
+		CodeSessionReused,
 		CodeSessionRevoked:
 		return http.StatusUnauthorized
 	case
+		CodeAccountSuspended,
+		CodeAdminSelfAction,
+		CodeAdminTargetForbidden,
 		CodeAuthNotVerified,
+		CodeImpersonationForbidden,
+		CodeMagicLinkDeviceMismatch,
 		CodeOAuthEmailChange,
+		CodeOAuthMFAEnrollment,
 		CodeOAuthNotVerified,
 		CodeOAuthPasswordChange,
-		CodeOAuthRegularLogin:
+		CodeOAuthRegularLogin,
+		CodePasswordResetRequired,
+		CodeScopeUnauthorized,
+		CodeStepUpUnavailable:
 		return http.StatusForbidden
-	case CodeAuthEmailConflict, CodeDBDuplicateData, CodeOAuthRegularExists:
+	case
+		CodeAccountDeletionNotFound,
+		CodeAccountNotFound,
+		CodeOAuthIdentityNotFound,
+		CodeOAuthProviderNotFound,
+		CodePasskeyNotFound,
+		CodePharmacyAppNotFound,
+		CodeServiceAccountNotFound,
+		CodeSessionIDNotFound:
+		return http.StatusNotFound
+	case
+		CodeAccountApproved,
+		CodeAccountDeletionExists,
+		CodeAccountSuspendState,
+		CodeAuthEmailConflict,
+		CodeDBDuplicateData,
+		CodeMFAEnabled,
+		CodeOAuthIdentityConflict,
+		CodeOAuthPasswordSet,
+		CodeOAuthRegularExists,
+		CodePharmacyLicenseConflict,
+		CodeServiceAccountDisabled:
 		return http.StatusConflict
 	case CodeAuthLocked:
 		return http.StatusLocked
+	case CodeAuthThrottled, CodeOTPAttemptsExceeded, CodeRateLimitExceeded:
+		return http.StatusTooManyRequests
 	case
 		CodeAuthTokenParsing,
 		CodeCacheBackoffWait,
@@ -67,13 +131,18 @@ func (e *Error) HTTPStatus() int {
 		CodeContextValueNotFound,
 		CodeDBQueryExecution,
 		CodeDBTransaction,
-		CodeEmailDelivery,
-		CodeEmailTemplateParsing,
+		CodeDecryptionFailed,
+		CodeEncryptionFailed,
 		CodeEventPublishingFailed,
+		CodeFileOperationFailed,
 		CodeJWTGenerationFailed,
+		CodeMFAGenerationFailed,
 		CodeOAuthCodeExchangeFailed,
-		CodeOAuthGetUserInfoFailed,
+		CodeOAuthDiscoveryFailed,
+		CodePasskeyCeremonyFailed,
 		CodePasswordHashingFailed,
+		CodeSigningKeyGeneration,
+		CodeSigningKeyInvalid,
 		CodeTypeAssertionFailed,
 		CodeTypeConversionFailed:
 		return http.StatusInternalServerError
@@ -0,0 +1,23 @@
+package constants
+
+const (
+	AdminActionAccountsSearched            string = "ACCOUNTS_SEARCHED"
+	AdminActionAccountViewed               string = "ACCOUNT_VIEWED"
+	AdminActionSessionsViewed              string = "SESSIONS_VIEWED"
+	AdminActionAuthEventsSearched          string = "AUTH_EVENTS_SEARCHED"
+	AdminActionAccountVerified             string = "ACCOUNT_VERIFIED"
+	AdminActionAccountApproved             string = "ACCOUNT_APPROVED"
+	AdminActionAccountSuspended            string = "ACCOUNT_SUSPENDED"
+	AdminActionAccountUnsuspended          string = "ACCOUNT_UNSUSPENDED"
+	AdminActionPasswordResetForced         string = "PASSWORD_RESET_FORCED"
+	AdminActionSessionsRevoked             string = "SESSIONS_REVOKED"
+	AdminActionImpersonated                string = "ACCOUNT_IMPERSONATED"
+	AdminActionServiceAccountsViewed       string = "SERVICE_ACCOUNTS_VIEWED"
+	AdminActionServiceAccountCreated       string = "SERVICE_ACCOUNT_CREATED"
+	AdminActionServiceAccountSecretRotated string = "SERVICE_ACCOUNT_SECRET_ROTATED"
+	AdminActionServiceAccountDisabled      string = "SERVICE_ACCOUNT_DISABLED"
+	AdminActionAdminPromoted               string = "ADMIN_PROMOTED"
+)
+
+// stands in for the session an impersonation token is tracked under, it never matches a real one
+const ImpersonationSessionToken string = "impersonation"
@@ -1,9 +1,25 @@
 package constants
 
 const (
+	CachePrefixAccessToken      string = "at"
+	CachePrefixClientToken      string = "cat"
+	CachePrefixDenylist         string = "dl"
 	CachePrefixEmailChange      string = "emch"
+	CachePrefixOAuthState       string = "oast"
 	CachePrefixOAuthStore       string = "oas"
+	CachePrefixOTP              string = "otp"
 	CachePrefixEmailReservation string = "emres"
+	CachePrefixLockout          string = "lkl"
+	CachePrefixLockoutDelay     string = "lkd"
+	CachePrefixLockoutFailure   string = "lkf"
+	CachePrefixMagicLink        string = "mglk"
+	CachePrefixMFAPending       string = "mfap"
+	CachePrefixMFAUsedCode      string = "mfau"
+	CachePrefixPasskeyLogin     string = "pklog"
+	CachePrefixPasskeyRegister  string = "pkreg"
+	CachePrefixRateLimit        string = "rl"
 	CachePrefixReset            string = "reset"
+	CachePrefixSignInReport     string = "sgnr"
+	CachePrefixUnlock           string = "unlock"
 	CachePrefixVerification     string = "emver"
 )
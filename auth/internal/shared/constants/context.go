@@ -3,8 +3,12 @@ package constants
 type ctxKey string
 
 const (
-	CtxKeyAuthID     ctxKey = "auth-id"
-	CtxKeyIsVerified ctxKey = "is-verified"
-	CtxKeyRequestID  ctxKey = "request-id"
-	CtxKeyRoleID     ctxKey = "role-id"
+	CtxKeyAuthID         ctxKey = "auth-id"
+	CtxKeyImpersonatorID ctxKey = "impersonator-id"
+	CtxKeyIPAddress      ctxKey = "ip-address"
+	CtxKeyIsVerified     ctxKey = "is-verified"
+	CtxKeyLocale         ctxKey = "locale"
+	CtxKeyRequestID      ctxKey = "request-id"
+	CtxKeyRoleID         ctxKey = "role-id"
+	CtxKeyUserAgent      ctxKey = "user-agent"
 )
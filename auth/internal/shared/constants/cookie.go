@@ -1,5 +1,7 @@
 package constants
 
 const (
-	CookieKeySessionToken string = "session_cookie"
+	CookieKeyMagicLinkDevice string = "magic_link_device"
+	CookieKeyOAuthState      string = "oauth_state"
+	CookieKeySessionToken    string = "session_cookie"
 )
@@ -1,5 +1,62 @@
 package constants
 
+const TopicAuthEvents string = "auth-events"
+
+const (
+	EventTypeAuthRegistered         string = "AUTH_REGISTERED"
+	EventTypeSessionReuseDetected   string = "SESSION_REUSE_DETECTED"
+	EventTypeVerificationRequested  string = "VERIFICATION_REQUESTED"
+	EventTypeEmailChangeRequested   string = "EMAIL_CHANGE_REQUESTED"
+	EventTypeEmailChanged           string = "EMAIL_CHANGED"
+	EventTypePasswordResetRequested string = "PASSWORD_RESET_REQUESTED"
+	EventTypePasswordChanged        string = "PASSWORD_CHANGED"
+	EventTypeAccountVerified        string = "ACCOUNT_VERIFIED"
+	EventTypeUnlockRequested        string = "UNLOCK_REQUESTED"
+	EventTypeMagicLinkRequested     string = "MAGIC_LINK_REQUESTED"
+	EventTypeLoginCodeRequested     string = "LOGIN_CODE_REQUESTED"
+	EventTypeSessionCreated         string = "SESSION_CREATED"
+	EventTypeSessionRevoked         string = "SESSION_REVOKED"
+	EventTypeNewSignInDetected      string = "NEW_SIGN_IN_DETECTED"
+	EventTypeOAuthLinked            string = "OAUTH_LINKED"
+	EventTypeDeletionScheduled      string = "ACCOUNT_DELETION_SCHEDULED"
+	EventTypeDeletionCancelled      string = "ACCOUNT_DELETION_CANCELLED"
+	EventTypeAccountDeleted         string = "ACCOUNT_DELETED"
+)
+
+// recorded in the auth event log only, never published
+const (
+	EventTypeLoginSucceeded    string = "LOGIN_SUCCEEDED"
+	EventTypeLoginFailed       string = "LOGIN_FAILED"
+	EventTypeLogout            string = "LOGOUT"
+	EventTypeSessionRefreshed  string = "SESSION_REFRESHED"
+	EventTypeOAuthUnlinked     string = "OAUTH_UNLINKED"
+	EventTypeAccountLocked     string = "ACCOUNT_LOCKED"
+	EventTypeSignInReported    string = "SIGN_IN_REPORTED"
+	EventTypeImpersonated      string = "ACCOUNT_IMPERSONATED"
+	EventTypeClientTokenIssued string = "CLIENT_TOKEN_ISSUED"
+	EventTypeClientAuthFailed  string = "CLIENT_AUTH_FAILED"
+)
+
+const (
+	LoginMethodPassword  string = "password"
+	LoginMethodMagicLink string = "magic_link"
+	LoginMethodEmailCode string = "email_code"
+	LoginMethodPasskey   string = "passkey"
+	LoginMethodOAuth     string = "oauth"
+	LoginMethodMFA       string = "mfa"
+)
+
+const (
+	LoginFailureUnknownEmail  string = "unknown_email"
+	LoginFailureWrongPassword string = "wrong_password"
+	LoginFailureWrongCode     string = "wrong_code"
+	LoginFailureBadPasskey    string = "bad_passkey"
+	LoginFailureLocked        string = "locked"
+)
+
 const (
-	EventTypeAuthRegistered string = "AUTH_REGISTERED"
+	SessionRevokeReasonLogout        string = "LOGOUT"
+	SessionRevokeReasonRevoked       string = "REVOKED"
+	SessionRevokeReasonRevokedAll    string = "REVOKED_ALL"
+	SessionRevokeReasonReuseDetected string = "REUSE_DETECTED"
 )
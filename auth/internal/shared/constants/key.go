@@ -0,0 +1,11 @@
+package constants
+
+const (
+	KeyAlgorithmEdDSA string = "EdDSA"
+	KeyAlgorithmRS256 string = "RS256"
+)
+
+const (
+	KeyStoreDatabase string = "database"
+	KeyStoreFile     string = "file"
+)
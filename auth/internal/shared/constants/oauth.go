@@ -1,6 +0,0 @@
-package constants
-
-const (
-	OAuthProviderGoogle    int16 = 1
-	OAuthProviderMicrosoft int16 = 2
-)
@@ -0,0 +1,8 @@
+package constants
+
+// what a one-time code was issued for, a subject holds at most one code per purpose
+const (
+	OTPPurposeEmailChange  string = "emch"
+	OTPPurposeLogin        string = "login"
+	OTPPurposeVerification string = "emver"
+)
@@ -0,0 +1,11 @@
+package constants
+
+const (
+	HasherArgon2ID string = "argon2id"
+	HasherBCrypt   string = "bcrypt"
+)
+
+const (
+	BreachSourceAPI  string = "api"
+	BreachSourceFile string = "file"
+)
@@ -2,4 +2,6 @@ package constants
 
 const (
 	RoleCustomer int16 = 1
+	RoleAdmin    int16 = 2
+	RolePharmacy int16 = 3
 )
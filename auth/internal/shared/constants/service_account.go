@@ -0,0 +1,26 @@
+package constants
+
+// tells the tokens of people apart from those of service accounts, which carry no auth id or role
+const (
+	SubjectTypeUser    string = "user"
+	SubjectTypeService string = "service"
+)
+
+const (
+	ScopeUsersRead      string = "users:read"
+	ScopePharmaciesRead string = "pharmacies:read"
+)
+
+// every scope a service account may be granted
+var Scopes = []string{ScopeUsersRead, ScopePharmaciesRead}
+
+const (
+	GrantTypeClientCredentials string = "client_credentials"
+	TokenTypeBearer            string = "Bearer"
+)
+
+const (
+	ClientAuthFailureUnknownClient string = "unknown_client"
+	ClientAuthFailureDisabled      string = "disabled"
+	ClientAuthFailureWrongSecret   string = "wrong_secret"
+)
@@ -14,3 +14,30 @@ func CtxGetAuthID(ctx context.Context) (int64, error) {
 	}
 	return authID, nil
 }
+
+// the admin behind an impersonation token, false for the owner's own tokens
+func CtxGetImpersonatorID(ctx context.Context) (int64, bool) {
+	impersonatorID, ok := ctx.Value(constants.CtxKeyImpersonatorID).(int64)
+	return impersonatorID, ok
+}
+
+func CtxGetLocale(ctx context.Context) string {
+	// events raised outside of a request carry no locale, consumers fall back to their default
+	locale, _ := ctx.Value(constants.CtxKeyLocale).(string)
+	return locale
+}
+
+func CtxGetRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(constants.CtxKeyRequestID).(string)
+	return requestID
+}
+
+func CtxGetUserAgent(ctx context.Context) string {
+	userAgent, _ := ctx.Value(constants.CtxKeyUserAgent).(string)
+	return userAgent
+}
+
+func CtxGetIPAddress(ctx context.Context) string {
+	ipAddress, _ := ctx.Value(constants.CtxKeyIPAddress).(string)
+	return ipAddress
+}
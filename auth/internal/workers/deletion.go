@@ -0,0 +1,35 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/usecases"
+)
+
+type DeletionWorker struct {
+	au       usecases.AccountUsecase
+	interval time.Duration
+}
+
+func NewDeletionWorker(au usecases.AccountUsecase, interval time.Duration) *DeletionWorker {
+	return &DeletionWorker{au, interval}
+}
+
+func (w *DeletionWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// carries out deletions whose cooling-off period is over
+			if err := w.au.PurgeDeletions(ctx); err != nil {
+				log.Println("WARNING ->", err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/usecases"
+)
+
+type KeyWorker struct {
+	ku       usecases.KeyUsecase
+	interval time.Duration
+}
+
+func NewKeyWorker(ku usecases.KeyUsecase, interval time.Duration) *KeyWorker {
+	return &KeyWorker{ku, interval}
+}
+
+func (w *KeyWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// picks up keys rotated by other instances and rotates when due
+			if err := w.ku.LoadKeys(ctx); err != nil {
+				log.Println("WARNING ->", err.Error())
+			}
+		}
+	}
+}
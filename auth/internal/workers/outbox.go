@@ -0,0 +1,44 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/usecases"
+)
+
+// sent messages are cleaned up about once an hour
+const outboxCleanupInterval time.Duration = time.Hour
+
+type OutboxWorker struct {
+	ou       usecases.OutboxUsecase
+	interval time.Duration
+}
+
+func NewOutboxWorker(ou usecases.OutboxUsecase, interval time.Duration) *OutboxWorker {
+	return &OutboxWorker{ou, interval}
+}
+
+func (w *OutboxWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.ou.Relay(ctx); err != nil {
+				log.Println("WARNING ->", err.Error())
+			}
+			if time.Since(lastCleanup) >= outboxCleanupInterval {
+				if err := w.ou.Cleanup(ctx); err != nil {
+					log.Println("WARNING ->", err.Error())
+				}
+				lastCleanup = time.Now()
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/auth/internal/app/usecases"
+)
+
+type ServiceAccountWorker struct {
+	sau      usecases.ServiceAccountUsecase
+	interval time.Duration
+}
+
+func NewServiceAccountWorker(sau usecases.ServiceAccountUsecase, interval time.Duration) *ServiceAccountWorker {
+	return &ServiceAccountWorker{sau, interval}
+}
+
+func (w *ServiceAccountWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// retires rotated-out secrets whose grace period is over
+			if err := w.sau.RetireExpiredSecrets(ctx); err != nil {
+				log.Println("WARNING ->", err.Error())
+			}
+		}
+	}
+}
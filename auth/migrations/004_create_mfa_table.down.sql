@@ -0,0 +1 @@
+DROP TABLE IF EXISTS mfa CASCADE;
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE mfa(
+    mfa_id BIGSERIAL PRIMARY KEY,
+    auth_id BIGINT NOT NULL,
+
+    -- Primary
+    secret VARCHAR NOT NULL,
+    is_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- Secondary
+    enabled_at TIMESTAMPTZ,
+
+    -- Metadata
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (auth_id) REFERENCES auth(auth_id) ON DELETE CASCADE
+);
+
+-- Enforce a single mfa record per auth
+CREATE UNIQUE INDEX idx_mfa_unique_auth_id ON mfa(auth_id);
\ No newline at end of file
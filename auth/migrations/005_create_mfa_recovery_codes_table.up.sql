@@ -0,0 +1,16 @@
+CREATE TABLE mfa_recovery_codes(
+    recovery_code_id BIGSERIAL PRIMARY KEY,
+    auth_id BIGINT NOT NULL,
+
+    -- Primary
+    code_hash VARCHAR NOT NULL,
+
+    -- Metadata
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMPTZ,
+
+    FOREIGN KEY (auth_id) REFERENCES auth(auth_id) ON DELETE CASCADE
+);
+
+-- Index to optimize queries for unused records by auth_id
+CREATE INDEX idx_mfa_recovery_codes_active ON mfa_recovery_codes(auth_id) WHERE used_at IS NULL;
\ No newline at end of file
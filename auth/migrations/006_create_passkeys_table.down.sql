@@ -0,0 +1 @@
+DROP TABLE IF EXISTS passkeys CASCADE;
\ No newline at end of file
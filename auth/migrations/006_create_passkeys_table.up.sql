@@ -0,0 +1,21 @@
+CREATE TABLE passkeys(
+    passkey_id BIGSERIAL PRIMARY KEY,
+    auth_id BIGINT NOT NULL,
+
+    -- Primary
+    credential_id BYTEA UNIQUE NOT NULL,
+    credential JSONB NOT NULL,
+    name VARCHAR NOT NULL,
+
+    -- Secondary
+    last_used_at TIMESTAMPTZ,
+
+    -- Metadata
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (auth_id) REFERENCES auth(auth_id) ON DELETE CASCADE
+);
+
+-- Index to optimize queries for records by auth_id
+CREATE INDEX idx_passkeys_auth_id ON passkeys(auth_id);
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- Time of the original sign-in, carried over to every rotated session
+ALTER TABLE sessions
+    ADD COLUMN signed_in_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- Index to optimize queries for active (not revoked) records by auth_id
+CREATE INDEX idx_sessions_auth_id_active ON sessions(auth_id, created_at DESC) WHERE revoked_at IS NULL;
\ No newline at end of file
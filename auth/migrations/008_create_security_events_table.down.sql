@@ -0,0 +1 @@
+DROP TABLE IF EXISTS security_events CASCADE;
\ No newline at end of file
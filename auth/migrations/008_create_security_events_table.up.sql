@@ -0,0 +1,17 @@
+CREATE TABLE security_events(
+    security_event_id BIGSERIAL PRIMARY KEY,
+    auth_id BIGINT NOT NULL,
+
+    -- Primary
+    event_type VARCHAR NOT NULL,
+    user_agent TEXT NOT NULL,
+    ip_address TEXT NOT NULL,
+
+    -- Metadata
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (auth_id) REFERENCES auth(auth_id) ON DELETE CASCADE
+);
+
+-- Index to optimize queries for records by auth_id
+CREATE INDEX idx_security_events_auth_id ON security_events(auth_id, created_at DESC);
\ No newline at end of file
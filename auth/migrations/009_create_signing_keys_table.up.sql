@@ -0,0 +1,15 @@
+CREATE TABLE signing_keys(
+    signing_key_id BIGSERIAL PRIMARY KEY,
+
+    -- Primary
+    kid VARCHAR UNIQUE NOT NULL,
+    algorithm VARCHAR NOT NULL,
+    private_key TEXT NOT NULL,
+
+    -- Metadata
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- Index to optimize queries for unexpired records
+CREATE INDEX idx_signing_keys_expires_at ON signing_keys(expires_at);
\ No newline at end of file
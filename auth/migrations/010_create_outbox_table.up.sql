@@ -0,0 +1,30 @@
+CREATE TABLE outbox(
+    outbox_id BIGSERIAL PRIMARY KEY,
+
+    -- Primary
+    event_id VARCHAR UNIQUE NOT NULL,
+    event_type VARCHAR NOT NULL,
+    topic VARCHAR NOT NULL,
+    message_key VARCHAR NOT NULL,
+    payload BYTEA NOT NULL,
+    trace_id VARCHAR NOT NULL DEFAULT '',
+    correlation_id VARCHAR NOT NULL DEFAULT '',
+
+    -- Delivery
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    available_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ,
+
+    -- Metadata
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index to optimize relaying of unsent records in insertion order
+CREATE INDEX idx_outbox_unsent ON outbox(outbox_id) WHERE sent_at IS NULL;
+
+-- Index to optimize per-key ordering checks of unsent records
+CREATE INDEX idx_outbox_unsent_message_key ON outbox(message_key, outbox_id) WHERE sent_at IS NULL;
+
+-- Index to optimize cleanup of sent records
+CREATE INDEX idx_outbox_sent_at ON outbox(sent_at) WHERE sent_at IS NOT NULL;
\ No newline at end of file
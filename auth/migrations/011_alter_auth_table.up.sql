@@ -0,0 +1,7 @@
+-- Deletion requested by the account owner, carried out once the cooling-off period is over
+ALTER TABLE auth
+    ADD COLUMN deletion_requested_at TIMESTAMPTZ,
+    ADD COLUMN deletion_scheduled_at TIMESTAMPTZ;
+
+-- Index to optimize queries for active records due for deletion
+CREATE INDEX idx_auth_deletion_due ON auth(deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL AND deleted_at IS NULL;
\ No newline at end of file
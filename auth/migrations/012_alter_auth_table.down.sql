@@ -0,0 +1 @@
+ALTER TABLE auth DROP COLUMN IF EXISTS password_reset_required, DROP COLUMN IF EXISTS suspension_reason, DROP COLUMN IF EXISTS suspended_at;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- Account state set by admins from the back office
+ALTER TABLE auth
+    ADD COLUMN suspended_at TIMESTAMPTZ,
+    ADD COLUMN suspension_reason TEXT,
+    ADD COLUMN password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE admin_audit_logs(
+    admin_audit_log_id BIGSERIAL PRIMARY KEY,
+    admin_id BIGINT, -- NULL when done from the command line
+    target_id BIGINT,
+
+    -- Primary
+    action VARCHAR NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    user_agent TEXT NOT NULL,
+    ip_address TEXT NOT NULL,
+
+    -- Metadata
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index to optimize queries for records by admin_id
+CREATE INDEX idx_admin_audit_logs_admin_id ON admin_audit_logs(admin_id, created_at DESC);
+
+-- Index to optimize queries for records by target_id
+CREATE INDEX idx_admin_audit_logs_target_id ON admin_audit_logs(target_id, created_at DESC);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE auth DROP COLUMN IF EXISTS is_approved;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Accounts whose role needs an admin to approve it before it is active
+ALTER TABLE auth ADD COLUMN is_approved BOOLEAN NOT NULL DEFAULT TRUE;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS password_history CASCADE;
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE password_history(
+    password_history_id BIGSERIAL PRIMARY KEY,
+    auth_id BIGINT NOT NULL,
+
+    -- Primary
+    password TEXT NOT NULL,
+
+    -- Metadata
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (auth_id) REFERENCES auth(auth_id) ON DELETE CASCADE
+);
+
+-- Index to optimize queries for the most recent records by auth_id
+CREATE INDEX idx_password_history_auth_id_created_at ON password_history(auth_id, created_at DESC);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS trg_auth_events_append_only ON auth_events;DROP FUNCTION IF EXISTS prevent_auth_events_change;DROP INDEX IF EXISTS idx_auth_events_created_at;DELETE FROM auth_events WHERE auth_id IS NULL;ALTER TABLE auth_events DROP COLUMN IF EXISTS trace_id, DROP COLUMN IF EXISTS request_id, DROP COLUMN IF EXISTS details, ALTER COLUMN auth_id SET NOT NULL;ALTER INDEX idx_auth_events_auth_id RENAME TO idx_security_events_auth_id;ALTER TABLE auth_events RENAME COLUMN auth_event_id TO security_event_id;ALTER TABLE auth_events RENAME TO security_events;
\ No newline at end of file
@@ -0,0 +1,25 @@
+-- Every security-relevant action on an account, kept as an append-only log
+ALTER TABLE security_events RENAME TO auth_events;
+ALTER TABLE auth_events RENAME COLUMN security_event_id TO auth_event_id;
+ALTER INDEX idx_security_events_auth_id RENAME TO idx_auth_events_auth_id;
+
+-- Failed sign-ins against unknown emails have no account to point to
+ALTER TABLE auth_events
+    ALTER COLUMN auth_id DROP NOT NULL,
+    ADD COLUMN details JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN request_id TEXT NOT NULL DEFAULT '',
+    ADD COLUMN trace_id TEXT NOT NULL DEFAULT '';
+
+-- Index to optimize queries for records across all accounts
+CREATE INDEX idx_auth_events_created_at ON auth_events(created_at DESC);
+
+-- Recorded events are never rewritten or removed
+CREATE FUNCTION prevent_auth_events_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'auth_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_auth_events_append_only
+    BEFORE UPDATE OR DELETE ON auth_events
+    FOR EACH ROW EXECUTE FUNCTION prevent_auth_events_change();
\ No newline at end of file
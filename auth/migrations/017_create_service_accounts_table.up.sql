@@ -0,0 +1,23 @@
+CREATE TABLE service_accounts(
+    service_account_id BIGSERIAL PRIMARY KEY,
+    created_by BIGINT, -- NULL when done from the command line
+
+    -- Primary
+    client_id VARCHAR NOT NULL,
+    name VARCHAR NOT NULL,
+    secret_hash VARCHAR NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '', -- space-delimited, as in an oauth2 scope parameter
+
+    -- Secondary
+    previous_secret_hash VARCHAR, -- still accepted until previous_secret_expires_at after a rotation
+    previous_secret_expires_at TIMESTAMPTZ,
+    secret_rotated_at TIMESTAMPTZ,
+    disabled_at TIMESTAMPTZ,
+
+    -- Metadata
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Enforce uniqueness of client_id
+CREATE UNIQUE INDEX idx_service_accounts_unique_client_id ON service_accounts(client_id);
\ No newline at end of file
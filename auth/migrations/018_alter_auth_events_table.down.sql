@@ -0,0 +1 @@
+CREATE OR REPLACE FUNCTION prevent_auth_events_change() RETURNS TRIGGER AS $$ BEGIN RAISE EXCEPTION 'auth_events is append-only'; END; $$ LANGUAGE plpgsql;
\ No newline at end of file
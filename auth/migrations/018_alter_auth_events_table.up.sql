@@ -0,0 +1,20 @@
+-- Erasure of a deleted account may blank the personal columns of its events, nothing else
+CREATE OR REPLACE FUNCTION prevent_auth_events_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND current_setting('auth_events.erasure', true) = 'on'
+        AND NEW.auth_event_id = OLD.auth_event_id
+        AND NEW.auth_id IS NOT DISTINCT FROM OLD.auth_id
+        AND NEW.event_type = OLD.event_type
+        AND NEW.request_id = OLD.request_id
+        AND NEW.trace_id = OLD.trace_id
+        AND NEW.created_at = OLD.created_at
+        AND NEW.user_agent = ''
+        AND NEW.ip_address = ''
+        AND NEW.details = '{}'
+    THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'auth_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS pharmacy_applications CASCADE;
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE TABLE pharmacy_applications(
+    auth_id BIGINT PRIMARY KEY,
+    reviewed_by BIGINT, -- the admin who approved the account
+
+    -- Primary
+    legal_name VARCHAR NOT NULL,
+    license_number VARCHAR NOT NULL,
+    license_authority VARCHAR NOT NULL,
+    license_expiry DATE NOT NULL,
+
+    -- Secondary
+    reviewed_at TIMESTAMPTZ,
+
+    -- Metadata
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (auth_id) REFERENCES auth(auth_id) ON DELETE CASCADE
+);
+
+-- Enforce uniqueness of license_number, a licence can back a single account
+CREATE UNIQUE INDEX idx_pharmacy_applications_unique_license_number ON pharmacy_applications(license_number);
\ No newline at end of file
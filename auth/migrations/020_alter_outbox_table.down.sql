@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_outbox_parked_at;DROP INDEX IF EXISTS idx_outbox_unsent_message_key;CREATE INDEX idx_outbox_unsent_message_key ON outbox(message_key, outbox_id) WHERE sent_at IS NULL;DROP INDEX IF EXISTS idx_outbox_unsent;CREATE INDEX idx_outbox_unsent ON outbox(outbox_id) WHERE sent_at IS NULL;ALTER TABLE outbox DROP COLUMN IF EXISTS parked_at;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- Messages that keep failing are parked for inspection instead of being retried forever
+ALTER TABLE outbox ADD COLUMN parked_at TIMESTAMPTZ;
+
+-- Parked records are no longer relayed, so they leave the unsent indexes
+DROP INDEX IF EXISTS idx_outbox_unsent;
+CREATE INDEX idx_outbox_unsent ON outbox(outbox_id) WHERE sent_at IS NULL AND parked_at IS NULL;
+
+DROP INDEX IF EXISTS idx_outbox_unsent_message_key;
+CREATE INDEX idx_outbox_unsent_message_key ON outbox(message_key, outbox_id) WHERE sent_at IS NULL AND parked_at IS NULL;
+
+-- Index to optimize lookups of parked records
+CREATE INDEX idx_outbox_parked_at ON outbox(parked_at) WHERE parked_at IS NOT NULL;
\ No newline at end of file
@@ -28,6 +28,7 @@ type Event struct {
 	SourceService string                 `protobuf:"bytes,3,opt,name=source_service,json=sourceService,proto3" json:"source_service,omitempty"`
 	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	Data          []byte                 `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	Locale        string                 `protobuf:"bytes,6,opt,name=locale,proto3" json:"locale,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -97,18 +98,26 @@ func (x *Event) GetData() []byte {
 	return nil
 }
 
+func (x *Event) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
 var File_pkg_events_event_proto protoreflect.FileDescriptor
 
 const file_pkg_events_event_proto_rawDesc = "" +
 	"\n" +
-	"\x16pkg/events/event.proto\x12\x06events\"\x9a\x01\n" +
+	"\x16pkg/events/event.proto\x12\x06events\"\xb2\x01\n" +
 	"\x05Event\x12\x19\n" +
 	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x1d\n" +
 	"\n" +
 	"event_type\x18\x02 \x01(\tR\teventType\x12%\n" +
 	"\x0esource_service\x18\x03 \x01(\tR\rsourceService\x12\x1c\n" +
 	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\x12\x12\n" +
-	"\x04data\x18\x05 \x01(\fR\x04dataB?Z=github.com/ritchieridanko/apotekly-api/auth/pkg/events;eventsb\x06proto3"
+	"\x04data\x18\x05 \x01(\fR\x04data\x12\x16\n" +
+	"\x06locale\x18\x06 \x01(\tR\x06localeB?Z=github.com/ritchieridanko/apotekly-api/auth/pkg/events;eventsb\x06proto3"
 
 var (
 	file_pkg_events_event_proto_rawDescOnce sync.Once
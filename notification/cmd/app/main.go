@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/ritchieridanko/apotekly-api/notification/configs"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/infrastructure"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/interfaces/di"
+)
+
+func main() {
+	cfg, err := configs.Load("./configs")
+	if err != nil {
+		log.Fatalln("FATAL ->", err.Error())
+	}
+
+	infra, err := infrastructure.Initialize(cfg)
+	if err != nil {
+		log.Fatalln("FATAL ->", err.Error())
+	}
+	defer infra.Close()
+
+	c, err := di.NewContainer(cfg, infra)
+	if err != nil {
+		log.Fatalln("FATAL ->", err.Error())
+	}
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	var wg sync.WaitGroup
+	for _, w := range c.Workers() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Run(workerCtx)
+		}()
+	}
+
+	// handle graceful shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	// a message being processed is left uncommitted and picked up again after restart
+	stopWorkers()
+	wg.Wait()
+}
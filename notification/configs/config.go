@@ -0,0 +1,113 @@
+package configs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	App       `mapstructure:"app"`
+	Client    `mapstructure:"client"`
+	Mail      `mapstructure:"mail"`
+	Templates `mapstructure:"templates"`
+	Delivery  `mapstructure:"delivery"`
+	Cache     `mapstructure:"cache"`
+	Tracer    `mapstructure:"tracer"`
+	Broker    `mapstructure:"broker"`
+}
+
+type App struct {
+	Name string `mapstructure:"name"`
+	Env  string `mapstructure:"env"`
+}
+
+type Client struct {
+	BaseURL string `mapstructure:"base_url"`
+
+	Paths struct {
+		Verification  string `mapstructure:"verification"`
+		EmailChange   string `mapstructure:"email_change"`
+		PasswordReset string `mapstructure:"password_reset"`
+		Unlock        string `mapstructure:"unlock"`
+		MagicLink     string `mapstructure:"magic_link"`
+		SignInReport  string `mapstructure:"sign_in_report"`
+		Security      string `mapstructure:"security"`
+	} `mapstructure:"paths"`
+}
+
+type Mail struct {
+	Host        string        `mapstructure:"host"`
+	Port        int           `mapstructure:"port"`
+	User        string        `mapstructure:"user"`
+	Pass        string        `mapstructure:"pass"`
+	Security    string        `mapstructure:"security"`
+	FromAddress string        `mapstructure:"from_address"`
+	FromName    string        `mapstructure:"from_name"`
+	Timeout     time.Duration `mapstructure:"timeout"`
+}
+
+type Templates struct {
+	Dir           string `mapstructure:"dir"`
+	DefaultLocale string `mapstructure:"default_locale"`
+}
+
+type Delivery struct {
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	BaseDelay   time.Duration `mapstructure:"base_delay"`
+	MaxDelay    time.Duration `mapstructure:"max_delay"`
+	Lease       time.Duration `mapstructure:"lease"`
+	DedupeTTL   time.Duration `mapstructure:"dedupe_ttl"`
+}
+
+type Cache struct {
+	Host       string `mapstructure:"host"`
+	Port       int    `mapstructure:"port"`
+	Pass       string `mapstructure:"pass"`
+	MaxRetries int    `mapstructure:"max_retries"`
+	BaseDelay  int    `mapstructure:"base_delay"`
+}
+
+type Tracer struct {
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+type Broker struct {
+	Brokers string `mapstructure:"brokers"`
+	GroupID string `mapstructure:"group_id"`
+
+	Topics struct {
+		AuthEvents string `mapstructure:"auth_events"`
+		DeadLetter string `mapstructure:"dead_letter"`
+	} `mapstructure:"topics"`
+
+	Timeout struct {
+		Batch time.Duration `mapstructure:"batch"`
+	} `mapstructure:"timeout"`
+}
+
+func Load(path string) (*Config, error) {
+	v := viper.New()
+
+	v.SetConfigName("config")
+	v.SetConfigType("yaml")
+	v.AddConfigPath(path)
+
+	// read YAML config
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// load env variables
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	var cfg Config
+	if err := v.UnmarshalExact(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	return &cfg, nil
+}
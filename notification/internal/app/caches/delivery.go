@@ -0,0 +1,121 @@
+package caches
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/notification/internal/services/cache"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/shared/utils"
+	"go.opentelemetry.io/otel"
+)
+
+const deliveryErrorTracer string = "cache.delivery"
+
+const (
+	deliveryPending string = "pending"
+	deliverySent    string = "sent"
+)
+
+type DeliveryCache interface {
+	Claim(ctx context.Context, eventID, recipient string, lease time.Duration) (isDuplicate bool, err error)
+	MarkSent(ctx context.Context, eventID, recipient string, duration time.Duration) (err error)
+	Release(ctx context.Context, eventID, recipient string) (err error)
+}
+
+type deliveryCache struct {
+	cache *cache.Cache
+}
+
+func NewDeliveryCache(cache *cache.Cache) DeliveryCache {
+	return &deliveryCache{cache}
+}
+
+func (c *deliveryCache) Claim(ctx context.Context, eventID, recipient string, lease time.Duration) (bool, error) {
+	ctx, span := otel.Tracer(deliveryErrorTracer).Start(ctx, "Claim")
+	defer span.End()
+
+	// a pending claim expires with its lease, so a consumer dying mid-send
+	// does not keep the notification from being delivered by the next one
+	script := `
+		local value = redis.call("GET", KEYS[1])
+		if value == ARGV[2] then
+			return 0
+		end
+		if value then
+			return -1
+		end
+		redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[3])
+		return 1
+	`
+
+	result, err := c.cache.Evaluate(
+		ctx, "hs:dlvc", script,
+		[]string{c.key(eventID, recipient)},
+		deliveryPending, deliverySent, lease.Milliseconds(),
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to claim delivery: %w", err)
+		return false, ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalFailure, wErr)
+	}
+
+	status, ok := result.(int64)
+	if !ok {
+		wErr := fmt.Errorf("failed to claim delivery: %w", ce.ErrTypeAssertionFailed)
+		return false, ce.NewError(span, ce.CodeTypeAssertionFailed, ce.MsgInternalFailure, wErr)
+	}
+
+	switch status {
+	case 0:
+		return true, nil
+	case -1:
+		wErr := fmt.Errorf("failed to claim delivery: %w", ce.ErrDeliveryInProgress)
+		return false, ce.NewError(span, ce.CodeDeliveryInProgress, ce.MsgDeliveryFailed, wErr)
+	default:
+		return false, nil
+	}
+}
+
+func (c *deliveryCache) MarkSent(ctx context.Context, eventID, recipient string, duration time.Duration) error {
+	ctx, span := otel.Tracer(deliveryErrorTracer).Start(ctx, "MarkSent")
+	defer span.End()
+
+	if err := c.cache.Set(ctx, c.key(eventID, recipient), deliverySent, duration); err != nil {
+		wErr := fmt.Errorf("failed to mark delivery as sent: %w", err)
+		return ce.NewError(span, ce.CodeCacheQueryExecution, ce.MsgInternalFailure, wErr)
+	}
+
+	return nil
+}
+
+func (c *deliveryCache) Release(ctx context.Context, eventID, recipient string) error {
+	ctx, span := otel.Tracer(deliveryErrorTracer).Start(ctx, "Release")
+	defer span.End()
+
+	// only a pending claim is released, a delivery that went through stays recorded
+	script := `
+		if redis.call("GET", KEYS[1]) == ARGV[1] then
+			redis.call("DEL", KEYS[1])
+		end
+		return 1
+	`
+
+	_, err := c.cache.Evaluate(
+		ctx, "hs:dlvr", script,
+		[]string{c.key(eventID, recipient)},
+		deliveryPending,
+	)
+	if err != nil {
+		wErr := fmt.Errorf("failed to release delivery: %w", err)
+		return ce.NewError(span, ce.CodeCacheScriptExecution, ce.MsgInternalFailure, wErr)
+	}
+
+	return nil
+}
+
+func (c *deliveryCache) key(eventID, recipient string) string {
+	// recipients are hashed to keep addresses out of the cache
+	return fmt.Sprintf("%s:%s:%s", constants.CachePrefixDelivery, eventID, utils.HashSHA256(utils.Normalize(recipient)))
+}
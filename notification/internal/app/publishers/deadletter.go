@@ -0,0 +1,52 @@
+package publishers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+
+	"github.com/ritchieridanko/apotekly-api/notification/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/services/broker"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/shared/constants"
+	"go.opentelemetry.io/otel"
+)
+
+const deadLetterErrorTracer string = "publisher.deadletter"
+
+type DeadLetterPublisher interface {
+	Publish(ctx context.Context, message *entities.Message, attempts int, reason error) (err error)
+}
+
+type deadLetterPublisher struct {
+	producer *broker.Producer
+	topic    string
+}
+
+func NewDeadLetterPublisher(producer *broker.Producer, topic string) DeadLetterPublisher {
+	return &deadLetterPublisher{producer, topic}
+}
+
+func (p *deadLetterPublisher) Publish(ctx context.Context, message *entities.Message, attempts int, reason error) error {
+	ctx, span := otel.Tracer(deadLetterErrorTracer).Start(ctx, "Publish")
+	defer span.End()
+
+	// the original message is kept as is, so it can be replayed onto its topic
+	headers := maps.Clone(message.Headers)
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	headers[constants.HeaderDeadLetterAttempts] = strconv.Itoa(attempts)
+	headers[constants.HeaderDeadLetterError] = reason.Error()
+	headers[constants.HeaderDeadLetterTopic] = message.Topic
+	headers[constants.HeaderDeadLetterPartition] = strconv.Itoa(message.Partition)
+	headers[constants.HeaderDeadLetterOffset] = strconv.FormatInt(message.Offset, 10)
+
+	if err := p.producer.PublishEncoded(ctx, p.topic, message.Key, message.Value, headers); err != nil {
+		wErr := fmt.Errorf("failed to publish to dead-letter topic: %w", err)
+		return ce.NewError(span, ce.CodeEventPublishingFailed, ce.MsgInternalFailure, wErr)
+	}
+
+	return nil
+}
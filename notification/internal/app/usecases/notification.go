@@ -0,0 +1,360 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/notification/configs"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/app/caches"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/app/publishers"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/services"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/services/logger"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/shared/ce"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/shared/utils"
+	"github.com/ritchieridanko/apotekly-api/notification/pkg/events"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const notificationErrorTracer string = "usecase.notification"
+
+type NotificationUsecase interface {
+	Process(ctx context.Context, message *entities.Message) (err error)
+}
+
+type notificationUsecase struct {
+	dc     caches.DeliveryCache
+	dlp    publishers.DeadLetterPublisher
+	ts     *services.TemplateService
+	mailer *services.MailerService
+	logger *logger.Logger
+	cfg    *configs.Config
+}
+
+func NewNotificationUsecase(
+	dc caches.DeliveryCache,
+	dlp publishers.DeadLetterPublisher,
+	ts *services.TemplateService,
+	mailer *services.MailerService,
+	logger *logger.Logger,
+	cfg *configs.Config,
+) NotificationUsecase {
+	return &notificationUsecase{dc, dlp, ts, mailer, logger, cfg}
+}
+
+func (u *notificationUsecase) Process(ctx context.Context, message *entities.Message) error {
+	ctx, span := otel.Tracer(notificationErrorTracer).Start(ctx, "Process")
+	defer span.End()
+
+	var event events.Event
+	if err := proto.Unmarshal(message.Value, &event); err != nil {
+		wErr := fmt.Errorf("failed to decode event envelope: %w", err)
+		return u.deadLetter(ctx, message, 0, ce.NewError(span, ce.CodeEventDecodingFailed, ce.MsgInvalidEvent, wErr))
+	}
+	span.SetAttributes(
+		attribute.String("event.id", event.EventId),
+		attribute.String("event.type", event.EventType),
+	)
+
+	notification, err := u.build(&event)
+	if err != nil {
+		wErr := fmt.Errorf("failed to decode event %s: %w", event.EventType, err)
+		return u.deadLetter(ctx, message, 0, ce.NewError(span, ce.CodeEventDecodingFailed, ce.MsgInvalidEvent, wErr))
+	}
+	if notification == nil {
+		// not every event is worth an email
+		return nil
+	}
+
+	attempts := 0
+	for {
+		attempts++
+
+		var isDuplicate bool
+		isDuplicate, err = u.deliver(ctx, span, notification)
+		if err == nil {
+			status := "notification sent"
+			if isDuplicate {
+				status = "notification already sent, skipped"
+			}
+			u.logger.Log(
+				ctx, constants.LogLevelInfo, status, message,
+				zap.String("event_id", notification.EventID),
+				zap.String("template", notification.Template),
+				zap.Int("attempts", attempts),
+			)
+			return nil
+		}
+
+		var cErr *ce.Error
+		if errors.As(err, &cErr) && cErr.IsPermanent() {
+			break
+		}
+		if attempts >= u.cfg.Delivery.MaxAttempts {
+			break
+		}
+
+		log.Println("WARNING ->", err.Error())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(u.retryDelay(attempts)):
+		}
+	}
+
+	return u.deadLetter(ctx, message, attempts, err)
+}
+
+func (u *notificationUsecase) build(event *events.Event) (*entities.Notification, error) {
+	n := entities.Notification{
+		EventID:   event.EventId,
+		EventType: event.EventType,
+		Locale:    event.Locale,
+	}
+	n.Data.OccurredAt = time.UnixMilli(event.Timestamp).UTC()
+
+	switch event.EventType {
+	case constants.EventTypeAuthRegistered:
+		var data events.AuthRegistered
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplateWelcome
+		n.Data.Link = u.link(u.cfg.Client.Paths.Verification, data.Token)
+		n.Data.Code = data.Code
+	case constants.EventTypeVerificationRequested:
+		var data events.VerificationRequested
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplateVerification
+		n.Data.Link = u.link(u.cfg.Client.Paths.Verification, data.Token)
+		n.Data.Code = data.Code
+	case constants.EventTypeEmailChangeRequested:
+		var data events.EmailChangeRequested
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplateEmailChange
+		n.Data.Link = u.link(u.cfg.Client.Paths.EmailChange, data.Token)
+		n.Data.Code = data.Code
+		n.Data.NewEmail = data.Recipient
+	case constants.EventTypeEmailChanged:
+		var data events.EmailChanged
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplateEmailChanged
+		n.Data.Link = u.link(u.cfg.Client.Paths.Security, "")
+		n.Data.NewEmail = data.NewEmail
+		n.Data.OccurredAt = time.UnixMilli(data.ChangedAt).UTC()
+	case constants.EventTypePasswordResetRequested:
+		var data events.PasswordResetRequested
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplatePasswordReset
+		n.Data.Link = u.link(u.cfg.Client.Paths.PasswordReset, data.Token)
+	case constants.EventTypePasswordChanged:
+		var data events.PasswordChanged
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplatePasswordChanged
+		n.Data.Link = u.link(u.cfg.Client.Paths.Security, "")
+		n.Data.OccurredAt = time.UnixMilli(data.ChangedAt).UTC()
+	case constants.EventTypeAccountVerified:
+		var data events.AccountVerified
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplateAccountVerified
+		n.Data.Link = u.link("", "")
+	case constants.EventTypeUnlockRequested:
+		var data events.UnlockRequested
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplateUnlock
+		n.Data.Link = u.link(u.cfg.Client.Paths.Unlock, data.Token)
+	case constants.EventTypeMagicLinkRequested:
+		var data events.MagicLinkRequested
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplateMagicLink
+		n.Data.Link = u.link(u.cfg.Client.Paths.MagicLink, data.Token)
+		n.Data.UserAgent = data.UserAgent
+		n.Data.IPAddress = data.IpAddress
+	case constants.EventTypeLoginCodeRequested:
+		var data events.LoginCodeRequested
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplateLoginCode
+		n.Data.Code = data.Code
+		n.Data.UserAgent = data.UserAgent
+		n.Data.IPAddress = data.IpAddress
+	case constants.EventTypeSessionReuseDetected:
+		var data events.SessionReuseDetected
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplateSessionReuse
+		n.Data.Link = u.link(u.cfg.Client.Paths.Security, "")
+		n.Data.UserAgent = data.UserAgent
+		n.Data.IPAddress = data.IpAddress
+		n.Data.OccurredAt = time.UnixMilli(data.DetectedAt).UTC()
+	case constants.EventTypeNewSignInDetected:
+		var data events.NewSignInDetected
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplateNewSignIn
+		n.Data.Link = u.link(u.cfg.Client.Paths.SignInReport, data.Token)
+		n.Data.UserAgent = data.UserAgent
+		n.Data.IPAddress = data.IpAddress
+		n.Data.Location = data.Location
+		n.Data.ImpossibleTravel = data.ImpossibleTravel
+		n.Data.OccurredAt = time.UnixMilli(data.SignedInAt).UTC()
+	case constants.EventTypeOAuthLinked:
+		var data events.OAuthLinked
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplateOAuthLinked
+		n.Data.Link = u.link(u.cfg.Client.Paths.Security, "")
+		n.Data.Provider = data.Provider
+		n.Data.OccurredAt = time.UnixMilli(data.LinkedAt).UTC()
+	case constants.EventTypeDeletionScheduled:
+		var data events.AccountDeletionScheduled
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplateDeletionScheduled
+		n.Data.Link = u.link(u.cfg.Client.Paths.Security, "")
+		n.Data.ScheduledAt = time.UnixMilli(data.ScheduledAt).UTC()
+	case constants.EventTypeDeletionCancelled:
+		var data events.AccountDeletionCancelled
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplateDeletionCancelled
+		n.Data.Link = u.link(u.cfg.Client.Paths.Security, "")
+		n.Data.OccurredAt = time.UnixMilli(data.CancelledAt).UTC()
+	case constants.EventTypeAccountDeleted:
+		var data events.AccountDeleted
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		}
+		n.Recipient = data.Recipient
+		n.Template = constants.TemplateAccountDeleted
+		n.Data.OccurredAt = time.UnixMilli(data.DeletedAt).UTC()
+	default:
+		return nil, nil
+	}
+
+	if n.Recipient == "" {
+		return nil, errors.New("event has no recipient")
+	}
+	n.Data.Recipient = n.Recipient
+
+	return &n, nil
+}
+
+func (u *notificationUsecase) deliver(ctx context.Context, span trace.Span, n *entities.Notification) (bool, error) {
+	subject, text, html, err := u.ts.Render(n.Locale, n.Template, &n.Data)
+	if err != nil {
+		wErr := fmt.Errorf("failed to render template %s: %w", n.Template, err)
+		return false, ce.NewError(span, ce.CodeEmailTemplateParsing, ce.MsgDeliveryFailed, wErr)
+	}
+
+	isDuplicate, err := u.dc.Claim(ctx, n.EventID, n.Recipient, u.cfg.Delivery.Lease)
+	if err != nil {
+		return false, err
+	}
+	if isDuplicate {
+		// already delivered before, e.g. the offset was not committed in time
+		return true, nil
+	}
+
+	email := entities.Email{
+		MessageID: fmt.Sprintf("%s.%s", n.EventID, utils.HashSHA256(utils.Normalize(n.Recipient))[:16]),
+		To:        n.Recipient,
+		Subject:   subject,
+		Text:      text,
+		HTML:      html,
+	}
+	if err := u.mailer.Send(ctx, &email); err != nil {
+		// non-fatal: a pending claim runs out with its lease anyway
+		if err := u.dc.Release(ctx, n.EventID, n.Recipient); err != nil {
+			log.Println("WARNING ->", err.Error())
+		}
+
+		code := ce.CodeEmailDelivery
+		if services.IsPermanentMailError(err) {
+			code = ce.CodeEmailRejected
+		}
+		wErr := fmt.Errorf("failed to send email: %w", err)
+		return false, ce.NewError(span, code, ce.MsgDeliveryFailed, wErr)
+	}
+
+	// non-fatal: the email is out, at worst a redelivery sends it again
+	if err := u.dc.MarkSent(ctx, n.EventID, n.Recipient, u.cfg.Delivery.DedupeTTL); err != nil {
+		log.Println("WARNING ->", err.Error())
+	}
+	return false, nil
+}
+
+func (u *notificationUsecase) deadLetter(ctx context.Context, message *entities.Message, attempts int, reason error) error {
+	if err := u.dlp.Publish(ctx, message, attempts, reason); err != nil {
+		return err
+	}
+
+	u.logger.Log(
+		ctx, constants.LogLevelError, "notification dead-lettered", message,
+		zap.Int("attempts", attempts),
+		zap.String("error", reason.Error()),
+	)
+	return nil
+}
+
+func (u *notificationUsecase) link(path, token string) string {
+	link := u.cfg.Client.BaseURL + path
+	if token != "" {
+		link += "?token=" + url.QueryEscape(token)
+	}
+	return link
+}
+
+func (u *notificationUsecase) retryDelay(attempts int) time.Duration {
+	delay := u.cfg.Delivery.BaseDelay
+	for i := 1; i < attempts && delay < u.cfg.Delivery.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, u.cfg.Delivery.MaxDelay)
+}
@@ -0,0 +1,9 @@
+package entities
+
+type Email struct {
+	MessageID string
+	To        string
+	Subject   string
+	Text      string
+	HTML      string
+}
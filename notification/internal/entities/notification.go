@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+type Notification struct {
+	EventID   string
+	EventType string
+	Recipient string
+	Locale    string
+	Template  string
+	Data      NotificationData
+}
+
+// everything a template may refer to, unused fields are left empty
+type NotificationData struct {
+	Recipient        string
+	Link             string
+	Code             string
+	NewEmail         string
+	Provider         string
+	UserAgent        string
+	IPAddress        string
+	Location         string
+	OccurredAt       time.Time
+	ScheduledAt      time.Time
+	ImpossibleTravel bool
+}
@@ -0,0 +1,50 @@
+package broker
+
+import (
+	"strings"
+
+	"github.com/ritchieridanko/apotekly-api/notification/configs"
+	"github.com/segmentio/kafka-go"
+)
+
+type Broker struct {
+	consumer *kafka.Reader
+	producer *kafka.Writer
+}
+
+func NewClient(cfg *configs.Broker) *Broker {
+	brokers := strings.Split(cfg.Brokers, ",")
+
+	// offsets are committed by hand, only once a message has been dealt with
+	c := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     brokers,
+		GroupID:     cfg.GroupID,
+		Topic:       cfg.Topics.AuthEvents,
+		StartOffset: kafka.FirstOffset,
+	})
+
+	p := kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		Async:        false,
+		BatchTimeout: cfg.Timeout.Batch,
+	}
+
+	return &Broker{consumer: c, producer: &p}
+}
+
+func (b *Broker) Consumer() *kafka.Reader {
+	return b.consumer
+}
+
+func (b *Broker) Producer() *kafka.Writer {
+	return b.producer
+}
+
+func (b *Broker) Close() error {
+	if err := b.consumer.Close(); err != nil {
+		return err
+	}
+	return b.producer.Close()
+}
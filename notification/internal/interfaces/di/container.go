@@ -0,0 +1,49 @@
+package di
+
+import (
+	"github.com/ritchieridanko/apotekly-api/notification/configs"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/app/caches"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/app/publishers"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/app/usecases"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/infrastructure"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/services"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/services/broker"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/services/cache"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/services/logger"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/workers"
+)
+
+type Container struct {
+	workers []workers.Worker
+}
+
+func NewContainer(cfg *configs.Config, infra *infrastructure.Infrastructure) (*Container, error) {
+	cache := cache.NewCache(infra.Cache(), cfg.Cache.MaxRetries, cfg.Cache.BaseDelay)
+	templates, err := services.NewTemplateService(cfg.Templates.Dir, cfg.Templates.DefaultLocale)
+	if err != nil {
+		return nil, err
+	}
+	mailer, err := services.NewMailerService(&cfg.Mail)
+	if err != nil {
+		return nil, err
+	}
+	logger := logger.NewLogger(infra.Logger())
+	consumer := broker.NewConsumer(infra.Broker().Consumer())
+	producer := broker.NewProducer(infra.Broker().Producer())
+
+	dc := caches.NewDeliveryCache(cache)
+
+	dlp := publishers.NewDeadLetterPublisher(producer, cfg.Broker.Topics.DeadLetter)
+
+	nu := usecases.NewNotificationUsecase(dc, dlp, templates, mailer, logger, cfg)
+
+	ws := []workers.Worker{
+		workers.NewConsumerWorker(consumer, nu),
+	}
+
+	return &Container{workers: ws}, nil
+}
+
+func (c *Container) Workers() []workers.Worker {
+	return c.workers
+}
@@ -0,0 +1,47 @@
+package broker
+
+import (
+	"context"
+
+	"github.com/ritchieridanko/apotekly-api/notification/internal/entities"
+	"github.com/segmentio/kafka-go"
+)
+
+type Consumer struct {
+	consumer *kafka.Reader
+}
+
+func NewConsumer(consumer *kafka.Reader) *Consumer {
+	return &Consumer{consumer}
+}
+
+func (c *Consumer) Fetch(ctx context.Context) (*entities.Message, error) {
+	m, err := c.consumer.FetchMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(map[string]string, len(m.Headers))
+	for _, h := range m.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+
+	message := entities.Message{
+		Topic:     m.Topic,
+		Partition: m.Partition,
+		Offset:    m.Offset,
+		Key:       string(m.Key),
+		Value:     m.Value,
+		Headers:   headers,
+	}
+
+	return &message, nil
+}
+
+func (c *Consumer) Commit(ctx context.Context, message *entities.Message) error {
+	return c.consumer.CommitMessages(ctx, kafka.Message{
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    message.Offset,
+	})
+}
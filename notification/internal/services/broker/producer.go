@@ -0,0 +1,28 @@
+package broker
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type Producer struct {
+	producer *kafka.Writer
+}
+
+func NewProducer(producer *kafka.Writer) *Producer {
+	return &Producer{producer}
+}
+
+func (p *Producer) PublishEncoded(ctx context.Context, topic, key string, value []byte, headers map[string]string) error {
+	message := kafka.Message{
+		Topic: topic,
+		Key:   []byte(key),
+		Value: value,
+	}
+	for k, v := range headers {
+		message.Headers = append(message.Headers, kafka.Header{Key: k, Value: []byte(v)})
+	}
+
+	return p.producer.WriteMessages(ctx, message)
+}
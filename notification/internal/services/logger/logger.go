@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"context"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/notification/internal/entities"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type Logger struct {
+	logger *zap.Logger
+}
+
+func NewLogger(logger *zap.Logger) *Logger {
+	return &Logger{logger}
+}
+
+func (l *Logger) Log(ctx context.Context, level zapcore.Level, message string, m *entities.Message, additionalFields ...zap.Field) {
+	traceID := trace.SpanFromContext(ctx).SpanContext().TraceID().String()
+
+	fields := []zap.Field{
+		zap.String("timestamp", time.Now().UTC().Format(time.RFC3339)),
+		zap.String("trace_id", traceID),
+		zap.String("source_trace_id", m.Headers["trace_id"]),
+		zap.String("correlation_id", m.Headers["correlation_id"]),
+		zap.String("topic", m.Topic),
+		zap.Int("partition", m.Partition),
+		zap.Int64("offset", m.Offset),
+		zap.String("key", m.Key),
+	}
+
+	fields = append(fields, additionalFields...)
+	l.logger.Log(level, message, fields...)
+}
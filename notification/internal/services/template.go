@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+)
+
+// every locale directory holds a layout.html and, per template, a <name>.html body
+// and a <name>.txt body which also defines the "subject"
+const layoutFile string = "layout.html"
+
+type TemplateService struct {
+	defaultLocale string
+	locales       map[string]map[string]*emailTemplate
+}
+
+type emailTemplate struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+func NewTemplateService(dir, defaultLocale string) (*TemplateService, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template directory: %w", err)
+	}
+
+	locales := make(map[string]map[string]*emailTemplate)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		templates, err := parseLocale(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse templates of locale %q: %w", entry.Name(), err)
+		}
+		locales[entry.Name()] = templates
+	}
+
+	if _, ok := locales[defaultLocale]; !ok {
+		return nil, fmt.Errorf("no templates for default locale %q", defaultLocale)
+	}
+
+	return &TemplateService{defaultLocale, locales}, nil
+}
+
+func (s *TemplateService) Render(locale, name string, data any) (subject, text, html string, err error) {
+	t, ok := s.lookup(locale, name)
+	if !ok {
+		return "", "", "", fmt.Errorf("template %q not found", name)
+	}
+
+	var buf bytes.Buffer
+	if err := t.text.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", "", err
+	}
+	subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	if err := t.text.Execute(&buf, data); err != nil {
+		return "", "", "", err
+	}
+	text = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	if err := t.html.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return "", "", "", err
+	}
+	html = buf.String()
+
+	return subject, text, html, nil
+}
+
+func (s *TemplateService) lookup(locale, name string) (*emailTemplate, bool) {
+	// a locale without the template, or no locale at all, gets the default one
+	if templates, ok := s.locales[locale]; ok {
+		if t, ok := templates[name]; ok {
+			return t, true
+		}
+	}
+
+	t, ok := s.locales[s.defaultLocale][name]
+	return t, ok
+}
+
+func parseLocale(dir string) (map[string]*emailTemplate, error) {
+	layout, err := htmltemplate.ParseFiles(filepath.Join(dir, layoutFile))
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]*emailTemplate, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".txt")
+
+		text, err := texttemplate.ParseFiles(file)
+		if err != nil {
+			return nil, err
+		}
+		if text.Lookup("subject") == nil {
+			return nil, fmt.Errorf("template %q does not define a subject", name)
+		}
+
+		html, err := layout.Clone()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := html.ParseFiles(filepath.Join(dir, name+".html")); err != nil {
+			return nil, err
+		}
+
+		templates[name] = &emailTemplate{html, text}
+	}
+
+	return templates, nil
+}
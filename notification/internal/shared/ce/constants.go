@@ -0,0 +1,36 @@
+package ce
+
+import (
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type errCode string
+
+// internal error codes (for logs/debugging)
+const (
+	CodeCacheQueryExecution   errCode = "CACHE_QUERY_EXECUTION_ERROR"
+	CodeCacheScriptExecution  errCode = "CACHE_SCRIPT_EXECUTION_ERROR"
+	CodeDeliveryInProgress    errCode = "DELIVERY_IN_PROGRESS_ERROR"
+	CodeEmailDelivery         errCode = "EMAIL_DELIVERY_ERROR"
+	CodeEmailRejected         errCode = "EMAIL_REJECTED_ERROR"
+	CodeEmailTemplateParsing  errCode = "EMAIL_TEMPLATE_PARSING_ERROR"
+	CodeEventDecodingFailed   errCode = "EVENT_DECODING_FAILED_ERROR"
+	CodeEventPublishingFailed errCode = "EVENT_PUBLISHING_FAILED_ERROR"
+	CodeTypeAssertionFailed   errCode = "TYPE_ASSERTION_FAILED_ERROR"
+)
+
+// error messages (for traces, nothing is returned to end-users)
+const (
+	MsgDeliveryFailed  string = "Failed to deliver notification"
+	MsgInternalFailure string = "Internal failure"
+	MsgInvalidEvent    string = "Invalid event"
+)
+
+// internal error logs
+var (
+	ErrCacheNil            error = redis.Nil
+	ErrDeliveryInProgress  error = errors.New("delivery in progress elsewhere")
+	ErrTypeAssertionFailed error = errors.New("type assertion failed")
+)
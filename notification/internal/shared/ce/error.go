@@ -0,0 +1,42 @@
+package ce
+
+import (
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Error struct {
+	Code    errCode
+	Message string
+	Err     error
+}
+
+func NewError(span trace.Span, code errCode, message string, err error) *Error {
+	if span != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, message)
+	}
+
+	return &Error{
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// retrying cannot change the outcome of these
+func (e *Error) IsPermanent() bool {
+	switch e.Code {
+	case
+		CodeEmailRejected,
+		CodeEmailTemplateParsing,
+		CodeEventDecodingFailed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,29 @@
+package constants
+
+const (
+	EventTypeAuthRegistered         string = "AUTH_REGISTERED"
+	EventTypeSessionReuseDetected   string = "SESSION_REUSE_DETECTED"
+	EventTypeNewSignInDetected      string = "NEW_SIGN_IN_DETECTED"
+	EventTypeVerificationRequested  string = "VERIFICATION_REQUESTED"
+	EventTypeEmailChangeRequested   string = "EMAIL_CHANGE_REQUESTED"
+	EventTypeEmailChanged           string = "EMAIL_CHANGED"
+	EventTypePasswordResetRequested string = "PASSWORD_RESET_REQUESTED"
+	EventTypePasswordChanged        string = "PASSWORD_CHANGED"
+	EventTypeAccountVerified        string = "ACCOUNT_VERIFIED"
+	EventTypeUnlockRequested        string = "UNLOCK_REQUESTED"
+	EventTypeMagicLinkRequested     string = "MAGIC_LINK_REQUESTED"
+	EventTypeLoginCodeRequested     string = "LOGIN_CODE_REQUESTED"
+	EventTypeOAuthLinked            string = "OAUTH_LINKED"
+	EventTypeDeletionScheduled      string = "ACCOUNT_DELETION_SCHEDULED"
+	EventTypeDeletionCancelled      string = "ACCOUNT_DELETION_CANCELLED"
+	EventTypeAccountDeleted         string = "ACCOUNT_DELETED"
+)
+
+// headers added to messages moved to the dead-letter topic
+const (
+	HeaderDeadLetterAttempts  string = "dlq_attempts"
+	HeaderDeadLetterError     string = "dlq_error"
+	HeaderDeadLetterOffset    string = "dlq_source_offset"
+	HeaderDeadLetterPartition string = "dlq_source_partition"
+	HeaderDeadLetterTopic     string = "dlq_source_topic"
+)
@@ -0,0 +1,20 @@
+package constants
+
+const (
+	TemplateAccountDeleted    string = "account_deleted"
+	TemplateAccountVerified   string = "account_verified"
+	TemplateDeletionCancelled string = "deletion_cancelled"
+	TemplateDeletionScheduled string = "deletion_scheduled"
+	TemplateEmailChange       string = "email_change"
+	TemplateEmailChanged      string = "email_changed"
+	TemplateLoginCode         string = "login_code"
+	TemplateMagicLink         string = "magic_link"
+	TemplateNewSignIn         string = "new_sign_in"
+	TemplateOAuthLinked       string = "oauth_linked"
+	TemplatePasswordChanged   string = "password_changed"
+	TemplatePasswordReset     string = "password_reset"
+	TemplateSessionReuse      string = "session_reuse"
+	TemplateUnlock            string = "unlock"
+	TemplateVerification      string = "verification"
+	TemplateWelcome           string = "welcome"
+)
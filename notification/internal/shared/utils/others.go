@@ -0,0 +1,7 @@
+package utils
+
+import "strings"
+
+func Normalize(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
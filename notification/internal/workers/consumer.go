@@ -0,0 +1,68 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/notification/internal/app/usecases"
+	"github.com/ritchieridanko/apotekly-api/notification/internal/services/broker"
+)
+
+// a message that cannot even be moved aside is tried again after a pause
+const consumerRetryInterval time.Duration = 5 * time.Second
+
+type ConsumerWorker struct {
+	consumer *broker.Consumer
+	nu       usecases.NotificationUsecase
+}
+
+func NewConsumerWorker(consumer *broker.Consumer, nu usecases.NotificationUsecase) *ConsumerWorker {
+	return &ConsumerWorker{consumer, nu}
+}
+
+func (w *ConsumerWorker) Run(ctx context.Context) {
+	for {
+		message, err := w.consumer.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Println("WARNING ->", err.Error())
+			if !w.wait(ctx) {
+				return
+			}
+			continue
+		}
+
+		// the offset only moves on once the message is delivered or dead-lettered,
+		// so nothing is skipped within the partition
+		for {
+			err := w.nu.Process(ctx, message)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			log.Println("WARNING ->", err.Error())
+			if !w.wait(ctx) {
+				return
+			}
+		}
+
+		if err := w.consumer.Commit(ctx, message); err != nil {
+			// non-fatal: the message comes again and is deduplicated
+			log.Println("WARNING ->", err.Error())
+		}
+	}
+}
+
+func (w *ConsumerWorker) wait(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(consumerRetryInterval):
+		return true
+	}
+}
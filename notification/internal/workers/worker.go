@@ -0,0 +1,7 @@
+package workers
+
+import "context"
+
+type Worker interface {
+	Run(ctx context.Context)
+}
@@ -1,17 +1,37 @@
 package config
 
 type authConfig struct {
-	JWTSecret string
+	JWKSURL      string
+	JWKSCacheTTL int
+
+	DenylistLocalTTL        int
+	DenylistLocalMaxEntries int
 }
 
 var authCfg *authConfig
 
 func loadAuthConfig() {
 	authCfg = &authConfig{
-		JWTSecret: getEnv("JWT_SECRET"),
+		JWKSURL:      getEnv("AUTH_JWKS_URL"),
+		JWKSCacheTTL: getNumberEnvWithFallback("AUTH_JWKS_CACHE_TTL", 300),
+
+		DenylistLocalTTL:        getNumberEnvWithFallback("AUTH_DENYLIST_LOCAL_TTL", 5),
+		DenylistLocalMaxEntries: getNumberEnvWithFallback("AUTH_DENYLIST_LOCAL_MAX_ENTRIES", 10000),
 	}
 }
 
-func AuthGetJWTSecret() (secret string) {
-	return authCfg.JWTSecret
+func AuthGetJWKSURL() (url string) {
+	return authCfg.JWKSURL
+}
+
+func AuthGetJWKSCacheTTL() (ttl int) {
+	return authCfg.JWKSCacheTTL
+}
+
+func AuthGetDenylistLocalTTL() (ttl int) {
+	return authCfg.DenylistLocalTTL
+}
+
+func AuthGetDenylistLocalMaxEntries() (maxEntries int) {
+	return authCfg.DenylistLocalMaxEntries
 }
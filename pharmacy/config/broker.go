@@ -0,0 +1,55 @@
+package config
+
+type brokerConfig struct {
+	Brokers         string
+	GroupID         string
+	TopicAuthEvents string
+	TopicDeadLetter string
+
+	ConsumerMaxAttempts int
+	ConsumerBaseDelay   int
+	ConsumerMaxDelay    int
+}
+
+var brokerCfg *brokerConfig
+
+func loadBrokerConfig() {
+	brokerCfg = &brokerConfig{
+		Brokers:         getEnvWithFallback("BROKER_BROKERS", "localhost:9092"),
+		GroupID:         getEnvWithFallback("BROKER_GROUP_ID", "pharmacy-service"),
+		TopicAuthEvents: getEnvWithFallback("BROKER_TOPIC_AUTH_EVENTS", "auth-events"),
+		TopicDeadLetter: getEnvWithFallback("BROKER_TOPIC_DEAD_LETTER", "auth-events-pharmacy-dlq"),
+
+		ConsumerMaxAttempts: getNumberEnvWithFallback("BROKER_CONSUMER_MAX_ATTEMPTS", 5),
+		ConsumerBaseDelay:   getNumberEnvWithFallback("BROKER_CONSUMER_BASE_DELAY", 1),
+		ConsumerMaxDelay:    getNumberEnvWithFallback("BROKER_CONSUMER_MAX_DELAY", 30),
+	}
+}
+
+func BrokerGetBrokers() (brokers string) {
+	return brokerCfg.Brokers
+}
+
+func BrokerGetGroupID() (groupID string) {
+	return brokerCfg.GroupID
+}
+
+func BrokerGetTopicAuthEvents() (topic string) {
+	return brokerCfg.TopicAuthEvents
+}
+
+func BrokerGetTopicDeadLetter() (topic string) {
+	return brokerCfg.TopicDeadLetter
+}
+
+func BrokerGetConsumerMaxAttempts() (max int) {
+	return brokerCfg.ConsumerMaxAttempts
+}
+
+func BrokerGetConsumerBaseDelay() (delay int) {
+	return brokerCfg.ConsumerBaseDelay
+}
+
+func BrokerGetConsumerMaxDelay() (delay int) {
+	return brokerCfg.ConsumerMaxDelay
+}
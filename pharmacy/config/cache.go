@@ -0,0 +1,29 @@
+package config
+
+type cacheConfig struct {
+	Host string
+	Port string
+	Pass string
+}
+
+var cacheCfg *cacheConfig
+
+func loadCacheConfig() {
+	cacheCfg = &cacheConfig{
+		Host: getEnvWithFallback("CACHE_HOST", "localhost"),
+		Port: getEnvWithFallback("CACHE_PORT", "6379"),
+		Pass: getEnv("CACHE_PASS"),
+	}
+}
+
+func CacheGetHost() (host string) {
+	return cacheCfg.Host
+}
+
+func CacheGetPort() (port string) {
+	return cacheCfg.Port
+}
+
+func CacheGetPass() (pass string) {
+	return cacheCfg.Pass
+}
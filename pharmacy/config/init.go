@@ -13,6 +13,8 @@ func Initialize() {
 
 	loadAppConfig()
 	loadAuthConfig()
+	loadBrokerConfig()
+	loadCacheConfig()
 	loadDBConfig()
 	loadServerConfig()
 	loadStorageConfig()
@@ -15,20 +15,25 @@ const (
 	CodeAuthNotFound         internalErrorCode = "AUTH_NOT_FOUND_ERROR"
 	CodeAuthNotVerified      internalErrorCode = "AUTH_NOT_VERIFIED_ERROR"
 	CodeAuthTokenExpired     internalErrorCode = "AUTH_TOKEN_EXPIRED_ERROR"
+	CodeAuthTokenInvalid     internalErrorCode = "AUTH_TOKEN_INVALID_ERROR"
 	CodeAuthTokenMalformed   internalErrorCode = "AUTH_TOKEN_MALFORMED_ERROR"
 	CodeAuthTokenParsing     internalErrorCode = "AUTH_TOKEN_PARSING_ERROR"
+	CodeAuthTokenRevoked     internalErrorCode = "AUTH_TOKEN_REVOKED_ERROR"
 	CodeAuthUnauthenticated  internalErrorCode = "AUTH_UNAUTHENTICATED_ERROR"
 	CodeContextValueNotFound internalErrorCode = "CONTEXT_VALUE_NOT_FOUND_ERROR"
 	CodeDBDuplicateData      internalErrorCode = "DB_DUPLICATE_DATA_ERROR"
 	CodeDBQueryExecution     internalErrorCode = "DB_QUERY_EXECUTION_ERROR"
 	CodeDBTransaction        internalErrorCode = "DB_TRANSACTION_ERROR"
 	CodeFileBuffer           internalErrorCode = "FILE_BUFFER_ERROR"
+	CodeFileDestroyFailed    internalErrorCode = "FILE_DESTROY_FAILED_ERROR"
 	CodeFileUploadFailed     internalErrorCode = "FILE_UPLOAD_FAILED_ERROR"
 	CodeInvalidParams        internalErrorCode = "INVALID_PARAMS_ERROR"
 	CodeInvalidPayload       internalErrorCode = "INVALID_PAYLOAD_ERROR"
 	CodePharmacyNotFound     internalErrorCode = "PHARMACY_NOT_FOUND_ERROR"
 	CodeRequestFile          internalErrorCode = "REQUEST_FILE_ERROR"
+	CodeRoleNotApproved      internalErrorCode = "ROLE_NOT_APPROVED_ERROR"
 	CodeRoleUnauthorized     internalErrorCode = "ROLE_UNAUTHORIZED_ERROR"
+	CodeScopeUnauthorized    internalErrorCode = "SCOPE_UNAUTHORIZED_ERROR"
 )
 
 // external error messages (for end-users)
@@ -39,6 +44,7 @@ const (
 	MsgInvalidPayload     string = "Invalid payload."
 	MsgNoFieldsToUpdate   string = "No fields to update."
 	MsgPharmacyNotFound   string = "Pharmacy not found."
+	MsgScopeUnauthorized  string = "Insufficient scope."
 	MsgUnauthenticated    string = "Unauthenticated."
 )
 
@@ -49,4 +55,6 @@ var (
 	ErrNoFieldsProvided error = errors.New("no fields provided")
 	ErrTokenExpired     error = jwt.ErrTokenExpired
 	ErrTokenMalformed   error = jwt.ErrTokenMalformed
+	ErrTokenRevoked     error = errors.New("token revoked")
+	ErrTokenSignature   error = jwt.ErrTokenSignatureInvalid
 )
@@ -39,11 +39,13 @@ func (e *Error) ToExternalErrorCode() (externalCode int) {
 		CodeAuthAudienceNotFound,
 		CodeAuthNotFound,
 		CodeAuthTokenExpired,
+		CodeAuthTokenInvalid,
 		CodeAuthTokenMalformed,
+		CodeAuthTokenRevoked,
 		CodeAuthUnauthenticated,
 		CodeRoleUnauthorized:
 		return http.StatusUnauthorized
-	case CodeAuthNotVerified:
+	case CodeAuthNotVerified, CodeRoleNotApproved, CodeScopeUnauthorized:
 		return http.StatusForbidden
 	case CodePharmacyNotFound:
 		return http.StatusNotFound
@@ -55,6 +57,7 @@ func (e *Error) ToExternalErrorCode() (externalCode int) {
 		CodeDBQueryExecution,
 		CodeDBTransaction,
 		CodeFileBuffer,
+		CodeFileDestroyFailed,
 		CodeFileUploadFailed,
 		CodeRequestFile:
 		return http.StatusInternalServerError
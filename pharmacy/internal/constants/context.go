@@ -4,9 +4,13 @@ type ctxKey string
 type ctxKeyTx struct{}
 
 const (
-	CtxKeyAuthID     ctxKey = "auth-id"
-	CtxKeyRoleID     ctxKey = "role-id"
-	CtxKeyIsVerified ctxKey = "is-verified"
+	CtxKeyAuthID         ctxKey = "auth-id"
+	CtxKeyRoleID         ctxKey = "role-id"
+	CtxKeyIsVerified     ctxKey = "is-verified"
+	CtxKeyIsApproved     ctxKey = "is-approved"
+	CtxKeyImpersonatorID ctxKey = "impersonator-id"
+	CtxKeyClientID       ctxKey = "client-id"
+	CtxKeyScopes         ctxKey = "scopes"
 )
 
 var (
@@ -2,27 +2,42 @@ package di
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/config"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/handlers"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/repos"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/routers"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/services/broker"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/services/db"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/services/denylist"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/services/jwks"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/services/storage"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/usecases"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/workers"
+	"github.com/segmentio/kafka-go"
 )
 
-func SetupDependencies(dbInstance *sql.DB, cloudInstance *cloudinary.Cloudinary) (router *gin.Engine) {
+func SetupDependencies(dbInstance *sql.DB, cacheInstance *redis.Client, cloudInstance *cloudinary.Cloudinary, brokerInstance *kafka.Reader, writerInstance *kafka.Writer) (router *gin.Engine, ws []workers.Worker) {
 	database := db.NewService(dbInstance)
 	txManager := db.NewTxManager(dbInstance)
 	storage := storage.NewService(cloudInstance)
+	denylist := denylist.NewService(cacheInstance)
+	jwks := jwks.NewService(config.AuthGetJWKSURL(), time.Duration(config.AuthGetJWKSCacheTTL())*time.Second)
+	consumer := broker.NewConsumerService(brokerInstance)
+	producer := broker.NewProducerService(writerInstance)
 
 	pr := repos.NewPharmacyRepo(database)
 
 	pu := usecases.NewPharmacyUsecase(pr, txManager, storage)
+	eu := usecases.NewEventUsecase(pu)
 
 	ph := handlers.NewPharmacyHandler(pu)
 
-	return routers.Initialize(ph)
+	cw := workers.NewConsumerWorker(consumer, producer, eu)
+
+	return routers.Initialize(ph, denylist, jwks), []workers.Worker{cw}
 }
@@ -3,8 +3,17 @@ package entities
 import "github.com/golang-jwt/jwt/v5"
 
 type Claim struct {
-	AuthID     int64
-	RoleID     int16
-	IsVerified bool
+	AuthID      int64
+	RoleID      int16
+	IsVerified  bool
+	IsApproved  bool
+	SubjectType string `json:"sub_type,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+	Act         *Actor `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
+
+// the admin acting as the subject, only set on impersonation tokens
+type Actor struct {
+	AuthID int64
+}
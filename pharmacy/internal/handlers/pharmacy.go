@@ -5,6 +5,7 @@ import (
 	"log"
 	"mime/multipart"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -24,6 +25,7 @@ const pharmacyErrorTracer string = "handler.pharmacy"
 type PharmacyHandler interface {
 	NewPharmacy(ctx *gin.Context)
 	GetPharmacy(ctx *gin.Context)
+	GetPharmacyByAuthID(ctx *gin.Context)
 	UpdatePharmacy(ctx *gin.Context)
 	ChangeLogo(ctx *gin.Context)
 }
@@ -138,6 +140,28 @@ func (h *pharmacyHandler) GetPharmacy(ctx *gin.Context) {
 	utils.SetResponse(ctx, "ok", response, http.StatusOK)
 }
 
+func (h *pharmacyHandler) GetPharmacyByAuthID(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(pharmacyErrorTracer).Start(ctx.Request.Context(), "GetPharmacyByAuthID")
+	defer span.End()
+
+	authID, err := strconv.ParseInt(ctx.Param("auth_id"), 10, 64)
+	if err != nil {
+		err := ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, err)
+		ctx.Error(err)
+		return
+	}
+
+	pharmacy, err := h.pu.GetPharmacy(ctxWithTracer, authID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := h.setPharmacyAsResponse(*pharmacy)
+
+	utils.SetResponse(ctx, "ok", response, http.StatusOK)
+}
+
 func (h *pharmacyHandler) UpdatePharmacy(ctx *gin.Context) {
 	ctxWithTracer, span := otel.Tracer(pharmacyErrorTracer).Start(ctx.Request.Context(), "UpdatePharmacy")
 	defer span.End()
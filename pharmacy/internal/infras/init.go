@@ -5,16 +5,24 @@ import (
 	"log"
 
 	c "github.com/cloudinary/cloudinary-go/v2"
+	r "github.com/redis/go-redis/v9"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/infras/cloudinary"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/infras/kafka"
 	ot "github.com/ritchieridanko/apotekly-api/pharmacy/internal/infras/open_telemetry"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/infras/postgresql"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/infras/redis"
+	k "github.com/segmentio/kafka-go"
 )
 
-func Initialize() (db *sql.DB, tracer *ot.Tracer, storage *c.Cloudinary) {
+func Initialize() (db *sql.DB, cache *r.Client, tracer *ot.Tracer, storage *c.Cloudinary, consumer *k.Reader, producer *k.Writer) {
 	db, err := postgresql.Connect()
 	if err != nil {
 		log.Fatalln("FATAL -> failed to connect to database:", err.Error())
 	}
+	cache, err = redis.Connect()
+	if err != nil {
+		log.Fatalln("FATAL -> failed to connect to cache:", err.Error())
+	}
 	tracer, err = ot.Initialize()
 	if err != nil {
 		log.Fatalln("FATAL -> failed to initialize tracer:", err.Error())
@@ -23,5 +31,6 @@ func Initialize() (db *sql.DB, tracer *ot.Tracer, storage *c.Cloudinary) {
 	if err != nil {
 		log.Fatalln("FATAL -> failed to initialize cloudinary:", err.Error())
 	}
-	return db, tracer, storage
+	consumer, producer = kafka.Initialize()
+	return db, cache, tracer, storage, consumer, producer
 }
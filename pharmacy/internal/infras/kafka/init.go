@@ -0,0 +1,26 @@
+package kafka
+
+import (
+	"strings"
+
+	"github.com/ritchieridanko/apotekly-api/pharmacy/config"
+	"github.com/segmentio/kafka-go"
+)
+
+func Initialize() (consumer *kafka.Reader, producer *kafka.Writer) {
+	// offsets are committed by hand, only once a message has been dealt with
+	consumer = kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     strings.Split(config.BrokerGetBrokers(), ","),
+		GroupID:     config.BrokerGetGroupID(),
+		Topic:       config.BrokerGetTopicAuthEvents(),
+		StartOffset: kafka.FirstOffset,
+	})
+
+	producer = &kafka.Writer{
+		Addr:         kafka.TCP(strings.Split(config.BrokerGetBrokers(), ",")...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}
+
+	return consumer, producer
+}
@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/config"
+)
+
+func Connect() (client *redis.Client, err error) {
+	if config.CacheGetPass() == "" {
+		log.Println("WARNING -> connecting to cache without password")
+	}
+
+	client = redis.NewClient(&redis.Options{
+		Addr:     config.CacheGetHost() + ":" + config.CacheGetPort(),
+		Password: config.CacheGetPass(),
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err = client.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+
+	log.Println("SUCCESS -> connected to cache")
+	return client, nil
+}
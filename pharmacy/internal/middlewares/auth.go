@@ -3,18 +3,25 @@ package middlewares
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/ce"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/constants"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/services/denylist"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/services/jwks"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/utils"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const authErrorTracer string = "middleware.auth"
 
-func Authenticate() gin.HandlerFunc {
+func Authenticate(ds denylist.DenylistService, js jwks.JWKSService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "Authenticate")
 		defer span.End()
@@ -41,13 +48,15 @@ func Authenticate() gin.HandlerFunc {
 			return
 		}
 
-		claim, err := utils.ParseJWTToken(authSlice[1])
+		claim, err := utils.ParseJWTToken(authSlice[1], js.Keyfunc)
 		if err != nil {
 			switch {
 			case errors.Is(err, ce.ErrTokenExpired):
 				err = ce.NewError(span, ce.CodeAuthTokenExpired, ce.MsgUnauthenticated, err)
 			case errors.Is(err, ce.ErrTokenMalformed):
 				err = ce.NewError(span, ce.CodeAuthTokenMalformed, ce.MsgUnauthenticated, err)
+			case errors.Is(err, ce.ErrTokenSignature), errors.Is(err, jwks.ErrKeyNotFound):
+				err = ce.NewError(span, ce.CodeAuthTokenInvalid, ce.MsgUnauthenticated, err)
 			default:
 				err = ce.NewError(span, ce.CodeAuthTokenParsing, ce.MsgInternalServer, err)
 			}
@@ -64,9 +73,45 @@ func Authenticate() gin.HandlerFunc {
 			return
 		}
 
+		isDenied, err := ds.IsDenied(ctxWithTracer, claim.ID, claim.ExpiresAt.Time)
+		if err != nil {
+			// non-fatal: fails open while the cache is down
+			span.AddEvent(
+				"token denylist check failed",
+				trace.WithAttributes(attribute.String("error", err.Error())),
+			)
+		}
+		if isDenied {
+			err := ce.NewError(span, ce.CodeAuthTokenRevoked, ce.MsgUnauthenticated, ce.ErrTokenRevoked)
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+
+		if claim.SubjectType == constants.SubjectTypeService {
+			// no account behind it, Authorize turns it away
+			span.SetAttributes(attribute.String("client_id", claim.Subject))
+			ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyClientID, claim.Subject)
+			ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyScopes, strings.Fields(claim.Scope))
+
+			ctx.Request = ctx.Request.WithContext(ctxWithTracer)
+			ctx.Next()
+			return
+		}
+
 		ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyAuthID, claim.AuthID)
 		ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyRoleID, claim.RoleID)
 		ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyIsVerified, claim.IsVerified)
+		ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyIsApproved, claim.IsApproved)
+		if claim.Act != nil {
+			// an admin acting as the owner
+			log.Printf(
+				"IMPERSONATION -> admin %d acting as auth %d: %s %s",
+				claim.Act.AuthID, claim.AuthID, ctx.Request.Method, ctx.Request.URL.Path,
+			)
+			span.SetAttributes(attribute.Int64("impersonator_id", claim.Act.AuthID))
+			ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyImpersonatorID, claim.Act.AuthID)
+		}
 
 		ctx.Request = ctx.Request.WithContext(ctxWithTracer)
 		ctx.Next()
@@ -108,3 +153,39 @@ func Authorize() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// a pending owner can already set up the profile, the operational routes wait for the approval
+func RequireApproved() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "RequireApproved")
+		defer span.End()
+
+		isApproved, _ := ctxWithTracer.Value(constants.CtxKeyIsApproved).(bool)
+		if !isApproved {
+			err := ce.NewError(span, ce.CodeRoleNotApproved, "Account is pending approval.", errors.New("role not approved"))
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func AuthorizeScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "AuthorizeScope")
+		defer span.End()
+
+		value := ctxWithTracer.Value(constants.CtxKeyScopes)
+		scopes, ok := value.([]string)
+		if !ok || !slices.Contains(scopes, scope) {
+			err := ce.NewError(span, ce.CodeScopeUnauthorized, ce.MsgScopeUnauthorized, fmt.Errorf("scope %q missing", scope))
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -22,6 +22,7 @@ type PharmacyRepo interface {
 	UpdatePharmacy(ctx context.Context, authID int64, data *entities.PharmacyChange) (pharmacy *entities.Pharmacy, err error)
 	UpdateLogo(ctx context.Context, authID int64, logo string) (err error)
 	HasPharmacy(ctx context.Context, authID int64) (exists bool, err error)
+	Anonymize(ctx context.Context, authID int64) (err error)
 }
 
 type pharmacyRepo struct {
@@ -299,6 +300,30 @@ func (r *pharmacyRepo) UpdateLogo(ctx context.Context, authID int64, logo string
 	return nil
 }
 
+func (r *pharmacyRepo) Anonymize(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(pharmacyErrorTracer).Start(ctx, "Anonymize")
+	defer span.End()
+
+	// the licence stays on record, contact details and media are dropped
+	query := `
+		UPDATE pharmacies
+		SET
+			description = NULL, email = NULL, phone = NULL, website = NULL,
+			logo = NULL, status = 'CLOSE', is_active = FALSE,
+			updated_at = NOW(), deleted_at = NOW()
+		WHERE auth_id = $1 AND deleted_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, authID); err != nil {
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodePharmacyNotFound, ce.MsgPharmacyNotFound, err)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, err)
+	}
+
+	return nil
+}
+
 func (r *pharmacyRepo) HasPharmacy(ctx context.Context, authID int64) (bool, error) {
 	ctx, span := otel.Tracer(pharmacyErrorTracer).Start(ctx, "HasPharmacy")
 	defer span.End()
@@ -6,10 +6,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/handlers"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/middlewares"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/services/denylist"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/services/jwks"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func Initialize(ph handlers.PharmacyHandler) *gin.Engine {
+func Initialize(ph handlers.PharmacyHandler, ds denylist.DenylistService, js jwks.JWKSService) *gin.Engine {
 	router := gin.New()
 
 	router.Use(otelgin.Middleware("app.pharmacy"))
@@ -25,7 +27,7 @@ func Initialize(ph handlers.PharmacyHandler) *gin.Engine {
 		ctx.JSON(http.StatusOK, gin.H{"message": "pong"})
 	})
 
-	pharmacy := pharmacyRouters(ph)
+	pharmacy := pharmacyRouters(ph, ds, js)
 	pharmacy(api.Group("/pharmacies"))
 
 	return router
@@ -2,17 +2,21 @@ package routers
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/constants"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/handlers"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/middlewares"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/services/denylist"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/services/jwks"
 )
 
-func pharmacyRouters(h handlers.PharmacyHandler) func(*gin.RouterGroup) {
+func pharmacyRouters(h handlers.PharmacyHandler, ds denylist.DenylistService, js jwks.JWKSService) func(*gin.RouterGroup) {
 	return func(rg *gin.RouterGroup) {
-		rg.GET("/me", middlewares.Authenticate(), middlewares.Authorize(), middlewares.RequireVerified(), h.GetPharmacy)
+		rg.GET("/me", middlewares.Authenticate(ds, js), middlewares.Authorize(), middlewares.RequireVerified(), middlewares.RequireApproved(), h.GetPharmacy)
+		rg.GET("/:auth_id", middlewares.Authenticate(ds, js), middlewares.AuthorizeScope(constants.ScopePharmaciesRead), h.GetPharmacyByAuthID)
 
-		rg.POST("", middlewares.Authenticate(), middlewares.Authorize(), middlewares.RequireVerified(), h.NewPharmacy)
+		rg.POST("", middlewares.Authenticate(ds, js), middlewares.Authorize(), middlewares.RequireVerified(), h.NewPharmacy)
 
-		rg.PATCH("/me", middlewares.Authenticate(), middlewares.Authorize(), middlewares.RequireVerified(), h.UpdatePharmacy)
-		rg.PATCH("/me/logo", middlewares.Authenticate(), middlewares.Authorize(), middlewares.RequireVerified(), h.ChangeLogo)
+		rg.PATCH("/me", middlewares.Authenticate(ds, js), middlewares.Authorize(), middlewares.RequireVerified(), middlewares.RequireApproved(), h.UpdatePharmacy)
+		rg.PATCH("/me/logo", middlewares.Authenticate(ds, js), middlewares.Authorize(), middlewares.RequireVerified(), middlewares.RequireApproved(), h.ChangeLogo)
 	}
 }
@@ -0,0 +1,154 @@
+package routers
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/config"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/constants"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/handlers"
+)
+
+const testAppName string = "app.pharmacy"
+
+const testEnv string = `APP_NAME="app.pharmacy"
+APP_VERSION=""
+APP_DESCRIPTION=""
+AUTH_JWKS_URL=""
+CACHE_PASS=""
+DB_HOST=""
+DB_PORT=""
+DB_USER=""
+DB_PASS=""
+DB_NAME=""
+DB_SSL_MODE=""
+DB_MAX_IDLE_CONNS=1
+DB_MAX_OPEN_CONNS=1
+DB_CONN_MAX_LIFETIME=1
+STORAGE_CLOUD_NAME=""
+STORAGE_API_KEY=""
+STORAGE_API_SECRET=""
+`
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "pharmacy-routers")
+	if err != nil {
+		log.Fatalln("FATAL -> failed to create temp dir:", err.Error())
+	}
+	if err := os.WriteFile(dir+"/.env", []byte(testEnv), 0o600); err != nil {
+		log.Fatalln("FATAL -> failed to write .env file:", err.Error())
+	}
+
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		log.Fatalln("FATAL -> failed to enter temp dir:", err.Error())
+	}
+	config.Initialize()
+	os.Chdir(wd)
+	os.RemoveAll(dir)
+
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+type stubJWKS struct {
+	key ed25519.PublicKey
+}
+
+func (s *stubJWKS) Keyfunc(t *jwt.Token) (interface{}, error) {
+	return s.key, nil
+}
+
+type stubDenylist struct{}
+
+func (s *stubDenylist) IsDenied(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	return false, nil
+}
+
+type stubPharmacyHandler struct{}
+
+func (h *stubPharmacyHandler) NewPharmacy(ctx *gin.Context)         { ctx.Status(http.StatusCreated) }
+func (h *stubPharmacyHandler) GetPharmacy(ctx *gin.Context)         { ctx.Status(http.StatusOK) }
+func (h *stubPharmacyHandler) GetPharmacyByAuthID(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+func (h *stubPharmacyHandler) UpdatePharmacy(ctx *gin.Context)      { ctx.Status(http.StatusOK) }
+func (h *stubPharmacyHandler) ChangeLogo(ctx *gin.Context)          { ctx.Status(http.StatusOK) }
+
+var _ handlers.PharmacyHandler = (*stubPharmacyHandler)(nil)
+
+func newTestRouter(t *testing.T) (router *gin.Engine, key ed25519.PrivateKey) {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	router = Initialize(&stubPharmacyHandler{}, &stubDenylist{}, &stubJWKS{key: public})
+	return router, private
+}
+
+func signOwnerToken(t *testing.T, key ed25519.PrivateKey, isApproved bool) (token string) {
+	t.Helper()
+
+	claim := entities.Claim{
+		AuthID:     1,
+		RoleID:     constants.RolePharmacy,
+		IsVerified: true,
+		IsApproved: isApproved,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti",
+			Audience:  jwt.ClaimStrings{testAppName},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claim)
+	jwtToken.Header["kid"] = "test"
+
+	token, err := jwtToken.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+func TestPharmacyRoutersRequireApproval(t *testing.T) {
+	router, key := newTestRouter(t)
+
+	tests := []struct {
+		method     string
+		path       string
+		isApproved bool
+		wantStatus int
+	}{
+		{http.MethodGet, "/api/v1/pharmacies/me", false, http.StatusForbidden},
+		{http.MethodPatch, "/api/v1/pharmacies/me", false, http.StatusForbidden},
+		{http.MethodPatch, "/api/v1/pharmacies/me/logo", false, http.StatusForbidden},
+		{http.MethodPost, "/api/v1/pharmacies", false, http.StatusCreated},
+		{http.MethodGet, "/api/v1/pharmacies/me", true, http.StatusOK},
+		{http.MethodPatch, "/api/v1/pharmacies/me", true, http.StatusOK},
+		{http.MethodPatch, "/api/v1/pharmacies/me/logo", true, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.Header.Set("Authorization", "Bearer "+signOwnerToken(t, key, tt.isApproved))
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s %s (approved: %t): got status %d, want %d", tt.method, tt.path, tt.isApproved, rec.Code, tt.wantStatus)
+		}
+	}
+}
@@ -12,16 +12,21 @@ import (
 
 	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/config"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/di"
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/infras"
+	"github.com/segmentio/kafka-go"
 )
 
 type App struct {
 	router  *gin.Engine
 	server  *http.Server
 	db      *sql.DB
+	cache   *redis.Client
 	storage *cloudinary.Cloudinary
+	broker  *kafka.Reader
+	writer  *kafka.Writer
 }
 
 func New() *App {
@@ -33,16 +38,30 @@ func (a *App) Run() {
 	config.Initialize()
 
 	// initialize infrastructures
-	db, tracer, storage := infras.Initialize()
+	db, cache, tracer, storage, broker, writer := infras.Initialize()
 	a.db = db
+	a.cache = cache
 	a.storage = storage
+	a.broker = broker
+	a.writer = writer
 	defer a.db.Close()
+	defer a.cache.Close()
+	defer a.broker.Close()
+	defer a.writer.Close()
 	defer tracer.Cleanup()
 
 	// initialize dependencies
-	router := di.SetupDependencies(a.db, a.storage)
+	router, workers := di.SetupDependencies(a.db, a.cache, a.storage, a.broker, a.writer)
 	a.router = router
 
+	// start workers, they stop once the context is cancelled on shutdown
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	for _, w := range workers {
+		go w.Run(workerCtx)
+	}
+
 	// create HTTP server
 	a.server = &http.Server{
 		Addr:    config.ServerGetBaseURL(),
@@ -64,6 +83,8 @@ func (a *App) Run() {
 
 	log.Println("SHUTTING DOWN SERVER...")
 
+	stopWorkers()
+
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.ServerGetTimeout())*time.Second)
 	defer cancel()
 
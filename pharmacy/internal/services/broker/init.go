@@ -0,0 +1,52 @@
+package broker
+
+import (
+	"context"
+
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/entities"
+	"github.com/segmentio/kafka-go"
+)
+
+type ConsumerService interface {
+	Fetch(ctx context.Context) (message *entities.Message, err error)
+	Commit(ctx context.Context, message *entities.Message) (err error)
+}
+
+type consumerService struct {
+	instance *kafka.Reader
+}
+
+func NewConsumerService(instance *kafka.Reader) ConsumerService {
+	return &consumerService{instance}
+}
+
+func (cs *consumerService) Fetch(ctx context.Context) (*entities.Message, error) {
+	m, err := cs.instance.FetchMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(map[string]string, len(m.Headers))
+	for _, h := range m.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+
+	message := entities.Message{
+		Topic:     m.Topic,
+		Partition: m.Partition,
+		Offset:    m.Offset,
+		Key:       string(m.Key),
+		Value:     m.Value,
+		Headers:   headers,
+	}
+
+	return &message, nil
+}
+
+func (cs *consumerService) Commit(ctx context.Context, message *entities.Message) error {
+	return cs.instance.CommitMessages(ctx, kafka.Message{
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    message.Offset,
+	})
+}
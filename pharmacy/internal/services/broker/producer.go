@@ -0,0 +1,32 @@
+package broker
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type ProducerService interface {
+	PublishEncoded(ctx context.Context, topic, key string, value []byte, headers map[string]string) (err error)
+}
+
+type producerService struct {
+	instance *kafka.Writer
+}
+
+func NewProducerService(instance *kafka.Writer) ProducerService {
+	return &producerService{instance}
+}
+
+func (ps *producerService) PublishEncoded(ctx context.Context, topic, key string, value []byte, headers map[string]string) error {
+	message := kafka.Message{
+		Topic: topic,
+		Key:   []byte(key),
+		Value: value,
+	}
+	for k, v := range headers {
+		message.Headers = append(message.Headers, kafka.Header{Key: k, Value: []byte(v)})
+	}
+
+	return ps.instance.WriteMessages(ctx, message)
+}
@@ -0,0 +1,93 @@
+package denylist
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/config"
+	"go.opentelemetry.io/otel"
+)
+
+const denylistErrorTracer string = "service.denylist"
+
+// written by the auth service on logout, session revocation and password changes
+const keyPrefix string = "dl"
+
+type DenylistService interface {
+	IsDenied(ctx context.Context, jti string, expiresAt time.Time) (isDenied bool, err error)
+}
+
+type denylistService struct {
+	instance *redis.Client
+
+	// in-process view of the denylist, so not every request costs a redis hit
+	mu    sync.Mutex
+	local map[string]entry
+}
+
+type entry struct {
+	isDenied  bool
+	expiresAt time.Time
+}
+
+func NewService(instance *redis.Client) DenylistService {
+	return &denylistService{instance: instance, local: make(map[string]entry)}
+}
+
+func (ds *denylistService) IsDenied(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	ctx, span := otel.Tracer(denylistErrorTracer).Start(ctx, "IsDenied")
+	defer span.End()
+
+	if jti == "" {
+		// issued before tokens carried an id, these simply run out
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+
+	ds.mu.Lock()
+	e, ok := ds.local[jti]
+	ds.mu.Unlock()
+
+	if ok && now.Before(e.expiresAt) {
+		return e.isDenied, nil
+	}
+
+	count, err := ds.instance.Exists(ctx, fmt.Sprintf("%s:%s", keyPrefix, jti)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check token denylist: %w", err)
+	}
+	isDenied := count > 0
+
+	// a denial never expires before the token does, an allowance is only trusted briefly
+	localTTL := time.Duration(config.AuthGetDenylistLocalTTL()) * time.Second
+	e = entry{isDenied: isDenied, expiresAt: expiresAt}
+	if !isDenied && now.Add(localTTL).Before(expiresAt) {
+		e.expiresAt = now.Add(localTTL)
+	}
+	ds.remember(jti, e, now)
+
+	return isDenied, nil
+}
+
+func (ds *denylistService) remember(jti string, e entry, now time.Time) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if len(ds.local) >= config.AuthGetDenylistLocalMaxEntries() {
+		for key, v := range ds.local {
+			if !now.Before(v.expiresAt) {
+				delete(ds.local, key)
+			}
+		}
+		if len(ds.local) >= config.AuthGetDenylistLocalMaxEntries() {
+			// still full of live entries, start over rather than grow unbounded
+			ds.local = make(map[string]entry)
+		}
+	}
+
+	ds.local[jti] = e
+}
@@ -0,0 +1,154 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minimum time between two fetches, so unknown kids cannot hammer the auth service
+const minRefreshInterval time.Duration = 10 * time.Second
+
+var ErrKeyNotFound error = errors.New("signing key not found in jwks")
+
+type JWKSService interface {
+	Keyfunc(t *jwt.Token) (key interface{}, err error)
+}
+
+type jwksService struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+type jsonWebKey struct {
+	KeyType  string `json:"kty"`
+	Curve    string `json:"crv"`
+	KeyID    string `json:"kid"`
+	Modulus  string `json:"n"`
+	Exponent string `json:"e"`
+	X        string `json:"x"`
+}
+
+func NewService(url string, ttl time.Duration) JWKSService {
+	return &jwksService{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: 5 * time.Second},
+		keys:   make(map[string]interface{}),
+	}
+}
+
+func (js *jwksService) Keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrKeyNotFound
+	}
+
+	js.mu.RLock()
+	key, ok := js.keys[kid]
+	isStale := time.Since(js.fetchedAt) > js.ttl
+	canRefresh := time.Since(js.fetchedAt) > minRefreshInterval
+	js.mu.RUnlock()
+
+	// unknown kids usually mean the key was just rotated
+	if isStale || (!ok && canRefresh) {
+		if err := js.refresh(); err != nil {
+			if ok {
+				// keep verifying with the cached key while the auth service is unreachable
+				return key, nil
+			}
+			return nil, err
+		}
+
+		js.mu.RLock()
+		key, ok = js.keys[kid]
+		js.mu.RUnlock()
+	}
+
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+func (js *jwksService) refresh() error {
+	resp, err := js.client.Get(js.url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("failed to decode jwks: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(body.Keys))
+	for _, k := range body.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			// skip keys this service cannot use rather than rejecting the whole set
+			continue
+		}
+		keys[k.KeyID] = key
+	}
+
+	js.mu.Lock()
+	js.keys = keys
+	js.fetchedAt = time.Now()
+	js.mu.Unlock()
+
+	return nil
+}
+
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	switch k.KeyType {
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.Modulus)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.Exponent)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
@@ -13,6 +13,7 @@ const storageErrorTracer string = "service.storage"
 
 type StorageService interface {
 	Upload(ctx context.Context, data *entities.NewUpload) (result *uploader.UploadResult, err error)
+	Destroy(ctx context.Context, publicID string) (err error)
 }
 
 type storageService struct {
@@ -36,3 +37,17 @@ func (ss *storageService) Upload(ctx context.Context, data *entities.NewUpload)
 
 	return ss.instance.Upload.Upload(ctx, data.File, params)
 }
+
+func (ss *storageService) Destroy(ctx context.Context, publicID string) error {
+	ctx, span := otel.Tracer(storageErrorTracer).Start(ctx, "Destroy")
+	defer span.End()
+
+	invalidate := true
+	params := uploader.DestroyParams{
+		PublicID:   publicID,
+		Invalidate: &invalidate,
+	}
+
+	_, err := ss.instance.Upload.Destroy(ctx, params)
+	return err
+}
@@ -0,0 +1,56 @@
+package usecases
+
+import (
+	"context"
+	"log"
+
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/constants"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/pharmacy/pkg/events"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/protobuf/proto"
+)
+
+const eventErrorTracer string = "usecase.event"
+
+type EventUsecase interface {
+	Process(ctx context.Context, message *entities.Message) (err error)
+}
+
+type eventUsecase struct {
+	pu PharmacyUsecase
+}
+
+func NewEventUsecase(pu PharmacyUsecase) EventUsecase {
+	return &eventUsecase{pu}
+}
+
+func (u *eventUsecase) Process(ctx context.Context, message *entities.Message) error {
+	ctx, span := otel.Tracer(eventErrorTracer).Start(ctx, "Process")
+	defer span.End()
+
+	var event events.Event
+	if err := proto.Unmarshal(message.Value, &event); err != nil {
+		// retrying a malformed message never helps, so it is skipped
+		log.Println("WARNING -> failed to decode event envelope:", err.Error())
+		return nil
+	}
+	span.SetAttributes(
+		attribute.String("event.id", event.EventId),
+		attribute.String("event.type", event.EventType),
+	)
+
+	switch event.EventType {
+	case constants.EventTypeAccountDeleted:
+		var data events.AccountDeleted
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			log.Printf("WARNING -> failed to decode event %s: %s\n", event.EventType, err.Error())
+			return nil
+		}
+		return u.pu.ErasePharmacy(ctx, data.AuthId)
+	default:
+		// only events touching data held here are of interest
+		return nil
+	}
+}
@@ -27,6 +27,7 @@ type PharmacyUsecase interface {
 	GetPharmacy(ctx context.Context, authID int64) (pharmacy *entities.Pharmacy, err error)
 	UpdatePharmacy(ctx context.Context, authID int64, data *entities.PharmacyChange) (pharmacy *entities.Pharmacy, err error)
 	ChangeLogo(ctx context.Context, authID int64, image multipart.File) (err error)
+	ErasePharmacy(ctx context.Context, authID int64) (err error)
 }
 
 type pharmacyUsecase struct {
@@ -138,6 +139,37 @@ func (u *pharmacyUsecase) ChangeLogo(ctx context.Context, authID int64, image mu
 	return u.pr.UpdateLogo(ctx, authID, imageURL)
 }
 
+func (u *pharmacyUsecase) ErasePharmacy(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(pharmacyErrorTracer).Start(ctx, "ErasePharmacy")
+	defer span.End()
+
+	return u.tx.WithTx(ctx, func(ctx context.Context) error {
+		pharmacy, err := u.pr.GetByAuthID(ctx, authID)
+		if err != nil {
+			var customErr *ce.Error
+			if errors.As(err, &customErr) && customErr.Code == ce.CodePharmacyNotFound {
+				// never registered a pharmacy, or already erased on an earlier delivery
+				return nil
+			}
+			return err
+		}
+
+		if err := u.pr.Anonymize(ctx, authID); err != nil {
+			return err
+		}
+
+		// removed before commit so a failure is retried with the row still there
+		if pharmacy.Logo != nil {
+			publicID := "pharmacies/logos/" + pharmacy.PharmacyPublicID.String()
+			if err := u.storage.Destroy(ctx, publicID); err != nil {
+				return ce.NewError(span, ce.CodeFileDestroyFailed, ce.MsgInternalServer, err)
+			}
+		}
+
+		return nil
+	})
+}
+
 func (u *pharmacyUsecase) uploadImage(ctx context.Context, image multipart.File, publicID, prefix, folder string, overwrite bool) (imageURL string, err error) {
 	ctx, span := otel.Tracer(pharmacyErrorTracer).Start(ctx, "uploadImage")
 	defer span.End()
@@ -8,13 +8,12 @@ import (
 	"github.com/ritchieridanko/apotekly-api/pharmacy/internal/entities"
 )
 
-func ParseJWTToken(tokenString string) (claim *entities.Claim, err error) {
+func ParseJWTToken(tokenString string, keyfunc jwt.Keyfunc) (claim *entities.Claim, err error) {
 	token, err := jwt.ParseWithClaims(
 		tokenString,
 		&entities.Claim{},
-		func(t *jwt.Token) (interface{}, error) {
-			return []byte(config.AuthGetJWTSecret()), nil
-		},
+		keyfunc,
+		jwt.WithValidMethods([]string{"EdDSA", "RS256"}),
 	)
 	if err != nil {
 		return nil, err
@@ -27,6 +27,13 @@ func main() {
 
 	c := di.NewContainer(cfg, infra)
 
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	for _, w := range c.Workers() {
+		go w.Run(workerCtx)
+	}
+
 	s := server.NewHTTPServer(cfg, c.Router().Engine())
 	go s.Start()
 
@@ -35,6 +42,8 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
+	stopWorkers()
+
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
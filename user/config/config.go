@@ -22,7 +22,8 @@ type Config struct {
 	}
 
 	JWT struct {
-		Secret string
+		JWKSURL  string
+		CacheTTL time.Duration
 	}
 
 	Database struct {
@@ -38,6 +39,17 @@ type Config struct {
 		ConnMaxLifetime time.Duration
 	}
 
+	Cache struct {
+		Host string
+		Port int
+		Pass string
+	}
+
+	Denylist struct {
+		LocalTTL        time.Duration
+		LocalMaxEntries int
+	}
+
 	Storage struct {
 		Provider  string
 		Bucket    string
@@ -50,6 +62,21 @@ type Config struct {
 		AllowedTypes []string
 	}
 
+	Broker struct {
+		Brokers string
+		GroupID string
+		Topics  struct {
+			AuthEvents string
+			DeadLetter string
+		}
+	}
+
+	Consumer struct {
+		MaxAttempts int
+		BaseDelay   time.Duration
+		MaxDelay    time.Duration
+	}
+
 	Tracer struct {
 		Endpoint string
 	}
@@ -73,6 +100,16 @@ func Load(path string) (*Config, error) {
 	v.RegisterAlias("server.write_timeout", "server.writetimeout")
 	v.RegisterAlias("server.shutdown_timeout", "server.shutdowntimeout")
 	v.RegisterAlias("database.conn_max_lifetime", "database.connmaxlifetime")
+	v.RegisterAlias("jwt.jwks_url", "jwt.jwksurl")
+	v.RegisterAlias("jwt.cache_ttl", "jwt.cachettl")
+	v.RegisterAlias("denylist.local_ttl", "denylist.localttl")
+	v.RegisterAlias("denylist.local_max_entries", "denylist.localmaxentries")
+	v.RegisterAlias("broker.group_id", "broker.groupid")
+	v.RegisterAlias("broker.topics.auth_events", "broker.topics.authevents")
+	v.RegisterAlias("broker.topics.dead_letter", "broker.topics.deadletter")
+	v.RegisterAlias("consumer.max_attempts", "consumer.maxattempts")
+	v.RegisterAlias("consumer.base_delay", "consumer.basedelay")
+	v.RegisterAlias("consumer.max_delay", "consumer.maxdelay")
 
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
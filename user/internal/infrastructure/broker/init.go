@@ -0,0 +1,25 @@
+package broker
+
+import (
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func NewConsumer(brokers, groupID, topic string) *kafka.Reader {
+	// offsets are committed by hand, only once a message has been dealt with
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     strings.Split(brokers, ","),
+		GroupID:     groupID,
+		Topic:       topic,
+		StartOffset: kafka.FirstOffset,
+	})
+}
+
+func NewProducer(brokers string) *kafka.Writer {
+	return &kafka.Writer{
+		Addr:         kafka.TCP(strings.Split(brokers, ",")...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}
+}
@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func NewConnection(host string, port int, pass string) (*redis.Client, error) {
+	if pass == "" {
+		log.Println("WARNING -> connecting to cache without password")
+	}
+
+	cache := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", host, port),
+		Password: pass,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := cache.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to ping cache: %w", err)
+	}
+
+	log.Println("✅ connected to cache")
+	return cache, nil
+}
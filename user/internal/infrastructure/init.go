@@ -5,15 +5,22 @@ import (
 	"fmt"
 
 	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/redis/go-redis/v9"
 	"github.com/ritchieridanko/apotekly-api/user/config"
+	"github.com/ritchieridanko/apotekly-api/user/internal/infrastructure/broker"
+	"github.com/ritchieridanko/apotekly-api/user/internal/infrastructure/cache"
 	"github.com/ritchieridanko/apotekly-api/user/internal/infrastructure/database"
 	"github.com/ritchieridanko/apotekly-api/user/internal/infrastructure/storage"
 	"github.com/ritchieridanko/apotekly-api/user/internal/infrastructure/tracer"
+	"github.com/segmentio/kafka-go"
 )
 
 type Infrastructure struct {
 	db      *sql.DB
+	cache   *redis.Client
 	storage *cloudinary.Cloudinary
+	broker  *kafka.Reader
+	writer  *kafka.Writer
 	tracer  *tracer.Tracer
 }
 
@@ -28,27 +35,47 @@ func Initialize(cfg config.Config) (*Infrastructure, error) {
 		return nil, err
 	}
 
+	c, err := cache.NewConnection(cfg.Cache.Host, cfg.Cache.Port, cfg.Cache.Pass)
+	if err != nil {
+		return nil, err
+	}
+
 	s, err := storage.NewInstance(cfg.Storage.Bucket, cfg.Storage.APIKey, cfg.Storage.APISecret)
 	if err != nil {
 		return nil, err
 	}
 
+	b := broker.NewConsumer(cfg.Broker.Brokers, cfg.Broker.GroupID, cfg.Broker.Topics.AuthEvents)
+	w := broker.NewProducer(cfg.Broker.Brokers)
+
 	t, err := tracer.NewProvider(cfg.App.Name, cfg.Tracer.Endpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Infrastructure{db: db, storage: s, tracer: t}, nil
+	return &Infrastructure{db: db, cache: c, storage: s, broker: b, writer: w, tracer: t}, nil
 }
 
 func (i *Infrastructure) DB() *sql.DB {
 	return i.db
 }
 
+func (i *Infrastructure) Cache() *redis.Client {
+	return i.cache
+}
+
 func (i *Infrastructure) Storage() *cloudinary.Cloudinary {
 	return i.storage
 }
 
+func (i *Infrastructure) Broker() *kafka.Reader {
+	return i.broker
+}
+
+func (i *Infrastructure) Writer() *kafka.Writer {
+	return i.writer
+}
+
 func (i *Infrastructure) Tracer() *tracer.Tracer {
 	return i.tracer
 }
@@ -57,6 +84,15 @@ func (i *Infrastructure) Close() error {
 	if err := i.db.Close(); err != nil {
 		return fmt.Errorf("failed to close database connection: %w", err)
 	}
+	if err := i.cache.Close(); err != nil {
+		return fmt.Errorf("failed to close cache connection: %w", err)
+	}
+	if err := i.broker.Close(); err != nil {
+		return fmt.Errorf("failed to close broker connection: %w", err)
+	}
+	if err := i.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close broker writer: %w", err)
+	}
 
 	i.tracer.Cleanup()
 	return nil
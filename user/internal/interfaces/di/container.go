@@ -8,38 +8,54 @@ import (
 	"github.com/ritchieridanko/apotekly-api/user/internal/interfaces/http/router"
 	"github.com/ritchieridanko/apotekly-api/user/internal/interfaces/http/validator"
 	"github.com/ritchieridanko/apotekly-api/user/internal/repositories"
+	"github.com/ritchieridanko/apotekly-api/user/internal/service/broker"
 	"github.com/ritchieridanko/apotekly-api/user/internal/service/database"
+	"github.com/ritchieridanko/apotekly-api/user/internal/service/denylist"
+	"github.com/ritchieridanko/apotekly-api/user/internal/service/jwks"
 	"github.com/ritchieridanko/apotekly-api/user/internal/service/storage"
 	"github.com/ritchieridanko/apotekly-api/user/internal/usecases"
+	"github.com/ritchieridanko/apotekly-api/user/internal/workers"
 )
 
 type Container struct {
-	router *router.Router
+	router  *router.Router
+	workers []workers.Worker
 }
 
 func NewContainer(cfg *config.Config, infra *infrastructure.Infrastructure) *Container {
 	db := database.NewDatabase(infra.DB())
 	tx := database.NewTransactor(infra.DB())
 	storage := storage.NewStorage(infra.Storage())
+	jwks := jwks.NewJWKS(cfg.JWT.JWKSURL, cfg.JWT.CacheTTL)
+	denylist := denylist.NewDenylist(infra.Cache(), cfg.Denylist.LocalTTL, cfg.Denylist.LocalMaxEntries)
+	consumer := broker.NewConsumer(infra.Broker())
+	producer := broker.NewProducer(infra.Writer())
 
 	ur := repositories.NewUserRepository(db)
 	ar := repositories.NewAddressRepository(db)
 
-	uu := usecases.NewUserUsecase(ur, tx, storage)
+	uu := usecases.NewUserUsecase(ur, ar, tx, storage)
 	au := usecases.NewAddressUsecase(ar, tx)
+	eu := usecases.NewEventUsecase(uu)
 
 	v := validator.NewValidator()
 
 	uh := handlers.NewUserHandler(uu, v, int64(cfg.Image.MaxSizeBytes), cfg.Image.AllowedTypes)
 	ah := handlers.NewAddressHandler(au, v)
 
-	am := middlewares.NewAuthMiddleware(cfg.App.Name, cfg.JWT.Secret)
+	am := middlewares.NewAuthMiddleware(cfg.App.Name, jwks, denylist)
 
 	r := router.NewRouter(am, uh, ah, cfg.App.Name)
 
-	return &Container{router: r}
+	cw := workers.NewConsumerWorker(consumer, producer, eu, cfg)
+
+	return &Container{router: r, workers: []workers.Worker{cw}}
 }
 
 func (c *Container) Router() *router.Router {
 	return c.router
 }
+
+func (c *Container) Workers() []workers.Worker {
+	return c.workers
+}
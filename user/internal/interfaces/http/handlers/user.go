@@ -133,6 +133,28 @@ func (h *UserHandler) GetUser(ctx *gin.Context) {
 	utils.SetResponse(ctx, "User retrieved successfully", response, http.StatusOK)
 }
 
+func (h *UserHandler) GetUserByAuthID(ctx *gin.Context) {
+	ctxWithTracer, span := otel.Tracer(userErrorTracer).Start(ctx.Request.Context(), "GetUserByAuthID")
+	defer span.End()
+
+	authID, err := utils.ToInt64(ctx.Param("auth_id"))
+	if err != nil {
+		wErr := fmt.Errorf("failed to fetch user: %w", err)
+		ctx.Error(ce.NewError(span, ce.CodeInvalidParams, ce.MsgInvalidParams, wErr))
+		return
+	}
+
+	user, err := h.uu.GetUser(ctxWithTracer, authID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := h.userToResponse(*user)
+
+	utils.SetResponse(ctx, "User retrieved successfully", response, http.StatusOK)
+}
+
 func (h *UserHandler) UpdateUser(ctx *gin.Context) {
 	ctxWithTracer, span := otel.Tracer(userErrorTracer).Start(ctx.Request.Context(), "UpdateUser")
 	defer span.End()
@@ -4,24 +4,31 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ritchieridanko/apotekly-api/user/internal/service/denylist"
+	"github.com/ritchieridanko/apotekly-api/user/internal/service/jwks"
 	"github.com/ritchieridanko/apotekly-api/user/internal/shared/ce"
 	"github.com/ritchieridanko/apotekly-api/user/internal/shared/constants"
 	"github.com/ritchieridanko/apotekly-api/user/internal/shared/utils"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const authErrorTracer string = "middleware.auth"
 
 type AuthMiddleware struct {
-	appName   string
-	jwtSecret string
+	appName  string
+	jwks     jwks.JWKSService
+	denylist *denylist.Denylist
 }
 
-func NewAuthMiddleware(appName, jwtSecret string) *AuthMiddleware {
-	return &AuthMiddleware{appName, jwtSecret}
+func NewAuthMiddleware(appName string, jwks jwks.JWKSService, denylist *denylist.Denylist) *AuthMiddleware {
+	return &AuthMiddleware{appName, jwks, denylist}
 }
 
 func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
@@ -45,7 +52,7 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 			return
 		}
 
-		claim, err := utils.JWTTokenParse(authParts[1], m.jwtSecret)
+		claim, err := utils.JWTTokenParse(authParts[1], m.jwks.Keyfunc)
 		if err != nil {
 			wErr := fmt.Errorf("failed to authenticate: %w", err)
 			switch {
@@ -53,6 +60,8 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 				err = ce.NewError(span, ce.CodeAuthTokenExpired, ce.MsgUnauthenticated, wErr)
 			case errors.Is(err, ce.ErrTokenMalformed):
 				err = ce.NewError(span, ce.CodeAuthTokenMalformed, ce.MsgUnauthenticated, wErr)
+			case errors.Is(err, ce.ErrTokenSignature), errors.Is(err, jwks.ErrKeyNotFound):
+				err = ce.NewError(span, ce.CodeAuthTokenInvalid, ce.MsgUnauthenticated, wErr)
 			case errors.Is(err, ce.ErrInvalidTokenClaim):
 				err = ce.NewError(span, ce.CodeInvalidTokenClaim, ce.MsgUnauthenticated, wErr)
 			default:
@@ -71,9 +80,44 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 			return
 		}
 
+		isDenied, err := m.denylist.IsDenied(ctxWithTracer, claim.ID, claim.ExpiresAt.Time)
+		if err != nil {
+			// non-fatal: a denylist outage lets the token through
+			span.AddEvent(
+				"token denylist check failed",
+				trace.WithAttributes(attribute.String("error", err.Error())),
+			)
+		}
+		if isDenied {
+			wErr := fmt.Errorf("failed to authenticate: %w", ce.ErrTokenRevoked)
+			ctx.Error(ce.NewError(span, ce.CodeAuthTokenRevoked, ce.MsgUnauthenticated, wErr))
+			ctx.Abort()
+			return
+		}
+
+		if claim.SubjectType == constants.SubjectTypeService {
+			// scopes only, no role
+			span.SetAttributes(attribute.String("client_id", claim.Subject))
+			ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyClientID, claim.Subject)
+			ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyScopes, strings.Fields(claim.Scope))
+
+			ctx.Request = ctx.Request.WithContext(ctxWithTracer)
+			ctx.Next()
+			return
+		}
+
 		ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyAuthID, claim.AuthID)
 		ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyRoleID, claim.RoleID)
 		ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyIsVerified, claim.IsVerified)
+		if claim.Act != nil {
+			// an admin acting as the user
+			log.Printf(
+				"IMPERSONATION -> admin %d acting as auth %d: %s %s",
+				claim.Act.AuthID, claim.AuthID, ctx.Request.Method, ctx.Request.URL.Path,
+			)
+			span.SetAttributes(attribute.Int64("impersonator_id", claim.Act.AuthID))
+			ctxWithTracer = context.WithValue(ctxWithTracer, constants.CtxKeyImpersonatorID, claim.Act.AuthID)
+		}
 
 		ctx.Request = ctx.Request.WithContext(ctxWithTracer)
 		ctx.Next()
@@ -98,6 +142,24 @@ func (m *AuthMiddleware) AuthorizeRole() gin.HandlerFunc {
 	}
 }
 
+func (m *AuthMiddleware) AuthorizeScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "AuthorizeScope")
+		defer span.End()
+
+		value := ctxWithTracer.Value(constants.CtxKeyScopes)
+		scopes, ok := value.([]string)
+		if !ok || !slices.Contains(scopes, scope) {
+			wErr := fmt.Errorf("failed to authorize scope: %w", fmt.Errorf("scope %q missing", scope))
+			ctx.Error(ce.NewError(span, ce.CodeScopeUnauthorized, ce.MsgScopeUnauthorized, wErr))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
 func (m *AuthMiddleware) AuthorizeVerification() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		ctxWithTracer, span := otel.Tracer(authErrorTracer).Start(ctx.Request.Context(), "AuthorizeVerification")
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ritchieridanko/apotekly-api/user/internal/interfaces/http/handlers"
 	"github.com/ritchieridanko/apotekly-api/user/internal/interfaces/http/middlewares"
+	"github.com/ritchieridanko/apotekly-api/user/internal/shared/constants"
 )
 
 type userRoutes struct {
@@ -17,6 +18,7 @@ func newUserRoutes(h *handlers.UserHandler, auth *middlewares.AuthMiddleware) *u
 
 func (r *userRoutes) register(rg *gin.RouterGroup) {
 	rg.GET("/me", r.auth.Authenticate(), r.auth.AuthorizeRole(), r.h.GetUser)
+	rg.GET("/:auth_id", r.auth.Authenticate(), r.auth.AuthorizeScope(constants.ScopeUsersRead), r.h.GetUserByAuthID)
 	rg.POST("", r.auth.Authenticate(), r.auth.AuthorizeRole(), r.h.CreateUser)
 	rg.PATCH("/me", r.auth.Authenticate(), r.auth.AuthorizeRole(), r.h.UpdateUser)
 	rg.PATCH("/me/profile-picture", r.auth.Authenticate(), r.auth.AuthorizeRole(), r.h.ChangeProfilePicture)
@@ -19,6 +19,7 @@ type AddressRepository interface {
 	GetAll(ctx context.Context, authID int64) (addresses []entities.Address, err error)
 	Update(ctx context.Context, authID, addressID int64, data *entities.UpdateAddress) (address *entities.Address, err error)
 	Delete(ctx context.Context, authID, addressID int64) (err error)
+	DeleteAll(ctx context.Context, authID int64) (err error)
 	HasPrimary(ctx context.Context, authID int64) (exists bool, err error)
 	SetPrimary(ctx context.Context, authID, addressID int64) (address *entities.Address, err error)
 	UnsetPrimary(ctx context.Context, authID int64) (address *entities.Address, err error)
@@ -263,6 +264,23 @@ func (r *addressRepository) Delete(ctx context.Context, authID, addressID int64)
 	return nil
 }
 
+func (r *addressRepository) DeleteAll(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(addressErrorTracer).Start(ctx, "DeleteAll")
+	defer span.End()
+
+	query := "DELETE FROM addresses WHERE auth_id = $1"
+
+	if err := r.database.Execute(ctx, query, authID); err != nil {
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return nil
+		}
+		wErr := fmt.Errorf("failed to delete addresses: %w", err)
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
+
 func (r *addressRepository) HasPrimary(ctx context.Context, authID int64) (bool, error) {
 	ctx, span := otel.Tracer(addressErrorTracer).Start(ctx, "HasPrimary")
 	defer span.End()
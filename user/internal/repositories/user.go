@@ -22,6 +22,7 @@ type UserRepository interface {
 	Update(ctx context.Context, authID int64, data *entities.UpdateUser) (user *entities.User, err error)
 	UpdateProfilePicture(ctx context.Context, authID int64, profilePicture string) (user *entities.User, err error)
 	Exists(ctx context.Context, authID int64) (exists bool, err error)
+	Anonymize(ctx context.Context, authID int64) (err error)
 }
 
 type userRepository struct {
@@ -246,3 +247,28 @@ func (r *userRepository) Exists(ctx context.Context, authID int64) (bool, error)
 
 	return true, nil
 }
+
+func (r *userRepository) Anonymize(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(userErrorTracer).Start(ctx, "Anonymize")
+	defer span.End()
+
+	// the row is kept so the user id stays taken, only personal data goes
+	query := `
+		UPDATE users
+		SET
+			name = '', bio = NULL, sex = NULL, birthdate = NULL,
+			phone = NULL, profile_picture = NULL,
+			updated_at = NOW(), deleted_at = NOW()
+		WHERE auth_id = $1 AND deleted_at IS NULL
+	`
+
+	if err := r.database.Execute(ctx, query, authID); err != nil {
+		wErr := fmt.Errorf("failed to anonymize user: %w", err)
+		if errors.Is(err, ce.ErrDBAffectNoRows) {
+			return ce.NewError(span, ce.CodeUserNotFound, ce.MsgUserNotFound, wErr)
+		}
+		return ce.NewError(span, ce.CodeDBQueryExecution, ce.MsgInternalServer, wErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package denylist
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// written by the auth service on logout, session revocation and password changes
+const keyPrefix string = "dl"
+
+type Denylist struct {
+	cache *redis.Client
+
+	// in-process view of the denylist, so not every request costs a redis hit
+	localTTL        time.Duration
+	localMaxEntries int
+
+	mu    sync.Mutex
+	local map[string]entry
+}
+
+type entry struct {
+	isDenied  bool
+	expiresAt time.Time
+}
+
+func NewDenylist(cache *redis.Client, localTTL time.Duration, localMaxEntries int) *Denylist {
+	return &Denylist{
+		cache:           cache,
+		localTTL:        localTTL,
+		localMaxEntries: localMaxEntries,
+		local:           make(map[string]entry),
+	}
+}
+
+func (d *Denylist) IsDenied(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	if jti == "" {
+		// issued before tokens carried an id, these simply run out
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+
+	d.mu.Lock()
+	e, ok := d.local[jti]
+	d.mu.Unlock()
+
+	if ok && now.Before(e.expiresAt) {
+		return e.isDenied, nil
+	}
+
+	count, err := d.cache.Exists(ctx, fmt.Sprintf("%s:%s", keyPrefix, jti)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check token denylist: %w", err)
+	}
+	isDenied := count > 0
+
+	// a denial never expires before the token does, an allowance is only trusted briefly
+	e = entry{isDenied: isDenied, expiresAt: expiresAt}
+	if !isDenied && now.Add(d.localTTL).Before(expiresAt) {
+		e.expiresAt = now.Add(d.localTTL)
+	}
+	d.remember(jti, e, now)
+
+	return isDenied, nil
+}
+
+func (d *Denylist) remember(jti string, e entry, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.local) >= d.localMaxEntries {
+		for key, v := range d.local {
+			if !now.Before(v.expiresAt) {
+				delete(d.local, key)
+			}
+		}
+		if len(d.local) >= d.localMaxEntries {
+			// still full of live entries, start over rather than grow unbounded
+			d.local = make(map[string]entry)
+		}
+	}
+
+	d.local[jti] = e
+}
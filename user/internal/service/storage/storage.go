@@ -26,3 +26,14 @@ func (s *Storage) Upload(ctx context.Context, data *entities.UploadParams) (*upl
 
 	return s.storage.Upload.Upload(ctx, data.File, params)
 }
+
+func (s *Storage) Destroy(ctx context.Context, publicID string) error {
+	invalidate := true
+	params := uploader.DestroyParams{
+		PublicID:   publicID,
+		Invalidate: &invalidate,
+	}
+
+	_, err := s.storage.Upload.Destroy(ctx, params)
+	return err
+}
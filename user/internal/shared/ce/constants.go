@@ -16,20 +16,24 @@ const (
 	CodeAuthNotFound         errCode = "AUTH_NOT_FOUND_ERROR"
 	CodeAuthNotVerified      errCode = "AUTH_NOT_VERIFIED_ERROR"
 	CodeAuthTokenExpired     errCode = "AUTH_TOKEN_EXPIRED_ERROR"
+	CodeAuthTokenInvalid     errCode = "AUTH_TOKEN_INVALID_ERROR"
 	CodeAuthTokenMalformed   errCode = "AUTH_TOKEN_MALFORMED_ERROR"
 	CodeAuthTokenParsing     errCode = "AUTH_TOKEN_PARSING_ERROR"
+	CodeAuthTokenRevoked     errCode = "AUTH_TOKEN_REVOKED_ERROR"
 	CodeAuthUnauthenticated  errCode = "AUTH_UNAUTHENTICATED_ERROR"
 	CodeContextValueNotFound errCode = "CONTEXT_VALUE_NOT_FOUND_ERROR"
 	CodeDBDuplicateData      errCode = "DB_DUPLICATE_DATA_ERROR"
 	CodeDBQueryExecution     errCode = "DB_QUERY_EXECUTION_ERROR"
 	CodeDBTransaction        errCode = "DB_TRANSACTION_ERROR"
 	CodeFileBuffer           errCode = "FILE_BUFFER_ERROR"
+	CodeFileDestroyFailed    errCode = "FILE_DESTROY_FAILED_ERROR"
 	CodeFileUploadFailed     errCode = "FILE_UPLOAD_FAILED_ERROR"
 	CodeInvalidParams        errCode = "INVALID_PARAMS_ERROR"
 	CodeInvalidPayload       errCode = "INVALID_PAYLOAD_ERROR"
 	CodeInvalidTokenClaim    errCode = "INVALID_TOKEN_CLAIM_ERROR"
 	CodeRequestFile          errCode = "REQUEST_FILE_ERROR"
 	CodeRoleUnauthorized     errCode = "ROLE_UNAUTHORIZED_ERROR"
+	CodeScopeUnauthorized    errCode = "SCOPE_UNAUTHORIZED_ERROR"
 	CodeUserNotFound         errCode = "USER_NOT_FOUND_ERROR"
 )
 
@@ -41,6 +45,7 @@ const (
 	MsgInvalidParams      string = "Invalid params"
 	MsgInvalidPayload     string = "Invalid payload"
 	MsgNoFieldsToUpdate   string = "No fields to update"
+	MsgScopeUnauthorized  string = "Insufficient scope"
 	MsgUnauthenticated    string = "Unauthenticated"
 	MsgUserNotFound       string = "User not found"
 )
@@ -55,4 +60,6 @@ var (
 	ErrNoFieldsProvided  error = errors.New("no fields provided")
 	ErrTokenExpired      error = jwt.ErrTokenExpired
 	ErrTokenMalformed    error = jwt.ErrTokenMalformed
+	ErrTokenRevoked      error = errors.New("token revoked")
+	ErrTokenSignature    error = jwt.ErrTokenSignatureInvalid
 )
@@ -42,12 +42,14 @@ func (e *Error) HTTPStatus() int {
 		CodeAuthAudienceNotFound,
 		CodeAuthNotFound,
 		CodeAuthTokenExpired,
+		CodeAuthTokenInvalid,
 		CodeAuthTokenMalformed,
+		CodeAuthTokenRevoked,
 		CodeAuthUnauthenticated,
 		CodeInvalidTokenClaim,
 		CodeRoleUnauthorized:
 		return http.StatusUnauthorized
-	case CodeAuthNotVerified:
+	case CodeAuthNotVerified, CodeScopeUnauthorized:
 		return http.StatusForbidden
 	case CodeAddressNotFound, CodeUserNotFound:
 		return http.StatusNotFound
@@ -59,6 +61,7 @@ func (e *Error) HTTPStatus() int {
 		CodeDBQueryExecution,
 		CodeDBTransaction,
 		CodeFileBuffer,
+		CodeFileDestroyFailed,
 		CodeFileUploadFailed,
 		CodeRequestFile:
 		return http.StatusInternalServerError
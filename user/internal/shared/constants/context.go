@@ -3,7 +3,10 @@ package constants
 type ctxKey string
 
 const (
-	CtxKeyAuthID     ctxKey = "auth-id"
-	CtxKeyRoleID     ctxKey = "role-id"
-	CtxKeyIsVerified ctxKey = "is-verified"
+	CtxKeyAuthID         ctxKey = "auth-id"
+	CtxKeyRoleID         ctxKey = "role-id"
+	CtxKeyIsVerified     ctxKey = "is-verified"
+	CtxKeyImpersonatorID ctxKey = "impersonator-id"
+	CtxKeyClientID       ctxKey = "client-id"
+	CtxKeyScopes         ctxKey = "scopes"
 )
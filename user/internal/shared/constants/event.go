@@ -0,0 +1,14 @@
+package constants
+
+const (
+	EventTypeAccountDeleted string = "ACCOUNT_DELETED"
+)
+
+// headers added to messages moved to the dead-letter topic
+const (
+	HeaderDeadLetterAttempts  string = "dlq_attempts"
+	HeaderDeadLetterError     string = "dlq_error"
+	HeaderDeadLetterOffset    string = "dlq_source_offset"
+	HeaderDeadLetterPartition string = "dlq_source_partition"
+	HeaderDeadLetterTopic     string = "dlq_source_topic"
+)
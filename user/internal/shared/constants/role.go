@@ -2,4 +2,5 @@ package constants
 
 const (
 	RoleCustomer int16 = 1
+	RoleAdmin    int16 = 2
 )
@@ -0,0 +1,6 @@
+package constants
+
+// issued by the auth service to service accounts, never to users
+const SubjectTypeService string = "service"
+
+const ScopeUsersRead string = "users:read"
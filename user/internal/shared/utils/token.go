@@ -8,13 +8,12 @@ import (
 	"github.com/ritchieridanko/apotekly-api/user/internal/shared/ce"
 )
 
-func JWTTokenParse(tokenString, jwtSecret string) (*entities.Claim, error) {
+func JWTTokenParse(tokenString string, keyfunc jwt.Keyfunc) (*entities.Claim, error) {
 	token, err := jwt.ParseWithClaims(
 		tokenString,
 		&entities.Claim{},
-		func(t *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		},
+		keyfunc,
+		jwt.WithValidMethods([]string{"EdDSA", "RS256"}),
 	)
 	if err != nil {
 		return nil, err
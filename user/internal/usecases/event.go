@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ritchieridanko/apotekly-api/user/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/user/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/user/pkg/events"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/protobuf/proto"
+)
+
+const eventErrorTracer string = "usecase.event"
+
+type EventUsecase interface {
+	Process(ctx context.Context, message *entities.Message) (err error)
+}
+
+type eventUsecase struct {
+	uu UserUsecase
+}
+
+func NewEventUsecase(uu UserUsecase) EventUsecase {
+	return &eventUsecase{uu}
+}
+
+func (u *eventUsecase) Process(ctx context.Context, message *entities.Message) error {
+	ctx, span := otel.Tracer(eventErrorTracer).Start(ctx, "Process")
+	defer span.End()
+
+	var event events.Event
+	if err := proto.Unmarshal(message.Value, &event); err != nil {
+		// retrying a malformed message never helps, so it is skipped
+		wErr := fmt.Errorf("failed to decode event envelope: %w", err)
+		log.Println("WARNING ->", wErr.Error())
+		return nil
+	}
+	span.SetAttributes(
+		attribute.String("event.id", event.EventId),
+		attribute.String("event.type", event.EventType),
+	)
+
+	switch event.EventType {
+	case constants.EventTypeAccountDeleted:
+		var data events.AccountDeleted
+		if err := proto.Unmarshal(event.Data, &data); err != nil {
+			wErr := fmt.Errorf("failed to decode event %s: %w", event.EventType, err)
+			log.Println("WARNING ->", wErr.Error())
+			return nil
+		}
+		return u.uu.EraseUser(ctx, data.AuthId)
+	default:
+		// only events touching data held here are of interest
+		return nil
+	}
+}
@@ -23,20 +23,23 @@ type UserUsecase interface {
 	GetUser(ctx context.Context, authID int64) (user *entities.User, err error)
 	UpdateUser(ctx context.Context, authID int64, data *entities.UpdateUser) (updatedUser *entities.User, err error)
 	ChangeProfilePicture(ctx context.Context, authID int64, image multipart.File) (user *entities.User, err error)
+	EraseUser(ctx context.Context, authID int64) (err error)
 }
 
 type userUsecase struct {
 	ur         repositories.UserRepository
+	ar         repositories.AddressRepository
 	transactor *database.Transactor
 	storage    *storage.Storage
 }
 
 func NewUserUsecase(
 	ur repositories.UserRepository,
+	ar repositories.AddressRepository,
 	transactor *database.Transactor,
 	storage *storage.Storage,
 ) UserUsecase {
-	return &userUsecase{ur, transactor, storage}
+	return &userUsecase{ur, ar, transactor, storage}
 }
 
 func (u *userUsecase) CreateUser(ctx context.Context, authID int64, data *entities.CreateUser, image multipart.File) (*entities.User, error) {
@@ -114,6 +117,42 @@ func (u *userUsecase) ChangeProfilePicture(ctx context.Context, authID int64, im
 	return u.ur.UpdateProfilePicture(ctx, authID, profilePicture)
 }
 
+func (u *userUsecase) EraseUser(ctx context.Context, authID int64) error {
+	ctx, span := otel.Tracer(userErrorTracer).Start(ctx, "EraseUser")
+	defer span.End()
+
+	return u.transactor.WithTx(ctx, func(ctx context.Context) error {
+		if err := u.ar.DeleteAll(ctx, authID); err != nil {
+			return err
+		}
+
+		user, err := u.ur.GetByAuthID(ctx, authID)
+		if err != nil {
+			var e *ce.Error
+			if errors.As(err, &e) && e.Code == ce.CodeUserNotFound {
+				// never set up a profile, or already erased on an earlier delivery
+				return nil
+			}
+			return err
+		}
+
+		if err := u.ur.Anonymize(ctx, authID); err != nil {
+			return err
+		}
+
+		// removed before commit so a failure is retried with the row still there
+		if user.ProfilePicture != nil {
+			publicID := fmt.Sprintf("users/profile_pictures/%s", user.ID.String())
+			if err := u.storage.Destroy(ctx, publicID); err != nil {
+				wErr := fmt.Errorf("failed to destroy image: %w", err)
+				return ce.NewError(span, ce.CodeFileDestroyFailed, ce.MsgInternalServer, wErr)
+			}
+		}
+
+		return nil
+	})
+}
+
 func (u *userUsecase) uploadImage(ctx context.Context, image multipart.File, publicID, prefix, folder string, overwrite bool) (string, error) {
 	ctx, span := otel.Tracer(userErrorTracer).Start(ctx, "uploadImage")
 	defer span.End()
@@ -0,0 +1,124 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/ritchieridanko/apotekly-api/user/config"
+	"github.com/ritchieridanko/apotekly-api/user/internal/entities"
+	"github.com/ritchieridanko/apotekly-api/user/internal/service/broker"
+	"github.com/ritchieridanko/apotekly-api/user/internal/shared/constants"
+	"github.com/ritchieridanko/apotekly-api/user/internal/usecases"
+)
+
+// a message that cannot even be moved aside is tried again after a pause
+const consumerRetryInterval time.Duration = 5 * time.Second
+
+type ConsumerWorker struct {
+	consumer *broker.Consumer
+	producer *broker.Producer
+	eu       usecases.EventUsecase
+	cfg      *config.Config
+}
+
+func NewConsumerWorker(consumer *broker.Consumer, producer *broker.Producer, eu usecases.EventUsecase, cfg *config.Config) *ConsumerWorker {
+	return &ConsumerWorker{consumer, producer, eu, cfg}
+}
+
+func (w *ConsumerWorker) Run(ctx context.Context) {
+	for {
+		message, err := w.consumer.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Println("WARNING ->", err.Error())
+			if !w.wait(ctx, consumerRetryInterval) {
+				return
+			}
+			continue
+		}
+
+		// the offset only moves on once the message is handled or dead-lettered,
+		// so no erasure is skipped within the partition
+		if !w.handle(ctx, message) {
+			return
+		}
+
+		if err := w.consumer.Commit(ctx, message); err != nil {
+			// non-fatal: the message comes again and erasing twice changes nothing
+			log.Println("WARNING ->", err.Error())
+		}
+	}
+}
+
+func (w *ConsumerWorker) handle(ctx context.Context, message *entities.Message) bool {
+	attempts := 0
+	for {
+		attempts++
+
+		err := w.eu.Process(ctx, message)
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		log.Println("WARNING ->", err.Error())
+
+		if attempts >= w.cfg.Consumer.MaxAttempts {
+			return w.deadLetter(ctx, message, attempts, err)
+		}
+		if !w.wait(ctx, w.retryDelay(attempts)) {
+			return false
+		}
+	}
+}
+
+func (w *ConsumerWorker) deadLetter(ctx context.Context, message *entities.Message, attempts int, reason error) bool {
+	// the original message is kept as is, so it can be replayed onto its topic
+	headers := maps.Clone(message.Headers)
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	headers[constants.HeaderDeadLetterAttempts] = strconv.Itoa(attempts)
+	headers[constants.HeaderDeadLetterError] = reason.Error()
+	headers[constants.HeaderDeadLetterTopic] = message.Topic
+	headers[constants.HeaderDeadLetterPartition] = strconv.Itoa(message.Partition)
+	headers[constants.HeaderDeadLetterOffset] = strconv.FormatInt(message.Offset, 10)
+
+	for {
+		err := w.producer.PublishEncoded(ctx, w.cfg.Broker.Topics.DeadLetter, message.Key, message.Value, headers)
+		if err == nil {
+			log.Printf("WARNING -> message dead-lettered after %d attempts: %s\n", attempts, reason.Error())
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		log.Println("WARNING ->", err.Error())
+		if !w.wait(ctx, consumerRetryInterval) {
+			return false
+		}
+	}
+}
+
+func (w *ConsumerWorker) retryDelay(attempts int) time.Duration {
+	delay := w.cfg.Consumer.BaseDelay
+	for i := 1; i < attempts && delay < w.cfg.Consumer.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, w.cfg.Consumer.MaxDelay)
+}
+
+func (w *ConsumerWorker) wait(ctx context.Context, delay time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(delay):
+		return true
+	}
+}